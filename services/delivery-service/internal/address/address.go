@@ -0,0 +1,113 @@
+package address
+
+import (
+	"delivery-service/internal/utils"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Address struct to describe a postal address, shared by every end of an order.
+type Address struct {
+	Street      string `json:"street" validate:"required,lte=200"`
+	Number      string `json:"number" validate:"omitempty,lte=20"`
+	District    string `json:"district" validate:"omitempty,lte=100"`
+	City        string `json:"city" validate:"required,lte=100"`
+	Region      string `json:"region" validate:"omitempty,lte=100"`
+	CountryCode string `json:"countryCode" validate:"required,len=2,alpha"`
+	PostalCode  string `json:"postalCode" validate:"omitempty,lte=20"`
+}
+
+// Format of the postal codes of a country.
+// The pattern applies to the compact code, without spaces or hyphens,
+// and the layouts write it back by length, each '#' being a character of the compact code.
+type postalCodeFormat struct {
+	pattern *regexp.Regexp
+	layouts map[int]string
+}
+
+// Postal code formats of the countries we know, other countries accept any code.
+var postalCodeFormats = map[string]postalCodeFormat{
+	"AR": {regexp.MustCompile(`^([A-Z]\d{4}[A-Z]{3}|\d{4})$`), nil},
+	"BO": {regexp.MustCompile(`^\d{4}$`), nil},
+	"BR": {regexp.MustCompile(`^\d{8}$`), map[int]string{8: "#####-###"}},
+	"CA": {regexp.MustCompile(`^[A-Z]\d[A-Z]\d[A-Z]\d$`), map[int]string{6: "### ###"}},
+	"CL": {regexp.MustCompile(`^\d{7}$`), nil},
+	"CO": {regexp.MustCompile(`^\d{6}$`), nil},
+	"EC": {regexp.MustCompile(`^\d{6}$`), nil},
+	"ES": {regexp.MustCompile(`^\d{5}$`), nil},
+	"MX": {regexp.MustCompile(`^\d{5}$`), nil},
+	"PE": {regexp.MustCompile(`^\d{5}$`), nil},
+	"US": {regexp.MustCompile(`^\d{5}(\d{4})?$`), map[int]string{9: "#####-####"}},
+	"UY": {regexp.MustCompile(`^\d{5}$`), nil},
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// Normalize cleans the address in place and checks the postal code against the format of its country.
+func Normalize(a *Address) error {
+	a.Street = cleanText(a.Street)
+	a.Number = cleanText(a.Number)
+	a.District = cleanText(a.District)
+	a.City = cleanText(a.City)
+	a.Region = cleanText(a.Region)
+
+	a.CountryCode = strings.ToUpper(strings.TrimSpace(a.CountryCode))
+	if err := utils.NewValidator().Var(a.CountryCode, "iso3166_1_alpha2"); err != nil {
+		return fmt.Errorf("the country code '%v' is not an ISO-3166 code", a.CountryCode)
+	}
+
+	compact := strings.NewReplacer(" ", "", "-", "").Replace(strings.ToUpper(strings.TrimSpace(a.PostalCode)))
+
+	// The postal code is optional, and so is any country without a known format.
+	format, ok := postalCodeFormats[a.CountryCode]
+	if !ok || compact == "" {
+		a.PostalCode = compact
+		return nil
+	}
+	if !format.pattern.MatchString(compact) {
+		return fmt.Errorf("the postal code '%v' is not valid for %v", a.PostalCode, a.CountryCode)
+	}
+
+	a.PostalCode = compact
+	if layout, ok := format.layouts[len(compact)]; ok {
+		a.PostalCode = applyLayout(layout, compact)
+	}
+
+	return nil
+}
+
+// Format writes the address in a single line.
+func (a *Address) Format() string {
+	street := strings.TrimSpace(a.Street + " " + a.Number)
+
+	var parts []string
+	for _, part := range []string{street, a.District, a.City, a.Region, a.PostalCode, a.CountryCode} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Trims the text and leaves a single space between words.
+func cleanText(text string) string {
+	return whitespace.ReplaceAllString(strings.TrimSpace(text), " ")
+}
+
+// Writes the compact code over the '#' of the layout.
+func applyLayout(layout string, compact string) string {
+	var builder strings.Builder
+	next := 0
+	for _, r := range layout {
+		if r == '#' {
+			builder.WriteByte(compact[next])
+			next++
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
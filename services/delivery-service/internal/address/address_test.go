@@ -0,0 +1,133 @@
+package address
+
+import (
+	"testing"
+)
+
+func TestNormalizeAcceptedForms(t *testing.T) {
+	tests := []struct {
+		name       string
+		address    Address
+		normalized Address
+	}{
+		{
+			name:       "clean address stays as is",
+			address:    Address{Street: "Av. Providencia", Number: "1208", District: "Providencia", City: "Santiago", Region: "RM", CountryCode: "CL", PostalCode: "7500000"},
+			normalized: Address{Street: "Av. Providencia", Number: "1208", District: "Providencia", City: "Santiago", Region: "RM", CountryCode: "CL", PostalCode: "7500000"},
+		},
+		{
+			name:       "text is trimmed with a single space between words",
+			address:    Address{Street: "  Av.   Providencia ", Number: " 1208 ", District: "\tLas  Condes\n", City: " Santiago  de Chile ", Region: " RM ", CountryCode: "CL"},
+			normalized: Address{Street: "Av. Providencia", Number: "1208", District: "Las Condes", City: "Santiago de Chile", Region: "RM", CountryCode: "CL"},
+		},
+		{
+			name:       "country code is upper cased",
+			address:    Address{Street: "Calle Larga", City: "Valparaiso", CountryCode: " cl ", PostalCode: "2340000"},
+			normalized: Address{Street: "Calle Larga", City: "Valparaiso", CountryCode: "CL", PostalCode: "2340000"},
+		},
+		{
+			name:       "postal code is optional for a known country",
+			address:    Address{Street: "Calle Larga", City: "Valparaiso", CountryCode: "CL", PostalCode: "  "},
+			normalized: Address{Street: "Calle Larga", City: "Valparaiso", CountryCode: "CL"},
+		},
+		{
+			name:       "spaces and hyphens are dropped from compact codes",
+			address:    Address{Street: "Calle Larga", City: "Valparaiso", CountryCode: "CL", PostalCode: "234-0000"},
+			normalized: Address{Street: "Calle Larga", City: "Valparaiso", CountryCode: "CL", PostalCode: "2340000"},
+		},
+		{
+			name:       "compact brazilian code is written with its hyphen",
+			address:    Address{Street: "Avenida Paulista", Number: "1578", City: "Sao Paulo", CountryCode: "BR", PostalCode: "01310200"},
+			normalized: Address{Street: "Avenida Paulista", Number: "1578", City: "Sao Paulo", CountryCode: "BR", PostalCode: "01310-200"},
+		},
+		{
+			name:       "canadian code is upper cased and split in two",
+			address:    Address{Street: "Wellington St", City: "Ottawa", CountryCode: "CA", PostalCode: "k1a0b1"},
+			normalized: Address{Street: "Wellington St", City: "Ottawa", CountryCode: "CA", PostalCode: "K1A 0B1"},
+		},
+		{
+			name:       "short american code keeps its five digits",
+			address:    Address{Street: "Main St", City: "Springfield", CountryCode: "US", PostalCode: "62701"},
+			normalized: Address{Street: "Main St", City: "Springfield", CountryCode: "US", PostalCode: "62701"},
+		},
+		{
+			name:       "long american code is written with its hyphen",
+			address:    Address{Street: "Main St", City: "Springfield", CountryCode: "US", PostalCode: "62701 1234"},
+			normalized: Address{Street: "Main St", City: "Springfield", CountryCode: "US", PostalCode: "62701-1234"},
+		},
+		{
+			name:       "both argentinian forms are accepted",
+			address:    Address{Street: "Av. Corrientes", City: "Buenos Aires", CountryCode: "AR", PostalCode: "c1043aaz"},
+			normalized: Address{Street: "Av. Corrientes", City: "Buenos Aires", CountryCode: "AR", PostalCode: "C1043AAZ"},
+		},
+		{
+			name:       "country without a known format accepts any code",
+			address:    Address{Street: "Rue de Rivoli", City: "Paris", CountryCode: "FR", PostalCode: "75 001"},
+			normalized: Address{Street: "Rue de Rivoli", City: "Paris", CountryCode: "FR", PostalCode: "75001"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := test.address
+			if err := Normalize(&a); err != nil {
+				t.Fatalf("normalize: %v", err)
+			}
+			if a != test.normalized {
+				t.Errorf("normalized %+v, expected %+v", a, test.normalized)
+			}
+		})
+	}
+}
+
+func TestNormalizeRejectedForms(t *testing.T) {
+	tests := []struct {
+		name    string
+		address Address
+		message string
+	}{
+		{
+			name:    "unknown country code",
+			address: Address{Street: "Calle Larga", City: "Valparaiso", CountryCode: "XX"},
+			message: "the country code 'XX' is not an ISO-3166 code",
+		},
+		{
+			name:    "country name instead of its code",
+			address: Address{Street: "Calle Larga", City: "Valparaiso", CountryCode: "Chile"},
+			message: "the country code 'CHILE' is not an ISO-3166 code",
+		},
+		{
+			name:    "chilean code too short",
+			address: Address{Street: "Calle Larga", City: "Valparaiso", CountryCode: "CL", PostalCode: "234000"},
+			message: "the postal code '234000' is not valid for CL",
+		},
+		{
+			name:    "letters in a numeric code",
+			address: Address{Street: "Gran Via", City: "Madrid", CountryCode: "ES", PostalCode: "28O13"},
+			message: "the postal code '28O13' is not valid for ES",
+		},
+		{
+			name:    "american code with a partial extension",
+			address: Address{Street: "Main St", City: "Springfield", CountryCode: "US", PostalCode: "62701-12"},
+			message: "the postal code '62701-12' is not valid for US",
+		},
+		{
+			name:    "canadian code out of order",
+			address: Address{Street: "Wellington St", City: "Ottawa", CountryCode: "CA", PostalCode: "1KA 0B1"},
+			message: "the postal code '1KA 0B1' is not valid for CA",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := test.address
+			err := Normalize(&a)
+			if err == nil {
+				t.Fatalf("normalized %+v, expected an error", a)
+			}
+			if err.Error() != test.message {
+				t.Errorf("error %q, expected %q", err.Error(), test.message)
+			}
+		})
+	}
+}
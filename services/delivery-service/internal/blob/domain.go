@@ -0,0 +1,14 @@
+package blob
+
+import (
+	"context"
+	"io"
+)
+
+// Our blob stores will implement these methods.
+// Keys are slash separated paths, 'Get' returns null when the key does not exist.
+type Store interface {
+	Put(ctx context.Context, key string, content io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
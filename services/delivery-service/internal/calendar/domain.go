@@ -0,0 +1,22 @@
+package calendar
+
+import (
+	"context"
+	"time"
+)
+
+// Calendar struct to describe when the operation of a country works.
+// Opening hours are minutes since midnight in the time zone of the country, closed days have none.
+type Calendar struct {
+	Country  string
+	Location *time.Location
+	Hours    map[time.Weekday][2]int
+	Holidays map[string]bool
+}
+
+// Our repository will implement these methods.
+// 'GetCalendar' returns null when the country has no calendar.
+type CalendarRepository interface {
+	GetCalendar(ctx context.Context, country string) (*Calendar, error)
+	IsConfigured() bool
+}
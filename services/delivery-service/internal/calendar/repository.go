@@ -0,0 +1,147 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// Days of the week as written in the calendars file.
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Represents that we will read the calendars from a JSON file.
+type fileRepository struct {
+	calendars map[string]*Calendar
+}
+
+// Create a new repository with the calendars of a JSON file, an empty path configures no calendars.
+func NewCalendarRepository(path string) (CalendarRepository, error) {
+	if path == "" {
+		return &fileRepository{}, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars, err := parseCalendars(content)
+	if err != nil {
+		return nil, fmt.Errorf("calendars file '%v' is not valid: %v", path, err)
+	}
+
+	return &fileRepository{calendars: calendars}, nil
+}
+
+// JSON structure of a calendar in the file.
+// Hours are "HH:MM-HH:MM" per lowercase English weekday, holidays are "YYYY-MM-DD".
+type calendarFile struct {
+	Country  string            `json:"country"`
+	TimeZone string            `json:"timezone"`
+	Hours    map[string]string `json:"hours"`
+	Holidays []string          `json:"holidays"`
+}
+
+// Reads the calendars of the file, keyed by their country.
+func parseCalendars(content []byte) (map[string]*Calendar, error) {
+	var files []calendarFile
+	if err := json.Unmarshal(content, &files); err != nil {
+		return nil, err
+	}
+
+	calendars := make(map[string]*Calendar, len(files))
+	for i, f := range files {
+		if f.Country == "" {
+			return nil, fmt.Errorf("calendar %d needs a 'country'", i)
+		}
+
+		location, err := time.LoadLocation(f.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("calendar '%v' has an unknown time zone: %v", f.Country, err)
+		}
+
+		calendar := &Calendar{
+			Country:  strings.ToUpper(f.Country),
+			Location: location,
+			Holidays: make(map[string]bool),
+		}
+
+		calendar.Hours, err = ParseWeekHours(f.Hours)
+		if err != nil {
+			return nil, fmt.Errorf("calendar '%v' %v", f.Country, err)
+		}
+
+		for _, holiday := range f.Holidays {
+			if _, err := time.Parse("2006-01-02", holiday); err != nil {
+				return nil, fmt.Errorf("calendar '%v' has an invalid holiday '%v'", f.Country, holiday)
+			}
+			calendar.Holidays[holiday] = true
+		}
+
+		calendars[calendar.Country] = calendar
+	}
+
+	return calendars, nil
+}
+
+// ParseWeekHours reads the opening hours of a week, "HH:MM-HH:MM" per lowercase English weekday.
+// Days left out are closed.
+func ParseWeekHours(week map[string]string) (map[time.Weekday][2]int, error) {
+	hours := make(map[time.Weekday][2]int, len(week))
+	for day, dayHours := range week {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return nil, fmt.Errorf("has an unknown day '%v'", day)
+		}
+		opening, err := parseHours(dayHours)
+		if err != nil {
+			return nil, fmt.Errorf("has invalid hours on %v: %v", day, err)
+		}
+		hours[weekday] = opening
+	}
+
+	return hours, nil
+}
+
+// Reads "HH:MM-HH:MM" as minutes since midnight.
+func parseHours(hours string) ([2]int, error) {
+	parts := strings.SplitN(hours, "-", 2)
+	if len(parts) != 2 {
+		return [2]int{}, fmt.Errorf("expected HH:MM-HH:MM")
+	}
+
+	var opening [2]int
+	for i, part := range parts {
+		clock, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return [2]int{}, err
+		}
+		opening[i] = clock.Hour()*60 + clock.Minute()
+	}
+	if opening[1] <= opening[0] {
+		return [2]int{}, fmt.Errorf("closing must be after opening")
+	}
+
+	return opening, nil
+}
+
+// Gets the calendar of a country.
+func (r *fileRepository) GetCalendar(ctx context.Context, country string) (*Calendar, error) {
+	return r.calendars[strings.ToUpper(country)], nil
+}
+
+// Whether any calendar was loaded.
+func (r *fileRepository) IsConfigured() bool {
+	return len(r.calendars) > 0
+}
@@ -0,0 +1,34 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Checks that a window fits inside the opening hours of a single working day of the calendar.
+func (c *Calendar) CheckWindow(from time.Time, to time.Time) error {
+	from = from.In(c.Location)
+	to = to.In(c.Location)
+
+	day := from.Format("2006-01-02")
+	if c.Holidays[day] {
+		return fmt.Errorf("%s is a holiday in %s", day, c.Country)
+	}
+	if to.Format("2006-01-02") != day {
+		return fmt.Errorf("the window must start and end on the same day")
+	}
+
+	opening, ok := c.Hours[from.Weekday()]
+	if !ok {
+		return fmt.Errorf("there is no operation on %s in %s", strings.ToLower(from.Weekday().String()), c.Country)
+	}
+
+	start := from.Hour()*60 + from.Minute()
+	end := to.Hour()*60 + to.Minute()
+	if start < opening[0] || end > opening[1] {
+		return fmt.Errorf("the window must be between %02d:%02d and %02d:%02d in %s", opening[0]/60, opening[0]%60, opening[1]/60, opening[1]%60, c.Country)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package contact
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/internal/address"
+	"time"
+)
+
+// Contact struct to describe a person saved in the address book of a user.
+type Contact struct {
+	ID          int       `db:"id"`
+	UserID      int       `db:"user_id"`
+	Alias       string    `db:"alias"`
+	DocumentID  string    `db:"document_id"`
+	FullName    string    `db:"full_name"`
+	Phone       string    `db:"phone"`
+	Email       string    `db:"email"`
+	Application string    `db:"application"`
+	CreatedUser string    `db:"created_user"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedUser string    `db:"updated_user"`
+	UpdatedAt   time.Time `db:"updated_at"`
+	Status      string    `db:"status"`
+}
+
+// ContactInsert struct to describe save a new contact.
+type ContactInsert struct {
+	Alias       string `json:"alias" validate:"required,lte=100"`
+	DocumentID  string `json:"documentId" validate:"required,lte=200"`
+	FullName    string `json:"fullName" validate:"required,lte=200"`
+	Phone       string `json:"phone" validate:"required,lte=200"`
+	Email       string `json:"email" validate:"required,lte=200,email"`
+	UserID      int    `json:"-"`
+	Application string `json:"-"`
+	CreatedUser string `json:"createdUser" validate:"required,lte=200"`
+}
+
+// ContactUpdate struct to describe update contact.
+type ContactUpdate struct {
+	Alias       string `json:"alias" validate:"required,lte=100"`
+	DocumentID  string `json:"documentId" validate:"required,lte=200"`
+	FullName    string `json:"fullName" validate:"required,lte=200"`
+	Phone       string `json:"phone" validate:"required,lte=200"`
+	Email       string `json:"email" validate:"required,lte=200,email"`
+	UserID      int    `json:"-"`
+	UpdatedUser string `json:"updatedUser" validate:"required,lte=200"`
+}
+
+// ContactDelete struct to describe delete contact or saved address.
+type ContactDelete struct {
+	UserID      int    `json:"-"`
+	UpdatedUser string `json:"updatedUser" validate:"required,lte=200"`
+}
+
+type ContactOut struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"userId"`
+	Alias       string    `json:"alias"`
+	DocumentID  string    `json:"documentId"`
+	FullName    string    `json:"fullName"`
+	Phone       string    `json:"phone"`
+	Email       string    `json:"email"`
+	Application string    `json:"application"`
+	CreatedUser string    `json:"created_user"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedUser string    `json:"updated_user"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Status      string    `json:"status"`
+}
+
+// SavedAddress struct to describe a place saved in the address book of a user.
+type SavedAddress struct {
+	ID          int       `db:"id"`
+	UserID      int       `db:"user_id"`
+	Alias       string    `db:"alias"`
+	Street      string    `db:"street"`
+	Number      string    `db:"number"`
+	District    string    `db:"district"`
+	City        string    `db:"city"`
+	Region      string    `db:"region"`
+	CountryCode string    `db:"country_code"`
+	PostalCode  string    `db:"postal_code"`
+	Reference   string    `db:"reference"`
+	Lat         *float64  `db:"lat"`
+	Lng         *float64  `db:"lng"`
+	Application string    `db:"application"`
+	CreatedUser string    `db:"created_user"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedUser string    `db:"updated_user"`
+	UpdatedAt   time.Time `db:"updated_at"`
+	Status      string    `db:"status"`
+}
+
+// SavedAddressInsert struct to describe save a new address, the coordinates are optional.
+type SavedAddressInsert struct {
+	Alias       string           `json:"alias" validate:"required,lte=100"`
+	Address     *address.Address `json:"address" validate:"required"`
+	Reference   string           `json:"reference" validate:"required,lte=200"`
+	Lat         *float64         `json:"lat" validate:"required_with=Lng,omitempty,gte=-90,lte=90"`
+	Lng         *float64         `json:"lng" validate:"required_with=Lat,omitempty,gte=-180,lte=180"`
+	UserID      int              `json:"-"`
+	Application string           `json:"-"`
+	CreatedUser string           `json:"createdUser" validate:"required,lte=200"`
+}
+
+// SavedAddressUpdate struct to describe update saved address.
+type SavedAddressUpdate struct {
+	Alias       string           `json:"alias" validate:"required,lte=100"`
+	Address     *address.Address `json:"address" validate:"required"`
+	Reference   string           `json:"reference" validate:"required,lte=200"`
+	Lat         *float64         `json:"lat" validate:"required_with=Lng,omitempty,gte=-90,lte=90"`
+	Lng         *float64         `json:"lng" validate:"required_with=Lat,omitempty,gte=-180,lte=180"`
+	UserID      int              `json:"-"`
+	UpdatedUser string           `json:"updatedUser" validate:"required,lte=200"`
+}
+
+type SavedAddressOut struct {
+	ID          int              `json:"id"`
+	UserID      int              `json:"userId"`
+	Alias       string           `json:"alias"`
+	Address     *address.Address `json:"address"`
+	Reference   string           `json:"reference"`
+	Lat         *float64         `json:"lat"`
+	Lng         *float64         `json:"lng"`
+	Application string           `json:"application"`
+	CreatedUser string           `json:"created_user"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedUser string           `json:"updated_user"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	Status      string           `json:"status"`
+}
+
+// Our repository will implement these methods.
+// Every read is limited to the entries of one user.
+type ContactRepository interface {
+	GetContacts(ctx context.Context, userID int) (*[]ContactOut, error)
+	GetContact(ctx context.Context, contactID int, userID int) (*ContactOut, error)
+	CreateContact(ctx context.Context, contact *Contact) (sql.Result, error)
+	UpdateContact(ctx context.Context, contactID int, contact *Contact) error
+	DeleteContact(ctx context.Context, contactID int, contact *Contact) error
+	GetSavedAddresses(ctx context.Context, userID int) (*[]SavedAddressOut, error)
+	GetSavedAddress(ctx context.Context, savedAddressID int, userID int) (*SavedAddressOut, error)
+	CreateSavedAddress(ctx context.Context, savedAddress *SavedAddress) (sql.Result, error)
+	UpdateSavedAddress(ctx context.Context, savedAddressID int, savedAddress *SavedAddress) error
+	DeleteSavedAddress(ctx context.Context, savedAddressID int, savedAddress *SavedAddress) error
+}
+
+// Our use-case or service will implement these methods.
+type ContactService interface {
+	GetContacts(ctx context.Context, userID int) (*[]ContactOut, error)
+	GetContact(ctx context.Context, contactID int, userID int) (*ContactOut, error)
+	CreateContact(ctx context.Context, contactInsert *ContactInsert) (*ContactOut, error)
+	UpdateContact(ctx context.Context, contactID int, contactUpdate *ContactUpdate) (*ContactOut, error)
+	DeleteContact(ctx context.Context, contactID int, contactDelete *ContactDelete) error
+	GetSavedAddresses(ctx context.Context, userID int) (*[]SavedAddressOut, error)
+	GetSavedAddress(ctx context.Context, savedAddressID int, userID int) (*SavedAddressOut, error)
+	CreateSavedAddress(ctx context.Context, savedAddressInsert *SavedAddressInsert) (*SavedAddressOut, error)
+	UpdateSavedAddress(ctx context.Context, savedAddressID int, savedAddressUpdate *SavedAddressUpdate) (*SavedAddressOut, error)
+	DeleteSavedAddress(ctx context.Context, savedAddressID int, contactDelete *ContactDelete) error
+}
@@ -0,0 +1,535 @@
+package contact
+
+import (
+	"context"
+	"delivery-service/internal/middleware"
+	"delivery-service/internal/utils"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Represents our handler with our use-case / service.
+type ContactHandler struct {
+	contactService ContactService
+}
+
+// Creates a new handler.
+func NewContactHandler(contactRoute fiber.Router, cs ContactService) {
+	// Create a handler based on our created service / use-case.
+	handler := &ContactHandler{
+		contactService: cs,
+	}
+
+	// We will restrict this route with our JWT middleware.
+	contactRoute.Use(middleware.JWTProtected(), middleware.ExtractTokenMetadata)
+
+	// Declare routing endpoints for general routes.
+	contactRoute.Get("", handler.getContacts)
+	contactRoute.Post("", handler.createContact)
+
+	// Declare routing endpoints for saved addresses.
+	contactRoute.Get("/addresses", handler.getSavedAddresses)
+	contactRoute.Post("/addresses", handler.createSavedAddress)
+	contactRoute.Get("/addresses/:addressID", handler.getSavedAddress)
+	contactRoute.Put("/addresses/:addressID", handler.updateSavedAddress)
+	contactRoute.Delete("/addresses/:addressID", handler.deleteSavedAddress)
+
+	// Declare routing endpoints for specific routes.
+	contactRoute.Get("/:contactID", handler.getContact)
+	contactRoute.Put("/:contactID", handler.updateContact)
+	contactRoute.Delete("/:contactID", handler.deleteContact)
+}
+
+// Gets all contacts of the user.
+func (h *ContactHandler) getContacts(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Get all contacts of the user.
+	contacts, err := h.contactService.GetContacts(customContext, c.Locals("userid").(int))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Contacts obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      contacts,
+	})
+}
+
+// Gets a single contact of the user.
+func (h *ContactHandler) getContact(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedContactID, err := c.ParamsInt("contactID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid contact ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get one contact.
+	contact, err := h.contactService.GetContact(customContext, targetedContactID, c.Locals("userid").(int))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if contact == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("Contact of ID {%d} does not exist.", targetedContactID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Contact obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      contact,
+	})
+}
+
+// Creates a single contact.
+func (h *ContactHandler) createContact(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	contactInsert := &ContactInsert{}
+
+	// Parse request body.
+	if err := c.BodyParser(contactInsert); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Contact model.
+	validate := utils.NewValidator()
+
+	// Validate contact fields.
+	if err := validate.Struct(contactInsert); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// The contact belongs to the user of the token.
+	contactInsert.UserID = c.Locals("userid").(int)
+	contactInsert.Application = c.Locals("application").(string)
+
+	// Create one contact.
+	contact, err := h.contactService.CreateContact(customContext, contactInsert)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusCreated).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Contact has been created successfully!",
+		"http_code": fiber.StatusCreated,
+		"data":      contact,
+	})
+}
+
+// Updates a single contact.
+func (h *ContactHandler) updateContact(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	contactUpdate := &ContactUpdate{}
+
+	// Fetch parameter.
+	targetedContactID, err := c.ParamsInt("contactID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid contact ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(contactUpdate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Contact model.
+	validate := utils.NewValidator()
+
+	// Validate contact fields.
+	if err := validate.Struct(contactUpdate); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Only the contacts of the user of the token can be updated.
+	contactUpdate.UserID = c.Locals("userid").(int)
+
+	// Update one contact.
+	contact, err := h.contactService.UpdateContact(customContext, targetedContactID, contactUpdate)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Contact has been updated successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      contact,
+	})
+}
+
+// Deletes a single contact.
+func (h *ContactHandler) deleteContact(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	contactDelete := &ContactDelete{}
+
+	// Fetch parameter.
+	targetedContactID, err := c.ParamsInt("contactID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid contact ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(contactDelete); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Contact model.
+	validate := utils.NewValidator()
+
+	// Validate contact fields.
+	if err := validate.Struct(contactDelete); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Only the contacts of the user of the token can be deleted.
+	contactDelete.UserID = c.Locals("userid").(int)
+
+	// Delete one contact.
+	err = h.contactService.DeleteContact(customContext, targetedContactID, contactDelete)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Contact has been deleted successfully!",
+		"http_code": fiber.StatusOK,
+	})
+}
+
+// Gets all saved addresses of the user.
+func (h *ContactHandler) getSavedAddresses(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Get all saved addresses of the user.
+	savedAddresses, err := h.contactService.GetSavedAddresses(customContext, c.Locals("userid").(int))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Saved addresss obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      savedAddresses,
+	})
+}
+
+// Gets a single saved address of the user.
+func (h *ContactHandler) getSavedAddress(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedSavedAddressID, err := c.ParamsInt("addressID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid saved address ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get one saved address.
+	savedAddress, err := h.contactService.GetSavedAddress(customContext, targetedSavedAddressID, c.Locals("userid").(int))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if savedAddress == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("Saved address of ID {%d} does not exist.", targetedSavedAddressID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Saved address obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      savedAddress,
+	})
+}
+
+// Creates a single saved address.
+func (h *ContactHandler) createSavedAddress(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	savedAddressInsert := &SavedAddressInsert{}
+
+	// Parse request body.
+	if err := c.BodyParser(savedAddressInsert); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a SavedAddress model.
+	validate := utils.NewValidator()
+
+	// Validate saved address fields.
+	if err := validate.Struct(savedAddressInsert); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// The saved address belongs to the user of the token.
+	savedAddressInsert.UserID = c.Locals("userid").(int)
+	savedAddressInsert.Application = c.Locals("application").(string)
+
+	// Create one saved address.
+	savedAddress, err := h.contactService.CreateSavedAddress(customContext, savedAddressInsert)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusCreated).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Saved address has been created successfully!",
+		"http_code": fiber.StatusCreated,
+		"data":      savedAddress,
+	})
+}
+
+// Updates a single saved address.
+func (h *ContactHandler) updateSavedAddress(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	savedAddressUpdate := &SavedAddressUpdate{}
+
+	// Fetch parameter.
+	targetedSavedAddressID, err := c.ParamsInt("addressID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid saved address ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(savedAddressUpdate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a SavedAddress model.
+	validate := utils.NewValidator()
+
+	// Validate saved address fields.
+	if err := validate.Struct(savedAddressUpdate); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Only the saved addresses of the user of the token can be updated.
+	savedAddressUpdate.UserID = c.Locals("userid").(int)
+
+	// Update one saved address.
+	savedAddress, err := h.contactService.UpdateSavedAddress(customContext, targetedSavedAddressID, savedAddressUpdate)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Saved address has been updated successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      savedAddress,
+	})
+}
+
+// Deletes a single saved address.
+func (h *ContactHandler) deleteSavedAddress(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	contactDelete := &ContactDelete{}
+
+	// Fetch parameter.
+	targetedSavedAddressID, err := c.ParamsInt("addressID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid saved address ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(contactDelete); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a SavedAddress model.
+	validate := utils.NewValidator()
+
+	// Validate saved address fields.
+	if err := validate.Struct(contactDelete); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Only the saved addresses of the user of the token can be deleted.
+	contactDelete.UserID = c.Locals("userid").(int)
+
+	// Delete one saved address.
+	err = h.contactService.DeleteSavedAddress(customContext, targetedSavedAddressID, contactDelete)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Saved address has been deleted successfully!",
+		"http_code": fiber.StatusOK,
+	})
+}
@@ -0,0 +1,281 @@
+package contact
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/internal/address"
+)
+
+// Queries that we will use.
+const (
+	QUERY_CONTACT_COLUMNS = "id, user_id, alias, document_id, full_name, phone, email, application, created_user, created_at, updated_user, updated_at, status"
+	QUERY_GET_CONTACTS    = "SELECT " + QUERY_CONTACT_COLUMNS + " FROM contact WHERE user_id = ? and status = ? order by alias asc"
+	QUERY_GET_CONTACT     = "SELECT " + QUERY_CONTACT_COLUMNS + " FROM contact WHERE id = ? and user_id = ? and status = ?"
+	QUERY_CREATE_CONTACT  = "INSERT INTO contact (user_id, alias, document_id, full_name, phone, email, application, created_user, created_at, updated_user, updated_at, status) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_UPDATE_CONTACT = "UPDATE contact SET alias = ?, document_id = ?, full_name = ?, phone = ?, email = ?, updated_user = ?, updated_at = ? " +
+		"WHERE id = ? and user_id = ?"
+	QUERY_DELETE_CONTACT = "UPDATE contact SET status = ?, updated_user = ?, updated_at = ? WHERE id = ? and user_id = ?"
+
+	QUERY_SAVED_ADDRESS_COLUMNS = "id, user_id, alias, street, number, district, city, region, country_code, postal_code, reference, lat, lng, " +
+		"application, created_user, created_at, updated_user, updated_at, status"
+	QUERY_GET_SAVED_ADDRESSES  = "SELECT " + QUERY_SAVED_ADDRESS_COLUMNS + " FROM contact_address WHERE user_id = ? and status = ? order by alias asc"
+	QUERY_GET_SAVED_ADDRESS    = "SELECT " + QUERY_SAVED_ADDRESS_COLUMNS + " FROM contact_address WHERE id = ? and user_id = ? and status = ?"
+	QUERY_CREATE_SAVED_ADDRESS = "INSERT INTO contact_address (user_id, alias, street, number, district, city, region, country_code, postal_code, reference, lat, lng, " +
+		"application, created_user, created_at, updated_user, updated_at, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_UPDATE_SAVED_ADDRESS = "UPDATE contact_address SET alias = ?, street = ?, number = ?, district = ?, city = ?, region = ?, country_code = ?, postal_code = ?, " +
+		"reference = ?, lat = ?, lng = ?, updated_user = ?, updated_at = ? WHERE id = ? and user_id = ?"
+	QUERY_DELETE_SAVED_ADDRESS = "UPDATE contact_address SET status = ?, updated_user = ?, updated_at = ? WHERE id = ? and user_id = ?"
+)
+
+// Represents that we will use MariaDB in order to implement the methods.
+type mariaDBRepository struct {
+	mariadb *sql.DB
+}
+
+// Create a new repository with MariaDB as the driver.
+func NewContactRepository(mariaDBConnection *sql.DB) ContactRepository {
+	return &mariaDBRepository{
+		mariadb: mariaDBConnection,
+	}
+}
+
+// Row of any query that selects 'QUERY_CONTACT_COLUMNS' or 'QUERY_SAVED_ADDRESS_COLUMNS'.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a contact row into the 'ContactOut' struct.
+func scanContact(row rowScanner) (*ContactOut, error) {
+	contact := &ContactOut{}
+	err := row.Scan(&contact.ID, &contact.UserID, &contact.Alias, &contact.DocumentID, &contact.FullName, &contact.Phone, &contact.Email,
+		&contact.Application, &contact.CreatedUser, &contact.CreatedAt, &contact.UpdatedUser, &contact.UpdatedAt, &contact.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// Scans a saved address row into the 'SavedAddressOut' struct.
+func scanSavedAddress(row rowScanner) (*SavedAddressOut, error) {
+	savedAddress := &SavedAddressOut{Address: &address.Address{}}
+	var lat, lng sql.NullFloat64
+	err := row.Scan(&savedAddress.ID, &savedAddress.UserID, &savedAddress.Alias,
+		&savedAddress.Address.Street, &savedAddress.Address.Number, &savedAddress.Address.District, &savedAddress.Address.City, &savedAddress.Address.Region,
+		&savedAddress.Address.CountryCode, &savedAddress.Address.PostalCode, &savedAddress.Reference, &lat, &lng,
+		&savedAddress.Application, &savedAddress.CreatedUser, &savedAddress.CreatedAt, &savedAddress.UpdatedUser, &savedAddress.UpdatedAt, &savedAddress.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// The coordinates are optional.
+	if lat.Valid && lng.Valid {
+		savedAddress.Lat = &lat.Float64
+		savedAddress.Lng = &lng.Float64
+	}
+
+	return savedAddress, nil
+}
+
+// Gets all contacts of a user in the database.
+func (r *mariaDBRepository) GetContacts(ctx context.Context, userID int) (*[]ContactOut, error) {
+	// Initialize variables.
+	contacts := []ContactOut{}
+
+	// Get all contacts.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_CONTACTS, userID, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'contacts' array.
+	for res.Next() {
+		contact, err := scanContact(res)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, *contact)
+	}
+
+	// Return all of our contacts.
+	return &contacts, res.Err()
+}
+
+// Gets a single contact of a user in the database.
+func (r *mariaDBRepository) GetContact(ctx context.Context, contactID int, userID int) (*ContactOut, error) {
+	// Get one contact.
+	// If it's empty, return null.
+	contact, err := scanContact(r.mariadb.QueryRowContext(ctx, QUERY_GET_CONTACT, contactID, userID, "A"))
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return contact, nil
+}
+
+// Creates a single contact in the database.
+func (r *mariaDBRepository) CreateContact(ctx context.Context, contact *Contact) (sql.Result, error) {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_CREATE_CONTACT)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Insert one contact.
+	result, err := stmt.ExecContext(ctx, contact.UserID, contact.Alias, contact.DocumentID, contact.FullName, contact.Phone, contact.Email,
+		contact.Application, contact.CreatedUser, contact.CreatedAt, contact.UpdatedUser, contact.UpdatedAt, contact.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return result, nil
+}
+
+// Updates a single contact in the database.
+func (r *mariaDBRepository) UpdateContact(ctx context.Context, contactID int, contact *Contact) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_CONTACT)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one contact.
+	_, err = stmt.ExecContext(ctx, contact.Alias, contact.DocumentID, contact.FullName, contact.Phone, contact.Email,
+		contact.UpdatedUser, contact.UpdatedAt, contactID, contact.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Deletes a single contact in the database.
+func (r *mariaDBRepository) DeleteContact(ctx context.Context, contactID int, contact *Contact) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_DELETE_CONTACT)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Delete one contact.
+	_, err = stmt.ExecContext(ctx, contact.Status, contact.UpdatedUser, contact.UpdatedAt, contactID, contact.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Gets all saved addresses of a user in the database.
+func (r *mariaDBRepository) GetSavedAddresses(ctx context.Context, userID int) (*[]SavedAddressOut, error) {
+	// Initialize variables.
+	savedAddresses := []SavedAddressOut{}
+
+	// Get all saved addresses.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_SAVED_ADDRESSES, userID, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'savedAddresses' array.
+	for res.Next() {
+		savedAddress, err := scanSavedAddress(res)
+		if err != nil {
+			return nil, err
+		}
+		savedAddresses = append(savedAddresses, *savedAddress)
+	}
+
+	// Return all of our saved addresses.
+	return &savedAddresses, res.Err()
+}
+
+// Gets a single saved address of a user in the database.
+func (r *mariaDBRepository) GetSavedAddress(ctx context.Context, savedAddressID int, userID int) (*SavedAddressOut, error) {
+	// Get one saved address.
+	// If it's empty, return null.
+	savedAddress, err := scanSavedAddress(r.mariadb.QueryRowContext(ctx, QUERY_GET_SAVED_ADDRESS, savedAddressID, userID, "A"))
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return savedAddress, nil
+}
+
+// Creates a single saved address in the database.
+func (r *mariaDBRepository) CreateSavedAddress(ctx context.Context, savedAddress *SavedAddress) (sql.Result, error) {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_CREATE_SAVED_ADDRESS)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Insert one saved address.
+	result, err := stmt.ExecContext(ctx, savedAddress.UserID, savedAddress.Alias, savedAddress.Street, savedAddress.Number, savedAddress.District,
+		savedAddress.City, savedAddress.Region, savedAddress.CountryCode, savedAddress.PostalCode, savedAddress.Reference, savedAddress.Lat, savedAddress.Lng,
+		savedAddress.Application, savedAddress.CreatedUser, savedAddress.CreatedAt, savedAddress.UpdatedUser, savedAddress.UpdatedAt, savedAddress.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return result, nil
+}
+
+// Updates a single saved address in the database.
+func (r *mariaDBRepository) UpdateSavedAddress(ctx context.Context, savedAddressID int, savedAddress *SavedAddress) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_SAVED_ADDRESS)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one saved address.
+	_, err = stmt.ExecContext(ctx, savedAddress.Alias, savedAddress.Street, savedAddress.Number, savedAddress.District, savedAddress.City,
+		savedAddress.Region, savedAddress.CountryCode, savedAddress.PostalCode, savedAddress.Reference, savedAddress.Lat, savedAddress.Lng,
+		savedAddress.UpdatedUser, savedAddress.UpdatedAt, savedAddressID, savedAddress.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Deletes a single saved address in the database.
+func (r *mariaDBRepository) DeleteSavedAddress(ctx context.Context, savedAddressID int, savedAddress *SavedAddress) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_DELETE_SAVED_ADDRESS)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Delete one saved address.
+	_, err = stmt.ExecContext(ctx, savedAddress.Status, savedAddress.UpdatedUser, savedAddress.UpdatedAt, savedAddressID, savedAddress.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
@@ -0,0 +1,238 @@
+package contact
+
+import (
+	"context"
+	"delivery-service/internal/address"
+	"delivery-service/internal/utils"
+	"fmt"
+	"time"
+)
+
+// Implementation of the repository in this service.
+type contactService struct {
+	contactRepository ContactRepository
+}
+
+// Create a new 'service' or 'use-case' for 'Contact' entity.
+func NewContactService(r ContactRepository) ContactService {
+	return &contactService{
+		contactRepository: r,
+	}
+}
+
+// Implementation of 'GetContacts'.
+func (s *contactService) GetContacts(ctx context.Context, userID int) (*[]ContactOut, error) {
+	return s.contactRepository.GetContacts(ctx, userID)
+}
+
+// Implementation of 'GetContact'.
+func (s *contactService) GetContact(ctx context.Context, contactID int, userID int) (*ContactOut, error) {
+	return s.contactRepository.GetContact(ctx, contactID, userID)
+}
+
+// Implementation of 'CreateContact'.
+func (s *contactService) CreateContact(ctx context.Context, contactInsert *ContactInsert) (*ContactOut, error) {
+	// Set initialized default data for contact.
+	contact := &Contact{
+		UserID:      contactInsert.UserID,
+		Alias:       contactInsert.Alias,
+		DocumentID:  contactInsert.DocumentID,
+		FullName:    contactInsert.FullName,
+		Phone:       contactInsert.Phone,
+		Email:       contactInsert.Email,
+		Application: contactInsert.Application,
+		CreatedUser: contactInsert.CreatedUser,
+		CreatedAt:   time.Now(),
+		Status:      "A",
+	}
+
+	// Pass to the repository layer.
+	result, err := s.contactRepository.CreateContact(ctx, contact)
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not insert record")
+	}
+
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not get inserted id")
+	}
+
+	return s.contactRepository.GetContact(ctx, int(insertedID), contactInsert.UserID)
+}
+
+// Implementation of 'UpdateContact'.
+func (s *contactService) UpdateContact(ctx context.Context, contactID int, contactUpdate *ContactUpdate) (*ContactOut, error) {
+	// Check if contact exists, contacts of other users do not.
+	searchedContact, err := s.contactRepository.GetContact(ctx, contactID, contactUpdate.UserID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedContact == nil {
+		return nil, fmt.Errorf("There is no contact with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	contact := &Contact{
+		UserID:      contactUpdate.UserID,
+		Alias:       contactUpdate.Alias,
+		DocumentID:  contactUpdate.DocumentID,
+		FullName:    contactUpdate.FullName,
+		Phone:       contactUpdate.Phone,
+		Email:       contactUpdate.Email,
+		UpdatedUser: contactUpdate.UpdatedUser,
+		UpdatedAt:   time.Now(),
+	}
+
+	// Pass to the repository layer.
+	if err = s.contactRepository.UpdateContact(ctx, contactID, contact); err != nil {
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	return s.contactRepository.GetContact(ctx, contactID, contactUpdate.UserID)
+}
+
+// Implementation of 'DeleteContact'.
+func (s *contactService) DeleteContact(ctx context.Context, contactID int, contactDelete *ContactDelete) error {
+	// Check if contact exists, contacts of other users do not.
+	searchedContact, err := s.contactRepository.GetContact(ctx, contactID, contactDelete.UserID)
+	if err != nil {
+		return utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedContact == nil {
+		return fmt.Errorf("There is no contact with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	contact := &Contact{
+		UserID:      contactDelete.UserID,
+		UpdatedUser: contactDelete.UpdatedUser,
+		UpdatedAt:   time.Now(),
+		Status:      "I",
+	}
+
+	// Pass to the repository layer.
+	if err = s.contactRepository.DeleteContact(ctx, contactID, contact); err != nil {
+		return utils.FailOnError(err, "could not delete record")
+	}
+
+	return nil
+}
+
+// Implementation of 'GetSavedAddresses'.
+func (s *contactService) GetSavedAddresses(ctx context.Context, userID int) (*[]SavedAddressOut, error) {
+	return s.contactRepository.GetSavedAddresses(ctx, userID)
+}
+
+// Implementation of 'GetSavedAddress'.
+func (s *contactService) GetSavedAddress(ctx context.Context, savedAddressID int, userID int) (*SavedAddressOut, error) {
+	return s.contactRepository.GetSavedAddress(ctx, savedAddressID, userID)
+}
+
+// Implementation of 'CreateSavedAddress'.
+func (s *contactService) CreateSavedAddress(ctx context.Context, savedAddressInsert *SavedAddressInsert) (*SavedAddressOut, error) {
+	// Saved addresses are stored in the same shape as the orders.
+	if err := address.Normalize(savedAddressInsert.Address); err != nil {
+		return nil, err
+	}
+
+	// Set initialized default data for saved address.
+	savedAddress := &SavedAddress{
+		UserID:      savedAddressInsert.UserID,
+		Alias:       savedAddressInsert.Alias,
+		Street:      savedAddressInsert.Address.Street,
+		Number:      savedAddressInsert.Address.Number,
+		District:    savedAddressInsert.Address.District,
+		City:        savedAddressInsert.Address.City,
+		Region:      savedAddressInsert.Address.Region,
+		CountryCode: savedAddressInsert.Address.CountryCode,
+		PostalCode:  savedAddressInsert.Address.PostalCode,
+		Reference:   savedAddressInsert.Reference,
+		Lat:         savedAddressInsert.Lat,
+		Lng:         savedAddressInsert.Lng,
+		Application: savedAddressInsert.Application,
+		CreatedUser: savedAddressInsert.CreatedUser,
+		CreatedAt:   time.Now(),
+		Status:      "A",
+	}
+
+	// Pass to the repository layer.
+	result, err := s.contactRepository.CreateSavedAddress(ctx, savedAddress)
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not insert record")
+	}
+
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not get inserted id")
+	}
+
+	return s.contactRepository.GetSavedAddress(ctx, int(insertedID), savedAddressInsert.UserID)
+}
+
+// Implementation of 'UpdateSavedAddress'.
+func (s *contactService) UpdateSavedAddress(ctx context.Context, savedAddressID int, savedAddressUpdate *SavedAddressUpdate) (*SavedAddressOut, error) {
+	// Check if saved address exists, addresses of other users do not.
+	searchedSavedAddress, err := s.contactRepository.GetSavedAddress(ctx, savedAddressID, savedAddressUpdate.UserID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedSavedAddress == nil {
+		return nil, fmt.Errorf("There is no saved address with this ID")
+	}
+
+	if err := address.Normalize(savedAddressUpdate.Address); err != nil {
+		return nil, err
+	}
+
+	// Set value for 'Modified' attribute.
+	savedAddress := &SavedAddress{
+		UserID:      savedAddressUpdate.UserID,
+		Alias:       savedAddressUpdate.Alias,
+		Street:      savedAddressUpdate.Address.Street,
+		Number:      savedAddressUpdate.Address.Number,
+		District:    savedAddressUpdate.Address.District,
+		City:        savedAddressUpdate.Address.City,
+		Region:      savedAddressUpdate.Address.Region,
+		CountryCode: savedAddressUpdate.Address.CountryCode,
+		PostalCode:  savedAddressUpdate.Address.PostalCode,
+		Reference:   savedAddressUpdate.Reference,
+		Lat:         savedAddressUpdate.Lat,
+		Lng:         savedAddressUpdate.Lng,
+		UpdatedUser: savedAddressUpdate.UpdatedUser,
+		UpdatedAt:   time.Now(),
+	}
+
+	// Pass to the repository layer.
+	if err = s.contactRepository.UpdateSavedAddress(ctx, savedAddressID, savedAddress); err != nil {
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	return s.contactRepository.GetSavedAddress(ctx, savedAddressID, savedAddressUpdate.UserID)
+}
+
+// Implementation of 'DeleteSavedAddress'.
+func (s *contactService) DeleteSavedAddress(ctx context.Context, savedAddressID int, contactDelete *ContactDelete) error {
+	// Check if saved address exists, addresses of other users do not.
+	searchedSavedAddress, err := s.contactRepository.GetSavedAddress(ctx, savedAddressID, contactDelete.UserID)
+	if err != nil {
+		return utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedSavedAddress == nil {
+		return fmt.Errorf("There is no saved address with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	savedAddress := &SavedAddress{
+		UserID:      contactDelete.UserID,
+		UpdatedUser: contactDelete.UpdatedUser,
+		UpdatedAt:   time.Now(),
+		Status:      "I",
+	}
+
+	// Pass to the repository layer.
+	if err = s.contactRepository.DeleteSavedAddress(ctx, savedAddressID, savedAddress); err != nil {
+		return utils.FailOnError(err, "could not delete record")
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package courier
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Availability of a courier.
+const (
+	COURIER_STATUS_AVAILABLE = "available"
+	COURIER_STATUS_BUSY      = "busy"
+	COURIER_STATUS_OFF_DUTY  = "off_duty"
+)
+
+// Courier struct to describe Courier object.
+type Courier struct {
+	ID             int        `db:"id"`
+	UserID         int        `db:"user_id"`
+	FullName       string     `db:"full_name"`
+	Phone          string     `db:"phone"`
+	VehicleType    string     `db:"vehicle_type"`
+	CapacityKg     int        `db:"capacity_kg"`
+	ActiveZone     string     `db:"active_zone"`
+	CourierStatus  string     `db:"courier_status"`
+	LastLat        *float64   `db:"last_lat"`
+	LastLng        *float64   `db:"last_lng"`
+	LastLocationAt *time.Time `db:"last_location_at"`
+	Application    string     `db:"application"`
+	CreatedUser    string     `db:"created_user"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedUser    string     `db:"updated_user"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+	Status         string     `db:"status"`
+}
+
+// CourierInsert struct to describe register a new courier.
+type CourierInsert struct {
+	UserID      int    `json:"userId" validate:"required,gt=0"`
+	FullName    string `json:"fullName" validate:"required,lte=200"`
+	Phone       string `json:"phone" validate:"required,lte=20"`
+	VehicleType string `json:"vehicleType" validate:"required,eq=bicycle|eq=motorcycle|eq=car|eq=van|eq=truck"`
+	CapacityKg  int    `json:"capacityKg" validate:"required,gt=0"`
+	ActiveZone  string `json:"activeZone" validate:"required,lte=100"`
+	Application string `json:"-"`
+	CreatedUser string `json:"createdUser" validate:"required,lte=200"`
+}
+
+// CourierUpdate struct to describe update courier.
+type CourierUpdate struct {
+	FullName      string `json:"fullName" validate:"required,lte=200"`
+	Phone         string `json:"phone" validate:"required,lte=20"`
+	VehicleType   string `json:"vehicleType" validate:"required,eq=bicycle|eq=motorcycle|eq=car|eq=van|eq=truck"`
+	CapacityKg    int    `json:"capacityKg" validate:"required,gt=0"`
+	ActiveZone    string `json:"activeZone" validate:"required,lte=100"`
+	CourierStatus string `json:"courierStatus" validate:"required,eq=available|eq=busy|eq=off_duty"`
+	UpdatedUser   string `json:"updatedUser" validate:"required,lte=200"`
+}
+
+// CourierDelete struct to describe delete courier.
+type CourierDelete struct {
+	UpdatedUser string `json:"updatedUser" validate:"required,lte=200"`
+}
+
+type CourierOut struct {
+	ID             int        `json:"id"`
+	UserID         int        `json:"userId"`
+	FullName       string     `json:"fullName"`
+	Phone          string     `json:"phone"`
+	VehicleType    string     `json:"vehicleType"`
+	CapacityKg     int        `json:"capacityKg"`
+	ActiveZone     string     `json:"activeZone"`
+	CourierStatus  string     `json:"courierStatus"`
+	LastLat        *float64   `json:"lastLat"`
+	LastLng        *float64   `json:"lastLng"`
+	LastLocationAt *time.Time `json:"lastLocationAt"`
+	Application    string     `json:"application"`
+	CreatedUser    string     `json:"created_user"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedUser    string     `json:"updated_user"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Status         string     `json:"status"`
+}
+
+// CourierLocation struct to describe a GPS ping sent by a courier.
+type CourierLocation struct {
+	Lat        *float64  `json:"lat" validate:"required,gte=-85.05112878,lte=85.05112878"`
+	Lng        *float64  `json:"lng" validate:"required,gte=-180,lte=180"`
+	RecordedAt time.Time `json:"recordedAt" validate:"required"`
+}
+
+// CourierPosition struct to describe the last known position of a courier.
+type CourierPosition struct {
+	CourierID  int       `json:"courierId"`
+	Lat        float64   `json:"lat"`
+	Lng        float64   `json:"lng"`
+	RecordedAt time.Time `json:"recordedAt"`
+	DistanceKm float64   `json:"distanceKm,omitempty"`
+}
+
+// CourierNearbyQuery struct to describe a search of couriers around a point.
+type CourierNearbyQuery struct {
+	Lat         *float64 `query:"lat" validate:"required,gte=-85.05112878,lte=85.05112878"`
+	Lng         *float64 `query:"lng" validate:"required,gte=-180,lte=180"`
+	RadiusKm    float64  `query:"radiusKm" validate:"required,gt=0,lte=100"`
+	Limit       int      `query:"limit" validate:"omitempty,gt=0,lte=100"`
+	Application string   `query:"-"`
+}
+
+type CourierNearbyOut struct {
+	CourierOut
+	Position *CourierPosition `json:"position"`
+}
+
+// Our repository will implement these methods.
+type CourierRepository interface {
+	GetCouriers(ctx context.Context, application string) (*[]CourierOut, error)
+	GetCourier(ctx context.Context, courierID int) (*CourierOut, error)
+	GetCourierByUser(ctx context.Context, userID int) (*CourierOut, error)
+	GetCouriersByIDs(ctx context.Context, courierIDs []int, application string) (*[]CourierOut, error)
+	GetAvailableCouriers(ctx context.Context) (*[]CourierOut, error)
+	CreateCourier(ctx context.Context, courier *Courier) (sql.Result, error)
+	UpdateCourier(ctx context.Context, courierID int, courier *Courier) error
+	DeleteCourier(ctx context.Context, courierID int, courier *Courier) error
+	UpdateCourierLocation(ctx context.Context, courierID int, courier *Courier) error
+}
+
+// Our live location store will implement these methods.
+type LocationRepository interface {
+	SaveLocation(ctx context.Context, position *CourierPosition) (bool, error)
+	GetLocation(ctx context.Context, courierID int) (*CourierPosition, error)
+	GetLocationsNearby(ctx context.Context, lat float64, lng float64, radiusKm float64, maxAge time.Duration, limit int) ([]*CourierPosition, error)
+	PopPendingLocations(ctx context.Context, count int) ([]*CourierPosition, error)
+	MarkLocationsPending(ctx context.Context, courierIDs []int) error
+}
+
+// Our use-case or service will implement these methods.
+type CourierService interface {
+	GetCouriers(ctx context.Context, application string) (*[]CourierOut, error)
+	GetCourier(ctx context.Context, courierID int, application string) (*CourierOut, error)
+	CreateCourier(ctx context.Context, courierInsert *CourierInsert) (*CourierOut, error)
+	UpdateCourier(ctx context.Context, courierID int, application string, courierUpdate *CourierUpdate) (*CourierOut, error)
+	DeleteCourier(ctx context.Context, courierID int, application string, courierDelete *CourierDelete) error
+	ReportLocation(ctx context.Context, userID int, courierLocation *CourierLocation) (*CourierPosition, error)
+	GetCouriersNearby(ctx context.Context, courierNearbyQuery *CourierNearbyQuery) (*[]CourierNearbyOut, error)
+	GetCourierPosition(ctx context.Context, courierID int, application string) (*CourierPosition, error)
+	StartLocationFlusher()
+}
@@ -0,0 +1,453 @@
+package courier
+
+import (
+	"context"
+	"delivery-service/internal/middleware"
+	"delivery-service/internal/utils"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Represents our handler with our use-case / service.
+type CourierHandler struct {
+	courierService CourierService
+}
+
+// Creates a new handler.
+func NewCourierHandler(courierRoute fiber.Router, cs CourierService) {
+	// Create a handler based on our created service / use-case.
+	handler := &CourierHandler{
+		courierService: cs,
+	}
+
+	// We will restrict this route with our JWT middleware.
+	// Couriers are scoped to the application of the token.
+	courierRoute.Use(middleware.JWTProtected(), middleware.ExtractTokenMetadata)
+
+	// Declare routing endpoints for general routes.
+	courierRoute.Get("", handler.getCouriers)
+	courierRoute.Post("", handler.createCourier)
+
+	// Declare routing endpoints for live locations.
+	courierRoute.Get("/nearby", handler.getCouriersNearby)
+	courierRoute.Post("/me/location", handler.reportLocation)
+
+	// Declare routing endpoints for specific routes.
+	courierRoute.Get("/:courierID", handler.getCourier)
+	courierRoute.Get("/:courierID/location", handler.getCourierPosition)
+	courierRoute.Put("/:courierID", handler.updateCourier)
+	courierRoute.Delete("/:courierID", handler.deleteCourier)
+}
+
+// Gets all couriers.
+func (h *CourierHandler) getCouriers(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Get all couriers of the application.
+	couriers, err := h.courierService.GetCouriers(customContext, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Couriers obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      couriers,
+	})
+}
+
+// Gets a single courier.
+func (h *CourierHandler) getCourier(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedCourierID, err := c.ParamsInt("courierID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid courier ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get one courier.
+	courier, err := h.courierService.GetCourier(customContext, targetedCourierID, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if courier == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("Courier of ID {%d} does not exist.", targetedCourierID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Courier obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      courier,
+	})
+}
+
+// Creates a single courier.
+func (h *CourierHandler) createCourier(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	courierInsert := &CourierInsert{}
+
+	// Parse request body.
+	if err := c.BodyParser(courierInsert); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Courier model.
+	validate := utils.NewValidator()
+
+	// Validate courier fields.
+	if err := validate.Struct(courierInsert); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// The courier belongs to the application of the caller.
+	courierInsert.Application = c.Locals("application").(string)
+
+	// Create one courier.
+	courier, err := h.courierService.CreateCourier(customContext, courierInsert)
+	if err != nil && errors.Is(err, ErrForeignUser) {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusCreated).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Courier has been created successfully!",
+		"http_code": fiber.StatusCreated,
+		"data":      courier,
+	})
+}
+
+// Updates a single courier.
+func (h *CourierHandler) updateCourier(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	courierUpdate := &CourierUpdate{}
+
+	// Fetch parameter.
+	targetedCourierID, err := c.ParamsInt("courierID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid courier ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(courierUpdate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Courier model.
+	validate := utils.NewValidator()
+
+	// Validate courier fields.
+	if err := validate.Struct(courierUpdate); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Update one courier.
+	courier, err := h.courierService.UpdateCourier(customContext, targetedCourierID, c.Locals("application").(string), courierUpdate)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Courier has been updated successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      courier,
+	})
+}
+
+// Deletes a single courier.
+func (h *CourierHandler) deleteCourier(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	courierDelete := &CourierDelete{}
+
+	// Fetch parameter.
+	targetedCourierID, err := c.ParamsInt("courierID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid courier ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(courierDelete); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Courier model.
+	validate := utils.NewValidator()
+
+	// Validate courier fields.
+	if err := validate.Struct(courierDelete); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Delete one courier.
+	err = h.courierService.DeleteCourier(customContext, targetedCourierID, c.Locals("application").(string), courierDelete)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Courier has been deleted successfully!",
+		"http_code": fiber.StatusOK,
+	})
+}
+
+// Stores a GPS ping of the courier of the token.
+func (h *CourierHandler) reportLocation(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	courierLocation := &CourierLocation{}
+
+	// Parse request body.
+	if err := c.BodyParser(courierLocation); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Courier model.
+	validate := utils.NewValidator()
+
+	// Validate location fields.
+	if err := validate.Struct(courierLocation); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Store the location of the courier.
+	position, err := h.courierService.ReportLocation(customContext, c.Locals("userid").(int), courierLocation)
+	if err != nil && errors.Is(err, ErrNotCourier) {
+		return c.Status(fiber.StatusForbidden).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusForbidden,
+		})
+	}
+	if err != nil && errors.Is(err, ErrFutureLocation) {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+	if err != nil && errors.Is(err, ErrStaleLocation) {
+		return c.Status(fiber.StatusConflict).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusConflict,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusAccepted).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Location has been received successfully!",
+		"http_code": fiber.StatusAccepted,
+		"data":      position,
+	})
+}
+
+// Gets the couriers within a radius of a point.
+func (h *CourierHandler) getCouriersNearby(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	courierNearbyQuery := &CourierNearbyQuery{}
+
+	// Parse query string.
+	if err := c.QueryParser(courierNearbyQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Courier model.
+	validate := utils.NewValidator()
+
+	// Validate search fields.
+	if err := validate.Struct(courierNearbyQuery); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the couriers of the application around the point.
+	courierNearbyQuery.Application = c.Locals("application").(string)
+	couriers, err := h.courierService.GetCouriersNearby(customContext, courierNearbyQuery)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Couriers obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      couriers,
+	})
+}
+
+// Gets the last known position of a single courier.
+func (h *CourierHandler) getCourierPosition(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedCourierID, err := c.ParamsInt("courierID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid courier ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the position of one courier.
+	position, err := h.courierService.GetCourierPosition(customContext, targetedCourierID, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if position == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("Courier of ID {%d} has no known location.", targetedCourierID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Location obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      position,
+	})
+}
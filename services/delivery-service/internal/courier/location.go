@@ -0,0 +1,209 @@
+package courier
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Stores a ping only if it is newer than the last one of the courier.
+// KEYS: geo set, timestamps hash, pending set. ARGV: courier ID, longitude, latitude, unix milliseconds.
+var saveLocationScript = redis.NewScript(`
+local last = redis.call('HGET', KEYS[2], ARGV[1])
+if last and tonumber(last) >= tonumber(ARGV[4]) then
+	return 0
+end
+redis.call('GEOADD', KEYS[1], ARGV[2], ARGV[3], ARGV[1])
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[4])
+redis.call('SADD', KEYS[3], ARGV[1])
+return 1
+`)
+
+// Represents that we will use Redis GEO sets for the live locations.
+type redisLocationRepository struct {
+	redis         *redis.Client
+	geoKey        string
+	recordedAtKey string
+	pendingKey    string
+}
+
+// Create a new live location store with Redis as the driver.
+func NewLocationRepository(redisConnection *redis.Client) LocationRepository {
+	geoKey := os.Getenv("COURIER_LOCATION_KEY")
+	if geoKey == "" {
+		geoKey = "delivery-service.courier-locations"
+	}
+
+	return &redisLocationRepository{
+		redis:         redisConnection,
+		geoKey:        geoKey,
+		recordedAtKey: geoKey + ".recorded-at",
+		pendingKey:    geoKey + ".pending",
+	}
+}
+
+// Saves the position of a courier, returns false when a newer one is already stored.
+func (r *redisLocationRepository) SaveLocation(ctx context.Context, position *CourierPosition) (bool, error) {
+	keys := []string{r.geoKey, r.recordedAtKey, r.pendingKey}
+	saved, err := saveLocationScript.Run(ctx, r.redis, keys, position.CourierID, position.Lng, position.Lat, position.RecordedAt.UnixNano()/int64(time.Millisecond)).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return saved == 1, nil
+}
+
+// Gets the last position of a single courier, null if it never reported one.
+func (r *redisLocationRepository) GetLocation(ctx context.Context, courierID int) (*CourierPosition, error) {
+	member := strconv.Itoa(courierID)
+
+	coordinates, err := r.redis.GeoPos(ctx, r.geoKey, member).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(coordinates) == 0 || coordinates[0] == nil {
+		return nil, nil
+	}
+
+	recordedAt, err := r.recordedAt(ctx, member)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CourierPosition{
+		CourierID:  courierID,
+		Lat:        coordinates[0].Latitude,
+		Lng:        coordinates[0].Longitude,
+		RecordedAt: recordedAt,
+	}, nil
+}
+
+// Gets the positions within the radius of a point, closest first.
+// Positions older than 'maxAge' are left out before the limit is applied, a zero 'maxAge' keeps every position.
+func (r *redisLocationRepository) GetLocationsNearby(ctx context.Context, lat float64, lng float64, radiusKm float64, maxAge time.Duration, limit int) ([]*CourierPosition, error) {
+	// Every member of the radius is read, the stale ones could otherwise take the place of live ones.
+	locations, err := r.redis.GeoRadius(ctx, r.geoKey, lng, lat, &redis.GeoRadiusQuery{
+		Radius:    radiusKm,
+		Unit:      "km",
+		WithCoord: true,
+		WithDist:  true,
+		Sort:      "ASC",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return []*CourierPosition{}, nil
+	}
+
+	// Get the time of every position at once.
+	members := make([]string, 0, len(locations))
+	for _, location := range locations {
+		members = append(members, location.Name)
+	}
+	recordedAts, err := r.redis.HMGet(ctx, r.recordedAtKey, members...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]*CourierPosition, 0, limit)
+	for i, location := range locations {
+		if len(positions) >= limit {
+			break
+		}
+
+		courierID, err := strconv.Atoi(location.Name)
+		if err != nil {
+			continue
+		}
+
+		var recordedAt time.Time
+		if value, ok := recordedAts[i].(string); ok {
+			milliseconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			recordedAt = time.Unix(0, milliseconds*int64(time.Millisecond))
+		}
+		if maxAge > 0 && time.Since(recordedAt) > maxAge {
+			continue
+		}
+
+		positions = append(positions, &CourierPosition{
+			CourierID:  courierID,
+			Lat:        location.Latitude,
+			Lng:        location.Longitude,
+			RecordedAt: recordedAt,
+			DistanceKm: location.Dist,
+		})
+	}
+
+	return positions, nil
+}
+
+// Takes up to 'count' positions that have not been persisted yet.
+func (r *redisLocationRepository) PopPendingLocations(ctx context.Context, count int) ([]*CourierPosition, error) {
+	members, err := r.redis.SPopN(ctx, r.pendingKey, int64(count)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]*CourierPosition, 0, len(members))
+	for _, member := range members {
+		courierID, err := strconv.Atoi(member)
+		if err != nil {
+			continue
+		}
+
+		position, err := r.GetLocation(ctx, courierID)
+		if err != nil {
+			// Put everything back, the next flush will retry.
+			_ = r.MarkLocationsPending(ctx, membersToIDs(members))
+			return nil, err
+		}
+		if position != nil {
+			positions = append(positions, position)
+		}
+	}
+
+	return positions, nil
+}
+
+// Marks the positions of the couriers to be persisted again.
+func (r *redisLocationRepository) MarkLocationsPending(ctx context.Context, courierIDs []int) error {
+	if len(courierIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, 0, len(courierIDs))
+	for _, courierID := range courierIDs {
+		members = append(members, courierID)
+	}
+
+	return r.redis.SAdd(ctx, r.pendingKey, members...).Err()
+}
+
+// Gets the time of the last ping of a courier.
+func (r *redisLocationRepository) recordedAt(ctx context.Context, member string) (time.Time, error) {
+	milliseconds, err := r.redis.HGet(ctx, r.recordedAtKey, member).Int64()
+	if err != nil && err != redis.Nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, milliseconds*int64(time.Millisecond)), nil
+}
+
+// Converts the members of a Redis set to courier IDs.
+func membersToIDs(members []string) []int {
+	courierIDs := make([]int, 0, len(members))
+	for _, member := range members {
+		if courierID, err := strconv.Atoi(member); err == nil {
+			courierIDs = append(courierIDs, courierID)
+		}
+	}
+
+	return courierIDs
+}
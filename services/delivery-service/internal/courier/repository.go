@@ -0,0 +1,228 @@
+package courier
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Queries that we will use.
+const (
+	QUERY_COURIER_COLUMNS = "id, user_id, full_name, phone, vehicle_type, capacity_kg, active_zone, courier_status, last_lat, last_lng, last_location_at, " +
+		"application, created_user, created_at, updated_user, updated_at, status"
+	QUERY_GET_COURIERS        = "SELECT " + QUERY_COURIER_COLUMNS + " FROM courier WHERE application = ? and status = ?"
+	QUERY_GET_COURIERS_STATUS = "SELECT " + QUERY_COURIER_COLUMNS + " FROM courier WHERE courier_status = ? and status = ?"
+	QUERY_GET_COURIER         = "SELECT " + QUERY_COURIER_COLUMNS + " FROM courier WHERE id = ? and status = ?"
+	QUERY_GET_COURIER_BY_USER = "SELECT " + QUERY_COURIER_COLUMNS + " FROM courier WHERE user_id = ? and status = ?"
+	QUERY_GET_COURIERS_BY_IDS = "SELECT " + QUERY_COURIER_COLUMNS + " FROM courier WHERE id IN (%s) and application = ? and status = ?"
+	QUERY_CREATE_COURIER      = "INSERT INTO courier (user_id, full_name, phone, vehicle_type, capacity_kg, active_zone, courier_status, application, created_user, created_at, updated_user, updated_at, status) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_UPDATE_COURIER          = "UPDATE courier SET full_name = ?, phone = ?, vehicle_type = ?, capacity_kg = ?, active_zone = ?, courier_status = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_DELETE_COURIER          = "UPDATE courier SET status = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_UPDATE_COURIER_LOCATION = "UPDATE courier SET last_lat = ?, last_lng = ?, last_location_at = ? " +
+		"WHERE id = ? and (last_location_at IS NULL or last_location_at < ?)"
+)
+
+// Represents that we will use MariaDB in order to implement the methods.
+type mariaDBRepository struct {
+	mariadb *sql.DB
+}
+
+// Create a new repository with MariaDB as the driver.
+func NewCourierRepository(mariaDBConnection *sql.DB) CourierRepository {
+	return &mariaDBRepository{
+		mariadb: mariaDBConnection,
+	}
+}
+
+// Row of any query that selects 'QUERY_COURIER_COLUMNS'.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a courier row into the 'CourierOut' struct.
+func scanCourier(row rowScanner) (*CourierOut, error) {
+	courier := &CourierOut{}
+	var lastLat, lastLng sql.NullFloat64
+	var lastLocationAt sql.NullTime
+	err := row.Scan(&courier.ID, &courier.UserID, &courier.FullName, &courier.Phone, &courier.VehicleType, &courier.CapacityKg, &courier.ActiveZone,
+		&courier.CourierStatus, &lastLat, &lastLng, &lastLocationAt,
+		&courier.Application, &courier.CreatedUser, &courier.CreatedAt, &courier.UpdatedUser, &courier.UpdatedAt, &courier.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// The position is unknown until the courier reports it.
+	if lastLat.Valid && lastLng.Valid {
+		courier.LastLat = &lastLat.Float64
+		courier.LastLng = &lastLng.Float64
+	}
+	if lastLocationAt.Valid {
+		courier.LastLocationAt = &lastLocationAt.Time
+	}
+
+	return courier, nil
+}
+
+// Gets all couriers of an application in the database.
+func (r *mariaDBRepository) GetCouriers(ctx context.Context, application string) (*[]CourierOut, error) {
+	return r.getCouriers(ctx, QUERY_GET_COURIERS, application, "A")
+}
+
+// Gets the couriers that can take new orders in the database.
+func (r *mariaDBRepository) GetAvailableCouriers(ctx context.Context) (*[]CourierOut, error) {
+	return r.getCouriers(ctx, QUERY_GET_COURIERS_STATUS, COURIER_STATUS_AVAILABLE, "A")
+}
+
+// Gets the couriers of an application with the given IDs in the database.
+func (r *mariaDBRepository) GetCouriersByIDs(ctx context.Context, courierIDs []int, application string) (*[]CourierOut, error) {
+	if len(courierIDs) == 0 {
+		return &[]CourierOut{}, nil
+	}
+
+	args := make([]interface{}, 0, len(courierIDs)+2)
+	for _, courierID := range courierIDs {
+		args = append(args, courierID)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(courierIDs)), ",")
+
+	return r.getCouriers(ctx, fmt.Sprintf(QUERY_GET_COURIERS_BY_IDS, placeholders), append(args, application, "A")...)
+}
+
+// Gets all couriers with the given query.
+func (r *mariaDBRepository) getCouriers(ctx context.Context, query string, args ...interface{}) (*[]CourierOut, error) {
+	// Initialize variables.
+	var couriers []CourierOut
+
+	// Get all couriers.
+	res, err := r.mariadb.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'couriers' array.
+	for res.Next() {
+		courier, err := scanCourier(res)
+		if err != nil {
+			return nil, err
+		}
+		couriers = append(couriers, *courier)
+	}
+
+	// Return all of our couriers.
+	return &couriers, res.Err()
+}
+
+// Gets a single courier in the database.
+func (r *mariaDBRepository) GetCourier(ctx context.Context, courierID int) (*CourierOut, error) {
+	return r.getCourier(ctx, QUERY_GET_COURIER, courierID)
+}
+
+// Gets the courier of a user in the database.
+func (r *mariaDBRepository) GetCourierByUser(ctx context.Context, userID int) (*CourierOut, error) {
+	return r.getCourier(ctx, QUERY_GET_COURIER_BY_USER, userID)
+}
+
+// Gets a single courier with the given query.
+func (r *mariaDBRepository) getCourier(ctx context.Context, query string, arg int) (*CourierOut, error) {
+	// Prepare SQL to get one courier.
+	stmt, err := r.mariadb.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Get one courier.
+	// If it's empty, return null.
+	courier, err := scanCourier(stmt.QueryRowContext(ctx, arg, "A"))
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return courier, nil
+}
+
+// Creates a single courier in the database.
+func (r *mariaDBRepository) CreateCourier(ctx context.Context, courier *Courier) (sql.Result, error) {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_CREATE_COURIER)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Insert one courier.
+	result, err := stmt.ExecContext(ctx, courier.UserID, courier.FullName, courier.Phone, courier.VehicleType, courier.CapacityKg, courier.ActiveZone,
+		courier.CourierStatus, courier.Application, courier.CreatedUser, courier.CreatedAt, courier.UpdatedUser, courier.UpdatedAt, courier.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return result, nil
+}
+
+// Updates a single courier in the database.
+func (r *mariaDBRepository) UpdateCourier(ctx context.Context, courierID int, courier *Courier) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_COURIER)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one courier.
+	_, err = stmt.ExecContext(ctx, courier.FullName, courier.Phone, courier.VehicleType, courier.CapacityKg, courier.ActiveZone, courier.CourierStatus,
+		courier.UpdatedUser, courier.UpdatedAt, courierID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Deletes a single courier in the database.
+func (r *mariaDBRepository) DeleteCourier(ctx context.Context, courierID int, courier *Courier) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_DELETE_COURIER)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Delete one courier.
+	_, err = stmt.ExecContext(ctx, courier.Status, courier.UpdatedUser, courier.UpdatedAt, courierID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Stores the last known position of a single courier in the database.
+// Older positions never replace a newer one.
+func (r *mariaDBRepository) UpdateCourierLocation(ctx context.Context, courierID int, courier *Courier) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_COURIER_LOCATION)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one courier.
+	_, err = stmt.ExecContext(ctx, courier.LastLat, courier.LastLng, courier.LastLocationAt, courierID, courier.LastLocationAt)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
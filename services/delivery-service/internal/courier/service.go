@@ -0,0 +1,340 @@
+package courier
+
+import (
+	"context"
+	"delivery-service/internal/user"
+	"delivery-service/internal/utils"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Returned when the user of the token has no courier.
+var ErrNotCourier = errors.New("the user is not registered as a courier")
+
+// Returned when a newer location of the courier is already stored.
+var ErrStaleLocation = errors.New("a newer location of the courier is already stored")
+
+// Returned when a location is recorded after the current time.
+var ErrFutureLocation = errors.New("the location can not be recorded in the future")
+
+// Returned when the user of a new courier belongs to another application.
+var ErrForeignUser = errors.New("the user does not belong to the application")
+
+// Implementation of the repository in this service.
+type courierService struct {
+	courierRepository  CourierRepository
+	locationRepository LocationRepository
+	userRepository     user.UserRepository
+}
+
+// Create a new 'service' or 'use-case' for 'Courier' entity.
+func NewCourierService(r CourierRepository, l LocationRepository, u user.UserRepository) CourierService {
+	return &courierService{
+		courierRepository:  r,
+		locationRepository: l,
+		userRepository:     u,
+	}
+}
+
+// Implementation of 'GetCouriers'.
+func (s *courierService) GetCouriers(ctx context.Context, application string) (*[]CourierOut, error) {
+	return s.courierRepository.GetCouriers(ctx, application)
+}
+
+// Implementation of 'GetCourier'.
+func (s *courierService) GetCourier(ctx context.Context, courierID int, application string) (*CourierOut, error) {
+	searchedCourier, err := s.courierRepository.GetCourier(ctx, courierID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Couriers of other applications do not exist for the caller.
+	if searchedCourier == nil || searchedCourier.Application != application {
+		return nil, nil
+	}
+
+	return searchedCourier, nil
+}
+
+// Implementation of 'CreateCourier'.
+func (s *courierService) CreateCourier(ctx context.Context, courierInsert *CourierInsert) (*CourierOut, error) {
+	// Only users of the application can be registered as its couriers.
+	searchedUser, err := s.userRepository.GetUser(ctx, courierInsert.UserID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "user information could not be retrieved")
+	}
+	if searchedUser == nil || searchedUser.Application != courierInsert.Application {
+		return nil, ErrForeignUser
+	}
+
+	// A user can only drive as one courier.
+	searchedCourier, err := s.courierRepository.GetCourierByUser(ctx, courierInsert.UserID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedCourier != nil {
+		return nil, fmt.Errorf("the user is already registered as a courier")
+	}
+
+	// Set initialized default data for courier.
+	courier := &Courier{
+		UserID:        courierInsert.UserID,
+		FullName:      courierInsert.FullName,
+		Phone:         courierInsert.Phone,
+		VehicleType:   courierInsert.VehicleType,
+		CapacityKg:    courierInsert.CapacityKg,
+		ActiveZone:    courierInsert.ActiveZone,
+		CourierStatus: COURIER_STATUS_AVAILABLE,
+		Application:   courierInsert.Application,
+		CreatedUser:   courierInsert.CreatedUser,
+		CreatedAt:     time.Now(),
+		Status:        "A",
+	}
+
+	// Pass to the repository layer.
+	result, err := s.courierRepository.CreateCourier(ctx, courier)
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not insert record")
+	}
+
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not get inserted id")
+	}
+
+	return s.courierRepository.GetCourier(ctx, int(insertedID))
+}
+
+// Implementation of 'UpdateCourier'.
+func (s *courierService) UpdateCourier(ctx context.Context, courierID int, application string, courierUpdate *CourierUpdate) (*CourierOut, error) {
+	// Check if courier exists.
+	searchedCourier, err := s.GetCourier(ctx, courierID, application)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedCourier == nil {
+		return nil, fmt.Errorf("There is no courier with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	courier := &Courier{
+		FullName:      courierUpdate.FullName,
+		Phone:         courierUpdate.Phone,
+		VehicleType:   courierUpdate.VehicleType,
+		CapacityKg:    courierUpdate.CapacityKg,
+		ActiveZone:    courierUpdate.ActiveZone,
+		CourierStatus: courierUpdate.CourierStatus,
+		UpdatedUser:   courierUpdate.UpdatedUser,
+		UpdatedAt:     time.Now(),
+	}
+
+	// Pass to the repository layer.
+	if err = s.courierRepository.UpdateCourier(ctx, courierID, courier); err != nil {
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	return s.courierRepository.GetCourier(ctx, courierID)
+}
+
+// Implementation of 'DeleteCourier'.
+func (s *courierService) DeleteCourier(ctx context.Context, courierID int, application string, courierDelete *CourierDelete) error {
+	// Check if courier exists.
+	searchedCourier, err := s.GetCourier(ctx, courierID, application)
+	if err != nil {
+		return utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedCourier == nil {
+		return fmt.Errorf("There is no courier with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	courier := &Courier{
+		UpdatedUser: courierDelete.UpdatedUser,
+		UpdatedAt:   time.Now(),
+		Status:      "I",
+	}
+
+	// Pass to the repository layer.
+	if err = s.courierRepository.DeleteCourier(ctx, courierID, courier); err != nil {
+		return utils.FailOnError(err, "could not delete record")
+	}
+
+	return nil
+}
+
+// Implementation of 'ReportLocation'.
+func (s *courierService) ReportLocation(ctx context.Context, userID int, courierLocation *CourierLocation) (*CourierPosition, error) {
+	// Only couriers can report a location.
+	searchedCourier, err := s.courierRepository.GetCourierByUser(ctx, userID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedCourier == nil {
+		return nil, ErrNotCourier
+	}
+
+	// A ping from the future is a wrong clock on the phone.
+	if courierLocation.RecordedAt.After(time.Now().Add(time.Minute)) {
+		return nil, ErrFutureLocation
+	}
+
+	position := &CourierPosition{
+		CourierID:  searchedCourier.ID,
+		Lat:        *courierLocation.Lat,
+		Lng:        *courierLocation.Lng,
+		RecordedAt: courierLocation.RecordedAt,
+	}
+
+	// Pass to the live location store, it is persisted later by the flusher.
+	saved, err := s.locationRepository.SaveLocation(ctx, position)
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not save location")
+	}
+	if !saved {
+		return nil, ErrStaleLocation
+	}
+
+	return position, nil
+}
+
+// Implementation of 'GetCouriersNearby'.
+func (s *courierService) GetCouriersNearby(ctx context.Context, courierNearbyQuery *CourierNearbyQuery) (*[]CourierNearbyOut, error) {
+	limit := courierNearbyQuery.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	// Positions older than this are not live anymore, they are left out before the limit is applied.
+	maxAgeSecondsCount, _ := strconv.Atoi(os.Getenv("COURIER_LOCATION_MAX_AGE"))
+	maxAge := time.Second * time.Duration(maxAgeSecondsCount)
+
+	positions, err := s.locationRepository.GetLocationsNearby(ctx, *courierNearbyQuery.Lat, *courierNearbyQuery.Lng, courierNearbyQuery.RadiusKm, maxAge, limit)
+	if err != nil {
+		return nil, utils.FailOnError(err, "locations could not be retrieved")
+	}
+
+	// Get every courier of the positions at once.
+	courierIDs := make([]int, 0, len(positions))
+	for _, position := range positions {
+		courierIDs = append(courierIDs, position.CourierID)
+	}
+	searchedCouriers, err := s.courierRepository.GetCouriersByIDs(ctx, courierIDs, courierNearbyQuery.Application)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	couriersByID := make(map[int]*CourierOut, len(*searchedCouriers))
+	for i := range *searchedCouriers {
+		couriersByID[(*searchedCouriers)[i].ID] = &(*searchedCouriers)[i]
+	}
+
+	couriers := make([]CourierNearbyOut, 0, len(positions))
+	for _, position := range positions {
+		// Skip couriers deleted after their last ping and those of other applications.
+		searchedCourier := couriersByID[position.CourierID]
+		if searchedCourier == nil {
+			continue
+		}
+
+		couriers = append(couriers, CourierNearbyOut{
+			CourierOut: *searchedCourier,
+			Position:   position,
+		})
+	}
+
+	return &couriers, nil
+}
+
+// Implementation of 'GetCourierPosition'.
+func (s *courierService) GetCourierPosition(ctx context.Context, courierID int, application string) (*CourierPosition, error) {
+	// Only couriers of the application can be followed.
+	searchedCourier, err := s.GetCourier(ctx, courierID, application)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedCourier == nil {
+		return nil, nil
+	}
+
+	// The live location comes first.
+	position, err := s.locationRepository.GetLocation(ctx, courierID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "location could not be retrieved")
+	}
+	if position != nil {
+		return position, nil
+	}
+
+	// Fall back to the last persisted position.
+	if searchedCourier.LastLat == nil || searchedCourier.LastLng == nil {
+		return nil, nil
+	}
+
+	position = &CourierPosition{
+		CourierID: courierID,
+		Lat:       *searchedCourier.LastLat,
+		Lng:       *searchedCourier.LastLng,
+	}
+	if searchedCourier.LastLocationAt != nil {
+		position.RecordedAt = *searchedCourier.LastLocationAt
+	}
+
+	return position, nil
+}
+
+// Implementation of 'StartLocationFlusher'.
+// Launches the goroutine that persists the live locations to the database.
+func (s *courierService) StartLocationFlusher() {
+	// Define flusher settings.
+	intervalSecondsCount, _ := strconv.Atoi(os.Getenv("COURIER_LOCATION_FLUSH_INTERVAL"))
+	if intervalSecondsCount <= 0 {
+		intervalSecondsCount = 30
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Second * time.Duration(intervalSecondsCount))
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.flushLocations(context.Background())
+		}
+	}()
+}
+
+// Persists every location received since the last flush.
+func (s *courierService) flushLocations(ctx context.Context) {
+	for {
+		positions, err := s.locationRepository.PopPendingLocations(ctx, 500)
+		if err != nil {
+			log.Printf("Oops... Courier locations could not be retrieved! Reason: %v", err)
+			return
+		}
+		if len(positions) == 0 {
+			return
+		}
+
+		var failedIDs []int
+		for _, position := range positions {
+			courier := &Courier{
+				LastLat:        &position.Lat,
+				LastLng:        &position.Lng,
+				LastLocationAt: &position.RecordedAt,
+			}
+			if err = s.courierRepository.UpdateCourierLocation(ctx, position.CourierID, courier); err != nil {
+				log.Printf("Oops... Location of courier %d could not be persisted! Reason: %v", position.CourierID, err)
+				failedIDs = append(failedIDs, position.CourierID)
+			}
+		}
+
+		// Keep the failed ones for the next flush.
+		if len(failedIDs) > 0 {
+			if err = s.locationRepository.MarkLocationsPending(ctx, failedIDs); err != nil {
+				log.Printf("Oops... Courier locations could not be kept for retry! Reason: %v", err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package geocoding
+
+import (
+	"context"
+	"delivery-service/internal/address"
+)
+
+// Coordinates struct to describe a point found for an address.
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Our geocoders will implement these methods.
+// Both return null when nothing is found.
+type Geocoder interface {
+	Geocode(ctx context.Context, a *address.Address) (*Coordinates, error)
+	ReverseGeocode(ctx context.Context, lat float64, lng float64) (*address.Address, error)
+}
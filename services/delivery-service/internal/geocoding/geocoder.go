@@ -0,0 +1,347 @@
+package geocoding
+
+import (
+	"context"
+	"crypto/sha1"
+	"delivery-service/internal/address"
+	"delivery-service/internal/utils"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NewGeocoder func for building the configured geocoder.
+func NewGeocoder(g string) (Geocoder, error) {
+	// Switch given names.
+	switch g {
+	case "http":
+		return &httpGeocoder{
+			url:    strings.TrimRight(os.Getenv("GEOCODER_URL"), "/"),
+			apiKey: os.Getenv("GEOCODER_API_KEY"),
+			client: &http.Client{Timeout: time.Second * 10},
+		}, nil
+	case "file":
+		return newGazetteerGeocoder(os.Getenv("GEOCODER_GAZETTEER_FILE"))
+	case "":
+		return &noopGeocoder{}, nil
+	default:
+		// Return error message.
+		return nil, fmt.Errorf("geocoder '%v' is not supported", g)
+	}
+}
+
+// Finds nothing, clients must send both the address and the coordinates.
+type noopGeocoder struct{}
+
+func (g *noopGeocoder) Geocode(ctx context.Context, a *address.Address) (*Coordinates, error) {
+	return nil, nil
+}
+
+func (g *noopGeocoder) ReverseGeocode(ctx context.Context, lat float64, lng float64) (*address.Address, error) {
+	return nil, nil
+}
+
+// Asks a provider with a Nominatim compatible API.
+// The API key, if any, is sent as the 'key' parameter.
+type httpGeocoder struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// Result of a search of the provider.
+type httpSearchResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+// Result of a reverse search of the provider.
+type httpReverseResult struct {
+	Address struct {
+		Road        string `json:"road"`
+		HouseNumber string `json:"house_number"`
+		Suburb      string `json:"suburb"`
+		City        string `json:"city"`
+		Town        string `json:"town"`
+		Village     string `json:"village"`
+		State       string `json:"state"`
+		Postcode    string `json:"postcode"`
+		CountryCode string `json:"country_code"`
+	} `json:"address"`
+}
+
+// Gets the coordinates of an address.
+func (g *httpGeocoder) Geocode(ctx context.Context, a *address.Address) (*Coordinates, error) {
+	query := url.Values{}
+	query.Set("format", "jsonv2")
+	query.Set("limit", "1")
+	query.Set("street", strings.TrimSpace(a.Number+" "+a.Street))
+	query.Set("city", a.City)
+	query.Set("state", a.Region)
+	query.Set("postalcode", a.PostalCode)
+	query.Set("countrycodes", strings.ToLower(a.CountryCode))
+
+	var results []httpSearchResult
+	if err := g.get(ctx, "/search", query, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, err
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Coordinates{Lat: lat, Lng: lng}, nil
+}
+
+// Gets the address of a point.
+func (g *httpGeocoder) ReverseGeocode(ctx context.Context, lat float64, lng float64) (*address.Address, error) {
+	query := url.Values{}
+	query.Set("format", "jsonv2")
+	query.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	query.Set("lon", strconv.FormatFloat(lng, 'f', -1, 64))
+
+	result := &httpReverseResult{}
+	if err := g.get(ctx, "/reverse", query, result); err != nil {
+		return nil, err
+	}
+	if result.Address.Road == "" || result.Address.CountryCode == "" {
+		return nil, nil
+	}
+
+	// Small places have no city.
+	city := result.Address.City
+	if city == "" {
+		city = result.Address.Town
+	}
+	if city == "" {
+		city = result.Address.Village
+	}
+
+	return &address.Address{
+		Street:      result.Address.Road,
+		Number:      result.Address.HouseNumber,
+		District:    result.Address.Suburb,
+		City:        city,
+		Region:      result.Address.State,
+		CountryCode: result.Address.CountryCode,
+		PostalCode:  result.Address.Postcode,
+	}, nil
+}
+
+// Calls one endpoint of the provider and decodes its JSON answer.
+func (g *httpGeocoder) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	if g.apiKey != "" {
+		query.Set("key", g.apiKey)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, g.url+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", "delivery-service")
+
+	response, err := g.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("geocoding provider answered with status %d", response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(out)
+}
+
+// Looks up a fixed list of places read from a JSON file.
+// Used for tests and local runs.
+type gazetteerGeocoder struct {
+	entries       []gazetteerEntry
+	maxDistanceKm float64
+}
+
+// A place of the gazetteer file.
+type gazetteerEntry struct {
+	Address *address.Address `json:"address"`
+	Lat     float64          `json:"lat"`
+	Lng     float64          `json:"lng"`
+}
+
+// Reads the gazetteer, a JSON array of entries.
+func newGazetteerGeocoder(path string) (Geocoder, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []gazetteerEntry
+	if err = json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("gazetteer file '%v' is not valid: %v", path, err)
+	}
+	for i := range entries {
+		if entries[i].Address == nil {
+			return nil, fmt.Errorf("gazetteer entry %d has no address", i)
+		}
+		if err = address.Normalize(entries[i].Address); err != nil {
+			return nil, fmt.Errorf("gazetteer entry %d: %v", i, err)
+		}
+	}
+
+	// Reverse lookups only match places this close to the point.
+	maxDistanceKm, err := strconv.ParseFloat(os.Getenv("GEOCODER_GAZETTEER_MAX_KM"), 64)
+	if err != nil || maxDistanceKm <= 0 {
+		maxDistanceKm = 0.5
+	}
+
+	return &gazetteerGeocoder{entries: entries, maxDistanceKm: maxDistanceKm}, nil
+}
+
+// Gets the coordinates of the entry with the same address.
+func (g *gazetteerGeocoder) Geocode(ctx context.Context, a *address.Address) (*Coordinates, error) {
+	key := addressKey(a)
+	for _, entry := range g.entries {
+		if addressKey(entry.Address) == key {
+			return &Coordinates{Lat: entry.Lat, Lng: entry.Lng}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Gets the address of the closest entry.
+func (g *gazetteerGeocoder) ReverseGeocode(ctx context.Context, lat float64, lng float64) (*address.Address, error) {
+	var closest *gazetteerEntry
+	closestDistance := 0.0
+	for i := range g.entries {
+		distance := utils.Haversine(lat, lng, g.entries[i].Lat, g.entries[i].Lng)
+		if distance <= g.maxDistanceKm && (closest == nil || distance < closestDistance) {
+			closest, closestDistance = &g.entries[i], distance
+		}
+	}
+	if closest == nil {
+		return nil, nil
+	}
+
+	found := *closest.Address
+	return &found, nil
+}
+
+// Keeps the results of another geocoder in Redis.
+// Only found results are kept, so a place added to the provider is found on the next try.
+// An unavailable cache falls back to the geocoder.
+type cachedGeocoder struct {
+	next   Geocoder
+	redis  *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// Create a geocoder that caches the results of 'g'.
+func NewCachedGeocoder(g Geocoder, redisConnection *redis.Client) Geocoder {
+	prefix := os.Getenv("GEOCODER_CACHE_KEY")
+	if prefix == "" {
+		prefix = "delivery-service.geocoding"
+	}
+
+	ttlSecondsCount, _ := strconv.Atoi(os.Getenv("GEOCODER_CACHE_TTL"))
+	if ttlSecondsCount <= 0 {
+		ttlSecondsCount = 604800
+	}
+
+	return &cachedGeocoder{
+		next:   g,
+		redis:  redisConnection,
+		prefix: prefix,
+		ttl:    time.Second * time.Duration(ttlSecondsCount),
+	}
+}
+
+// Gets the coordinates of an address, from the cache if possible.
+func (g *cachedGeocoder) Geocode(ctx context.Context, a *address.Address) (*Coordinates, error) {
+	key := g.prefix + ".forward." + addressKey(a)
+
+	coordinates := &Coordinates{}
+	if g.load(ctx, key, coordinates) {
+		return coordinates, nil
+	}
+
+	coordinates, err := g.next.Geocode(ctx, a)
+	if err != nil || coordinates == nil {
+		return coordinates, err
+	}
+
+	g.store(ctx, key, coordinates)
+	return coordinates, nil
+}
+
+// Gets the address of a point, from the cache if possible.
+// Points are rounded to about one meter.
+func (g *cachedGeocoder) ReverseGeocode(ctx context.Context, lat float64, lng float64) (*address.Address, error) {
+	key := fmt.Sprintf("%s.reverse.%.5f,%.5f", g.prefix, lat, lng)
+
+	found := &address.Address{}
+	if g.load(ctx, key, found) {
+		return found, nil
+	}
+
+	found, err := g.next.ReverseGeocode(ctx, lat, lng)
+	if err != nil || found == nil {
+		return found, err
+	}
+
+	g.store(ctx, key, found)
+	return found, nil
+}
+
+// Reads a cached result, false when there is none.
+func (g *cachedGeocoder) load(ctx context.Context, key string, out interface{}) bool {
+	content, err := g.redis.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return false
+	}
+	if err == nil {
+		err = json.Unmarshal(content, out)
+	}
+	if err != nil {
+		log.Printf("Oops... Geocoding cache could not be read! Reason: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// Writes a result to the cache.
+func (g *cachedGeocoder) store(ctx context.Context, key string, value interface{}) {
+	content, err := json.Marshal(value)
+	if err == nil {
+		err = g.redis.Set(ctx, key, content, g.ttl).Err()
+	}
+	if err != nil {
+		log.Printf("Oops... Geocoding result could not be cached! Reason: %v", err)
+	}
+}
+
+// Identifies an address regardless of case, hashed to keep cache keys short.
+func addressKey(a *address.Address) string {
+	sum := sha1.Sum([]byte(strings.ToLower(a.Format())))
+	return hex.EncodeToString(sum[:])
+}
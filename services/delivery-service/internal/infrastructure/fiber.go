@@ -1,13 +1,27 @@
 package infrastructure
 
 import (
+	"delivery-service/internal/blob"
+	"delivery-service/internal/calendar"
 	"delivery-service/internal/configs"
+	"delivery-service/internal/contact"
+	"delivery-service/internal/courier"
+	"delivery-service/internal/geocoding"
+	"delivery-service/internal/job"
 	"delivery-service/internal/middleware"
 	"delivery-service/internal/misc"
+	"delivery-service/internal/notification"
+	"delivery-service/internal/outbox"
 	"delivery-service/internal/package_size"
+	"delivery-service/internal/product"
+	"delivery-service/internal/service_level"
 	"delivery-service/internal/shipping_order"
+	"delivery-service/internal/station"
 	"delivery-service/internal/user"
 	"delivery-service/internal/utils"
+	"delivery-service/internal/verification"
+	"delivery-service/internal/webhook"
+	"delivery-service/internal/zone"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"log"
@@ -32,19 +46,113 @@ func Run() {
 	// Middlewares.
 	middleware.FiberMiddleware(app) // Register Fiber's middleware for app.
 
+	// Connect to Redis for the live data.
+	redisConnection, err := utils.RedisConnection()
+	if err != nil {
+		log.Fatalf("Redis connection error: %v", err)
+	}
+
 	// Create repositories.
 	userRepository := user.NewUserRepository(mariadb)
-	shippingOrderRepository := shipping_order.NewShippingOrderRepository(mariadb)
+	outboxRepository := outbox.NewOutboxRepository(mariadb)
+	shippingOrderRepository := shipping_order.NewShippingOrderRepository(mariadb, outboxRepository)
 	packageSizeRepository := package_size.NewPackageSizeRepository(mariadb)
+	productRepository := product.NewProductRepository(mariadb)
+	serviceLevelRepository := service_level.NewServiceLevelRepository(mariadb)
+	jobRepository := job.NewJobRepository(mariadb)
+	webhookRepository := webhook.NewWebhookRepository(mariadb)
+	courierRepository := courier.NewCourierRepository(mariadb)
+	locationRepository := courier.NewLocationRepository(redisConnection)
+	contactRepository := contact.NewContactRepository(mariadb)
+	verificationRepository := verification.NewVerificationRepository(redisConnection)
+	stationRepository := station.NewStationRepository(mariadb)
+	zoneRepository, err := zone.NewZoneRepository(os.Getenv("SERVICE_ZONES_FILE"))
+	if err != nil {
+		log.Fatalf("Service zones error: %v", err)
+	}
+	calendarRepository, err := calendar.NewCalendarRepository(os.Getenv("OPERATING_CALENDARS_FILE"))
+	if err != nil {
+		log.Fatalf("Operating calendars error: %v", err)
+	}
+
+	// Create the channels of our notifications.
+	emailNotifier, err := notification.NewNotifier(os.Getenv("NOTIFICATION_EMAIL_SINK"))
+	if err != nil {
+		log.Fatalf("Notifier error: %v", err)
+	}
+	smsNotifier, err := notification.NewNotifier(os.Getenv("NOTIFICATION_SMS_SINK"))
+	if err != nil {
+		log.Fatalf("Notifier error: %v", err)
+	}
+
+	// Create the geocoder of the addresses, its results are cached in Redis.
+	geocoder, err := geocoding.NewGeocoder(os.Getenv("GEOCODER"))
+	if err != nil {
+		log.Fatalf("Geocoder error: %v", err)
+	}
+	geocoder = geocoding.NewCachedGeocoder(geocoder, redisConnection)
+
+	// Create the store of uploaded files.
+	blobStore, err := blob.NewStore(os.Getenv("BLOB_STORE"))
+	if err != nil {
+		log.Fatalf("Blob store error: %v", err)
+	}
 
 	// Create all of our services.
 	userService := user.NewUserService(userRepository)
-	shippingOrderService := shipping_order.NewShippingOrderService(shippingOrderRepository, packageSizeRepository)
+	notificationService := notification.NewNotificationService(emailNotifier, smsNotifier)
+	shippingOrderService := shipping_order.NewShippingOrderService(shippingOrderRepository, shipping_order.ShippingOrderDependencies{
+		PackageSizeRepository:  packageSizeRepository,
+		ProductRepository:      productRepository,
+		ServiceLevelRepository: serviceLevelRepository,
+		ZoneRepository:         zoneRepository,
+		Geocoder:               geocoder,
+		ContactRepository:      contactRepository,
+		CourierRepository:      courierRepository,
+		LocationRepository:     locationRepository,
+		NotificationService:    notificationService,
+		BlobStore:              blobStore,
+		CalendarRepository:     calendarRepository,
+		VerificationRepository: verificationRepository,
+		StationRepository:      stationRepository,
+	})
+	jobService := job.NewJobService(jobRepository, shippingOrderService)
+	webhookService := webhook.NewWebhookService(webhookRepository)
+	courierService := courier.NewCourierService(courierRepository, locationRepository, userRepository)
+	zoneService := zone.NewZoneService(zoneRepository)
+	contactService := contact.NewContactService(contactRepository)
+	productService := product.NewProductService(productRepository)
+	stationService := station.NewStationService(stationRepository)
+
+	// Create the sink of our domain events.
+	outboxPublisher, err := outbox.NewPublisher(os.Getenv("OUTBOX_SINK"))
+	if err != nil {
+		log.Fatalf("Outbox sink error: %v", err)
+	}
+
+	// Start background workers.
+	jobService.StartWorkers()
+	webhookService.StartDispatcher()
+	courierService.StartLocationFlusher()
+	outbox.NewRelay(outboxRepository, outboxPublisher, webhookService).Start()
 
 	// Prepare our endpoints for the API.
 	misc.NewMiscHandler(app.Group("/api/v1"))
 	user.NewUserHandler(app.Group("/api/v1/users"), userService)
 	shipping_order.NewShippingOrderHandler(app.Group("/api/v1/order"), shippingOrderService)
+	job.NewJobHandler(app.Group("/api/v1/jobs"), jobService)
+	webhook.NewWebhookHandler(app.Group("/api/v1/webhooks"), webhookService)
+	courierRoute := app.Group("/api/v1/couriers")
+	courier.NewCourierHandler(courierRoute, courierService)
+	shipping_order.NewCourierRouteHandler(courierRoute, shippingOrderService)
+	shipping_order.NewPickupHandler(app.Group("/api/v1/pickups"), shippingOrderService)
+	shipping_order.NewTrackingHandler(app.Group("/api/v1/tracking"), shippingOrderService)
+	zone.NewZoneHandler(app.Group("/api/v1/zones"), zoneService)
+	contact.NewContactHandler(app.Group("/api/v1/contacts"), contactService)
+	product.NewProductHandler(app.Group("/api/v1/products"), productService)
+	stationRoute := app.Group("/api/v1/stations")
+	station.NewStationHandler(stationRoute, stationService)
+	shipping_order.NewStationInventoryHandler(stationRoute, shippingOrderService)
 
 	// Prepare an endpoint for 'Not Found'.
 	app.All("*", func(c *fiber.Ctx) error {
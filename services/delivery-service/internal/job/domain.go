@@ -0,0 +1,125 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"delivery-service/internal/shipping_order"
+)
+
+// Types of bulk operations a job can run.
+const (
+	JOB_TYPE_CREATE_ORDER        = "create_order"
+	JOB_TYPE_UPDATE_ORDER_STATUS = "update_order_status"
+	JOB_TYPE_CANCEL_ORDER        = "cancel_order"
+)
+
+// States of a job and of each one of its items.
+const (
+	JOB_STATUS_PENDING   = "pending"
+	JOB_STATUS_RUNNING   = "running"
+	JOB_STATUS_COMPLETED = "completed"
+	JOB_STATUS_FAILED    = "failed"
+)
+
+// Job struct to describe Job object.
+type Job struct {
+	ID             int       `db:"id"`
+	JobType        string    `db:"job_type"`
+	JobStatus      string    `db:"job_status"`
+	TotalItems     int       `db:"total_items"`
+	ProcessedItems int       `db:"processed_items"`
+	FailedItems    int       `db:"failed_items"`
+	UserID         int       `db:"user_id"`
+	Application    string    `db:"application"`
+	CreatedUser    string    `db:"created_user"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedUser    string    `db:"updated_user"`
+	UpdatedAt      time.Time `db:"updated_at"`
+	Status         string    `db:"status"`
+}
+
+// JobItem struct to describe a single operation of a job.
+type JobItem struct {
+	ID           int       `db:"id"`
+	JobID        int       `db:"job_id"`
+	ItemIndex    int       `db:"item_index"`
+	Payload      string    `db:"payload"`
+	ItemStatus   string    `db:"item_status"`
+	Result       string    `db:"result"`
+	ErrorMessage string    `db:"error_message"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// JobInsert struct to describe register a new job.
+type JobInsert struct {
+	JobType     string            `json:"jobType" validate:"required,eq=create_order|eq=update_order_status|eq=cancel_order"`
+	Items       []json.RawMessage `json:"items" validate:"required,min=1,max=5000"`
+	UserID      int               `json:"-"`
+	Application string            `json:"-"`
+	CreatedUser string            `json:"createdUser" validate:"required,lte=200"`
+}
+
+// JobUpdateOrderStatusItem struct to describe an item of an 'update_order_status' job.
+type JobUpdateOrderStatusItem struct {
+	ShippingOrderID int `json:"shippingOrderId" validate:"required,gt=0"`
+	shipping_order.ShippingOrderUpdate
+}
+
+// JobCancelOrderItem struct to describe an item of a 'cancel_order' job.
+type JobCancelOrderItem struct {
+	ShippingOrderID int `json:"shippingOrderId" validate:"required,gt=0"`
+	shipping_order.ShippingOrderCancel
+}
+
+type JobItemOut struct {
+	ID           int             `json:"id"`
+	ItemIndex    int             `json:"itemIndex"`
+	ItemStatus   string          `json:"itemStatus"`
+	Result       json.RawMessage `json:"result,omitempty"`
+	ErrorMessage string          `json:"errorMessage,omitempty"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+type JobOut struct {
+	ID             int           `json:"id"`
+	JobType        string        `json:"jobType"`
+	JobStatus      string        `json:"jobStatus"`
+	TotalItems     int           `json:"totalItems"`
+	ProcessedItems int           `json:"processedItems"`
+	FailedItems    int           `json:"failedItems"`
+	Items          *[]JobItemOut `json:"items,omitempty"`
+	UserID         int           `json:"userId"`
+	Application    string        `json:"application"`
+	CreatedUser    string        `json:"created_user"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedUser    string        `json:"updated_user"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	Status         string        `json:"status"`
+}
+
+// Returned when the workers already have as many jobs waiting as the queue holds.
+var ErrQueueFull = errors.New("there are too many jobs waiting, please try again later")
+
+// Our repository will implement these methods.
+type JobRepository interface {
+	GetJob(ctx context.Context, jobID int) (*JobOut, error)
+	GetJobItems(ctx context.Context, jobID int) (*[]JobItemOut, error)
+	GetUnfinishedJobs(ctx context.Context) (*[]Job, error)
+	GetPendingJobItems(ctx context.Context, jobID int) (*[]JobItem, error)
+	CreateJob(ctx context.Context, job *Job, jobItems *[]JobItem) (sql.Result, error)
+	UpdateJob(ctx context.Context, jobID int, job *Job) error
+	StartJobItem(ctx context.Context, jobItem *JobItem) error
+	CompleteJobItem(ctx context.Context, jobID int, jobItem *JobItem) error
+}
+
+// Our use-case or service will implement these methods.
+type JobService interface {
+	GetJob(ctx context.Context, jobID int, application string, userID int) (*JobOut, error)
+	CreateJob(ctx context.Context, jobInsert *JobInsert) (*JobOut, error)
+	StartWorkers()
+}
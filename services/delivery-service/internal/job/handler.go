@@ -0,0 +1,134 @@
+package job
+
+import (
+	"context"
+	"delivery-service/internal/middleware"
+	"delivery-service/internal/utils"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Represents our handler with our use-case / service.
+type JobHandler struct {
+	jobService JobService
+}
+
+// Creates a new handler.
+func NewJobHandler(jobRoute fiber.Router, js JobService) {
+	// Create a handler based on our created service / use-case.
+	handler := &JobHandler{
+		jobService: js,
+	}
+
+	// We will restrict this route with our JWT middleware.
+	jobRoute.Use(middleware.JWTProtected(), middleware.ExtractTokenMetadata)
+
+	// Declare routing endpoints for general routes.
+	jobRoute.Post("", handler.createJob)
+	jobRoute.Get("/:jobID", handler.getJob)
+}
+
+// Gets a single job with the results of its items.
+func (h *JobHandler) getJob(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedJobID, err := c.ParamsInt("jobID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid job ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get one job of the user.
+	job, err := h.jobService.GetJob(customContext, targetedJobID, c.Locals("application").(string), c.Locals("userid").(int))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if job == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("job of ID {%d} does not exist.", targetedJobID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "job obtained succesfully",
+		"http_code": fiber.StatusOK,
+		"data":      job,
+	})
+}
+
+// Submits a bulk operation to be processed in background.
+func (h *JobHandler) createJob(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables and Create a new job struct.
+	jobInsert := &JobInsert{}
+
+	// Parse request body.
+	if err := c.BodyParser(jobInsert); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Job model.
+	validate := utils.NewValidator()
+
+	// Validate job fields.
+	if err := validate.Struct(jobInsert); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// The job runs on behalf of the user and application of the token.
+	jobInsert.UserID = c.Locals("userid").(int)
+	jobInsert.Application = c.Locals("application").(string)
+
+	// Create one job.
+	job, err := h.jobService.CreateJob(customContext, jobInsert)
+	if err != nil && errors.Is(err, ErrQueueFull) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusServiceUnavailable,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result, the job keeps running after the response.
+	return c.Status(fiber.StatusAccepted).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Job has been accepted successfully!",
+		"http_code": fiber.StatusAccepted,
+		"data":      job,
+	})
+}
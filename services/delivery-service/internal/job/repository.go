@@ -0,0 +1,250 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Queries that we will use.
+const (
+	QUERY_GET_JOB = "SELECT id, job_type, job_status, total_items, processed_items, failed_items, user_id, application, created_user, created_at, updated_user, updated_at, status " +
+		"FROM job WHERE id = ? and status = ?"
+	QUERY_GET_JOB_ITEMS = "SELECT id, item_index, item_status, result, error_message, updated_at " +
+		"FROM job_item WHERE job_id = ? order by item_index asc"
+	QUERY_GET_UNFINISHED_JOBS = "SELECT id, job_type, job_status, total_items, processed_items, failed_items, user_id, application, created_user, created_at, updated_user, updated_at, status " +
+		"FROM job WHERE job_status in (?, ?) and status = ? order by id asc"
+	QUERY_GET_PENDING_JOB_ITEMS = "SELECT id, job_id, item_index, payload, item_status, result, error_message, created_at, updated_at " +
+		"FROM job_item WHERE job_id = ? and item_status in (?, ?) order by item_index asc"
+	QUERY_CREATE_JOB = "INSERT INTO job (job_type, job_status, total_items, processed_items, failed_items, user_id, application, created_user, created_at, updated_user, updated_at, status) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_CREATE_JOB_ITEM = "INSERT INTO job_item (job_id, item_index, payload, item_status, result, error_message, created_at, updated_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_UPDATE_JOB          = "UPDATE job SET job_status = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_START_JOB_ITEM      = "UPDATE job_item SET item_status = ?, updated_at = ? WHERE id = ?"
+	QUERY_UPDATE_JOB_ITEM     = "UPDATE job_item SET item_status = ?, result = ?, error_message = ?, updated_at = ? WHERE id = ?"
+	QUERY_UPDATE_JOB_PROGRESS = "UPDATE job SET processed_items = processed_items + 1, failed_items = failed_items + ?, updated_at = ? WHERE id = ?"
+)
+
+// Represents that we will use MariaDB in order to implement the methods.
+type mariaDBRepository struct {
+	mariadb *sql.DB
+}
+
+// Create a new repository with MariaDB as the driver.
+func NewJobRepository(mariaDBConnection *sql.DB) JobRepository {
+	return &mariaDBRepository{
+		mariadb: mariaDBConnection,
+	}
+}
+
+// Gets a single job in the database.
+func (r *mariaDBRepository) GetJob(ctx context.Context, jobID int) (*JobOut, error) {
+	// Initialize variable.
+	job := &JobOut{}
+
+	// Prepare SQL to get one job.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_GET_JOB)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Get one job and insert it to the 'job' struct.
+	// If it's empty, return null.
+	err = stmt.QueryRowContext(ctx, jobID, "A").Scan(&job.ID, &job.JobType, &job.JobStatus, &job.TotalItems, &job.ProcessedItems, &job.FailedItems,
+		&job.UserID, &job.Application, &job.CreatedUser, &job.CreatedAt, &job.UpdatedUser, &job.UpdatedAt, &job.Status)
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return job, nil
+}
+
+// Gets the items of a job in the database.
+func (r *mariaDBRepository) GetJobItems(ctx context.Context, jobID int) (*[]JobItemOut, error) {
+	// Initialize variables.
+	var jobItems []JobItemOut
+
+	// Get all items.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_JOB_ITEMS, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'jobItems' array.
+	for res.Next() {
+		jobItem := &JobItemOut{}
+		result := ""
+		err = res.Scan(&jobItem.ID, &jobItem.ItemIndex, &jobItem.ItemStatus, &result, &jobItem.ErrorMessage, &jobItem.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if result != "" {
+			jobItem.Result = []byte(result)
+		}
+		jobItems = append(jobItems, *jobItem)
+	}
+
+	// Return all of our items.
+	return &jobItems, nil
+}
+
+// Gets the jobs that have not finished yet, used to resume them after a restart.
+func (r *mariaDBRepository) GetUnfinishedJobs(ctx context.Context) (*[]Job, error) {
+	// Initialize variables.
+	var jobs []Job
+
+	// Get all unfinished jobs.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_UNFINISHED_JOBS, JOB_STATUS_PENDING, JOB_STATUS_RUNNING, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'jobs' array.
+	for res.Next() {
+		job := &Job{}
+		err = res.Scan(&job.ID, &job.JobType, &job.JobStatus, &job.TotalItems, &job.ProcessedItems, &job.FailedItems,
+			&job.UserID, &job.Application, &job.CreatedUser, &job.CreatedAt, &job.UpdatedUser, &job.UpdatedAt, &job.Status)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	// Return all of our jobs.
+	return &jobs, nil
+}
+
+// Gets the items of a job that have not been processed yet, including the ones interrupted while running.
+func (r *mariaDBRepository) GetPendingJobItems(ctx context.Context, jobID int) (*[]JobItem, error) {
+	// Initialize variables.
+	var jobItems []JobItem
+
+	// Get all pending items.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_PENDING_JOB_ITEMS, jobID, JOB_STATUS_PENDING, JOB_STATUS_RUNNING)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'jobItems' array.
+	for res.Next() {
+		jobItem := &JobItem{}
+		err = res.Scan(&jobItem.ID, &jobItem.JobID, &jobItem.ItemIndex, &jobItem.Payload, &jobItem.ItemStatus, &jobItem.Result,
+			&jobItem.ErrorMessage, &jobItem.CreatedAt, &jobItem.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		jobItems = append(jobItems, *jobItem)
+	}
+
+	// Return all of our items.
+	return &jobItems, nil
+}
+
+// Creates a job together with all of its items in the database.
+func (r *mariaDBRepository) CreateJob(ctx context.Context, job *Job, jobItems *[]JobItem) (sql.Result, error) {
+	// Begin transaction, the job and its items are stored together.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Insert one job.
+	result, err := tx.ExecContext(ctx, QUERY_CREATE_JOB, job.JobType, job.JobStatus, job.TotalItems, job.ProcessedItems, job.FailedItems,
+		job.UserID, job.Application, job.CreatedUser, job.CreatedAt, job.UpdatedUser, job.UpdatedAt, job.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	jobID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare context to be used for the items.
+	stmt, err := tx.PrepareContext(ctx, QUERY_CREATE_JOB_ITEM)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Insert all items.
+	for _, jobItem := range *jobItems {
+		_, err = stmt.ExecContext(ctx, jobID, jobItem.ItemIndex, jobItem.Payload, jobItem.ItemStatus, jobItem.Result, jobItem.ErrorMessage,
+			jobItem.CreatedAt, jobItem.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return result, nil
+}
+
+// Updates the state of a job in the database.
+func (r *mariaDBRepository) UpdateJob(ctx context.Context, jobID int, job *Job) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_JOB)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one job.
+	_, err = stmt.ExecContext(ctx, job.JobStatus, job.UpdatedUser, job.UpdatedAt, jobID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Marks an item as running before its operation is done.
+func (r *mariaDBRepository) StartJobItem(ctx context.Context, jobItem *JobItem) error {
+	_, err := r.mariadb.ExecContext(ctx, QUERY_START_JOB_ITEM, jobItem.ItemStatus, jobItem.UpdatedAt, jobItem.ID)
+	return err
+}
+
+// Stores the outcome of an item and the progress of its job in the database.
+func (r *mariaDBRepository) CompleteJobItem(ctx context.Context, jobID int, jobItem *JobItem) error {
+	// Begin transaction, item and progress must not get out of sync.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Update one item.
+	_, err = tx.ExecContext(ctx, QUERY_UPDATE_JOB_ITEM, jobItem.ItemStatus, jobItem.Result, jobItem.ErrorMessage, jobItem.UpdatedAt, jobItem.ID)
+	if err != nil {
+		return err
+	}
+
+	// Update progress of the job.
+	failed := 0
+	if jobItem.ItemStatus == JOB_STATUS_FAILED {
+		failed = 1
+	}
+
+	_, err = tx.ExecContext(ctx, QUERY_UPDATE_JOB_PROGRESS, failed, time.Now(), jobID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return tx.Commit()
+}
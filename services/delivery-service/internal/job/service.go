@@ -0,0 +1,306 @@
+package job
+
+import (
+	"context"
+	"delivery-service/internal/shipping_order"
+	"delivery-service/internal/utils"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Implementation of the repository in this service.
+type jobService struct {
+	jobRepository        JobRepository
+	shippingOrderService shipping_order.ShippingOrderService
+	queue                chan int
+}
+
+// Create a new 'service' or 'use-case' for 'Job' entity.
+func NewJobService(r JobRepository, s shipping_order.ShippingOrderService) JobService {
+	// Define queue settings.
+	queueSize, _ := strconv.Atoi(os.Getenv("JOB_QUEUE_SIZE"))
+	if queueSize <= 0 {
+		queueSize = 100
+	}
+
+	return &jobService{
+		jobRepository:        r,
+		shippingOrderService: s,
+		queue:                make(chan int, queueSize),
+	}
+}
+
+// Implementation of 'GetJob'.
+func (s *jobService) GetJob(ctx context.Context, jobID int, application string, userID int) (*JobOut, error) {
+	job, err := s.jobRepository.GetJob(ctx, jobID)
+	if err != nil || job == nil {
+		return job, err
+	}
+
+	// Jobs are only seen by the user that submitted them.
+	if job.Application != application || job.UserID != userID {
+		return nil, nil
+	}
+
+	// Attach the per-item results.
+	jobItems, err := s.jobRepository.GetJobItems(ctx, jobID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "job items could not be retrieved")
+	}
+
+	job.Items = jobItems
+	return job, nil
+}
+
+// Implementation of 'CreateJob'.
+func (s *jobService) CreateJob(ctx context.Context, jobInsert *JobInsert) (*JobOut, error) {
+	// Jobs are only taken while the queue has room for them, a full queue is rejected before storing anything.
+	if len(s.queue) >= cap(s.queue) {
+		return nil, ErrQueueFull
+	}
+
+	// Create a new job struct.
+	job := &Job{}
+
+	// Set initialized default data for job:
+	job.JobType = jobInsert.JobType
+	job.JobStatus = JOB_STATUS_PENDING
+	job.TotalItems = len(jobInsert.Items)
+	job.UserID = jobInsert.UserID
+	job.Application = jobInsert.Application
+	job.CreatedUser = jobInsert.CreatedUser
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = job.CreatedAt
+	job.Status = "A"
+
+	// Every item keeps its raw payload, it is decoded by the workers.
+	jobItems := make([]JobItem, 0, len(jobInsert.Items))
+	for i, item := range jobInsert.Items {
+		jobItems = append(jobItems, JobItem{
+			ItemIndex:  i,
+			Payload:    string(item),
+			ItemStatus: JOB_STATUS_PENDING,
+			CreatedAt:  job.CreatedAt,
+			UpdatedAt:  job.CreatedAt,
+		})
+	}
+
+	// Pass to the repository layer.
+	result, err := s.jobRepository.CreateJob(ctx, job, &jobItems)
+	if err != nil {
+		return nil, utils.FailOnError(err, "problems creating the record")
+	}
+
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, utils.FailOnError(err, "it is not possible to retrieve the id from the record")
+	}
+
+	// Hand over to the workers without waiting, the queue may have filled up since the check above.
+	// A job that does not fit is failed, so it is neither resumed later nor left pending for the client.
+	select {
+	case s.queue <- int(insertedID):
+	default:
+		s.finishJob(ctx, &JobOut{ID: int(insertedID), UpdatedUser: job.CreatedUser}, JOB_STATUS_FAILED)
+		return nil, ErrQueueFull
+	}
+
+	JobOut := &JobOut{
+		ID:          int(insertedID),
+		JobType:     job.JobType,
+		JobStatus:   job.JobStatus,
+		TotalItems:  job.TotalItems,
+		UserID:      job.UserID,
+		Application: job.Application,
+		CreatedUser: job.CreatedUser,
+		CreatedAt:   job.CreatedAt,
+		UpdatedUser: job.UpdatedUser,
+		UpdatedAt:   job.UpdatedAt,
+		Status:      job.Status,
+	}
+	return JobOut, nil
+}
+
+// Implementation of 'StartWorkers'.
+// Launches the workers and resumes the jobs left unfinished by a previous run.
+func (s *jobService) StartWorkers() {
+	// Define workers settings.
+	workers, _ := strconv.Atoi(os.Getenv("JOB_WORKERS"))
+	if workers <= 0 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		go s.worker()
+	}
+
+	// Resume unfinished jobs.
+	jobs, err := s.jobRepository.GetUnfinishedJobs(context.Background())
+	if err != nil {
+		log.Printf("Oops... Jobs could not be resumed! Reason: %v", err)
+		return
+	}
+
+	// The workers make room for the jobs that do not fit in the queue.
+	go func() {
+		for _, job := range *jobs {
+			s.enqueue(context.Background(), job.ID)
+		}
+	}()
+}
+
+// Queues a resumed job, waiting for room in the queue until the context is done.
+func (s *jobService) enqueue(ctx context.Context, jobID int) error {
+	select {
+	case s.queue <- jobID:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Takes jobs from the queue until the application stops.
+func (s *jobService) worker() {
+	for jobID := range s.queue {
+		if err := s.runJob(context.Background(), jobID); err != nil {
+			log.Printf("Oops... Job %d has failed! Reason: %v", jobID, err)
+		}
+	}
+}
+
+// Processes every pending item of a job.
+// Items already completed before a restart are not processed again,
+// and items interrupted while running do not repeat what they already did.
+func (s *jobService) runJob(ctx context.Context, jobID int) error {
+	job, err := s.jobRepository.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("there is no job with this ID")
+	}
+
+	err = s.jobRepository.UpdateJob(ctx, jobID, &Job{JobStatus: JOB_STATUS_RUNNING, UpdatedUser: job.UpdatedUser, UpdatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	jobItems, err := s.jobRepository.GetPendingJobItems(ctx, jobID)
+	if err != nil {
+		s.finishJob(ctx, job, JOB_STATUS_FAILED)
+		return err
+	}
+
+	for _, jobItem := range *jobItems {
+		// Mark the item before its operation, so a restart knows it may be done already.
+		resumed := jobItem.ItemStatus == JOB_STATUS_RUNNING
+		if !resumed {
+			jobItem.ItemStatus = JOB_STATUS_RUNNING
+			jobItem.UpdatedAt = time.Now()
+			if err = s.jobRepository.StartJobItem(ctx, &jobItem); err != nil {
+				s.finishJob(ctx, job, JOB_STATUS_FAILED)
+				return err
+			}
+		}
+
+		jobItem.ItemStatus = JOB_STATUS_COMPLETED
+		jobItem.UpdatedAt = time.Now()
+
+		result, err := s.processItem(ctx, job, &jobItem, resumed)
+		if err != nil {
+			jobItem.ItemStatus = JOB_STATUS_FAILED
+			jobItem.ErrorMessage = err.Error()
+		} else if result != nil {
+			output, _ := json.Marshal(result)
+			jobItem.Result = string(output)
+		}
+
+		if err = s.jobRepository.CompleteJobItem(ctx, jobID, &jobItem); err != nil {
+			s.finishJob(ctx, job, JOB_STATUS_FAILED)
+			return err
+		}
+	}
+
+	s.finishJob(ctx, job, JOB_STATUS_COMPLETED)
+	return nil
+}
+
+// Stores the final state of a job.
+func (s *jobService) finishJob(ctx context.Context, job *JobOut, jobStatus string) {
+	err := s.jobRepository.UpdateJob(ctx, job.ID, &Job{JobStatus: jobStatus, UpdatedUser: job.UpdatedUser, UpdatedAt: time.Now()})
+	if err != nil {
+		log.Printf("Oops... Job %d could not be finished! Reason: %v", job.ID, err)
+	}
+}
+
+// Runs one item of a job through the shippingOrder service.
+// A resumed item is checked against the order first, its operation may be done already.
+func (s *jobService) processItem(ctx context.Context, job *JobOut, jobItem *JobItem, resumed bool) (result interface{}, err error) {
+	// A malformed item must not take the worker down.
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("item could not be processed: %v", r)
+		}
+	}()
+
+	// Create a new validator for the items.
+	validate := utils.NewValidator()
+
+	switch job.JobType {
+	case JOB_TYPE_CREATE_ORDER:
+		shippingOrderInsert := &shipping_order.ShippingOrderInsert{}
+		if err := json.Unmarshal([]byte(jobItem.Payload), shippingOrderInsert); err != nil {
+			return nil, err
+		}
+		if err := validate.Struct(shippingOrderInsert); err != nil {
+			return nil, fmt.Errorf("%v", utils.ValidatorErrors(err))
+		}
+		shippingOrderInsert.UserID = job.UserID
+		shippingOrderInsert.Application = job.Application
+		shippingOrderInsert.IdempotencyKey = fmt.Sprintf("job-%d-%d", job.ID, jobItem.ItemIndex)
+		return s.shippingOrderService.CreateShippingOrder(ctx, shippingOrderInsert)
+	case JOB_TYPE_UPDATE_ORDER_STATUS:
+		item := &JobUpdateOrderStatusItem{}
+		if err := json.Unmarshal([]byte(jobItem.Payload), item); err != nil {
+			return nil, err
+		}
+		if err := validate.Struct(item); err != nil {
+			return nil, fmt.Errorf("%v", utils.ValidatorErrors(err))
+		}
+		item.UserID = job.UserID
+		if resumed {
+			shippingOrder, err := s.shippingOrderService.GetShippingOrder(ctx, item.ShippingOrderID)
+			if err != nil {
+				return nil, err
+			}
+			if shippingOrder != nil && shippingOrder.OrderStatus == item.OrderStatus {
+				return shippingOrder, nil
+			}
+		}
+		return s.shippingOrderService.UpdateShippingOrder(ctx, item.ShippingOrderID, &item.ShippingOrderUpdate)
+	case JOB_TYPE_CANCEL_ORDER:
+		item := &JobCancelOrderItem{}
+		if err := json.Unmarshal([]byte(jobItem.Payload), item); err != nil {
+			return nil, err
+		}
+		if err := validate.Struct(item); err != nil {
+			return nil, fmt.Errorf("%v", utils.ValidatorErrors(err))
+		}
+		if resumed {
+			shippingOrder, err := s.shippingOrderService.GetShippingOrder(ctx, item.ShippingOrderID)
+			if err != nil {
+				return nil, err
+			}
+			if shippingOrder != nil && shippingOrder.OrderStatus == "cancelado" {
+				return nil, nil
+			}
+		}
+		return nil, s.shippingOrderService.CancelShippingOrder(ctx, item.ShippingOrderID, &item.ShippingOrderCancel)
+	default:
+		return nil, fmt.Errorf("job type '%v' is not supported", job.JobType)
+	}
+}
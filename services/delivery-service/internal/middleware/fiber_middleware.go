@@ -1,6 +1,10 @@
 package middleware
 
 import (
+	"os"
+	"strconv"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -44,3 +48,27 @@ func FiberMiddleware(a *fiber.App) {
 		}),
 	)
 }
+
+// VerificationLimiter limits the attempts to check a verification code of each tracking code from each client.
+// It is a first barrier, the verification store caps the attempts of a code across every instance.
+func VerificationLimiter() fiber.Handler {
+	max, err := strconv.Atoi(os.Getenv("VERIFICATION_VERIFY_LIMIT"))
+	if err != nil || max <= 0 {
+		max = 10
+	}
+
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP() + "|" + c.Params("trackingCode")
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(&fiber.Map{
+				"status":    "fail",
+				"message":   "Too many verification attempts for this order! Please wait another minute!",
+				"http_code": fiber.StatusTooManyRequests,
+			})
+		},
+	})
+}
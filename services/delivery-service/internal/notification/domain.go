@@ -0,0 +1,56 @@
+package notification
+
+import "context"
+
+// Channels a message can be sent through.
+const (
+	CHANNEL_EMAIL = "email"
+	CHANNEL_SMS   = "sms"
+)
+
+// Message struct to describe a single notification.
+type Message struct {
+	Channel string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Contact struct to describe who receives the notifications of an order.
+type Contact struct {
+	FullName string
+	Email    string
+	Phone    string
+	OptOut   bool
+}
+
+// StatusChange struct to describe the change of status to notify.
+type StatusChange struct {
+	ShippingOrderID int
+	OrderStatus     string
+	Language        string
+	Sender          *Contact
+	Recipient       *Contact
+}
+
+// VerificationCode struct to describe a one-time code sent to a contact of an order.
+type VerificationCode struct {
+	ShippingOrderID int
+	Channel         string
+	To              string
+	FullName        string
+	Code            string
+	ExpiresMinutes  int
+	Language        string
+}
+
+// Our channels will implement these methods.
+type Notifier interface {
+	Notify(ctx context.Context, message *Message) error
+}
+
+// Our use-case or service will implement these methods.
+type NotificationService interface {
+	NotifyStatusChange(ctx context.Context, statusChange *StatusChange) error
+	NotifyVerificationCode(ctx context.Context, verificationCode *VerificationCode) error
+}
@@ -0,0 +1,138 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewNotifier func for building the notifier configured for a channel.
+func NewNotifier(n string) (Notifier, error) {
+	// Switch given names.
+	switch n {
+	case "smtp":
+		return &smtpNotifier{
+			host:     os.Getenv("SMTP_HOST"),
+			port:     os.Getenv("SMTP_PORT"),
+			user:     os.Getenv("SMTP_USER"),
+			password: os.Getenv("SMTP_PASSWORD"),
+			from:     os.Getenv("SMTP_FROM"),
+		}, nil
+	case "sms":
+		return &smsNotifier{
+			url:    os.Getenv("SMS_PROVIDER_URL"),
+			apiKey: os.Getenv("SMS_PROVIDER_API_KEY"),
+			from:   os.Getenv("SMS_PROVIDER_FROM"),
+			client: &http.Client{Timeout: time.Second * 10},
+		}, nil
+	case "", "log":
+		return &logNotifier{path: os.Getenv("NOTIFICATION_LOG_FILE")}, nil
+	default:
+		// Return error message.
+		return nil, fmt.Errorf("notifier '%v' is not supported", n)
+	}
+}
+
+// Sends emails through an SMTP server.
+type smtpNotifier struct {
+	host     string
+	port     string
+	user     string
+	password string
+	from     string
+}
+
+// Sends a single email.
+func (n *smtpNotifier) Notify(ctx context.Context, message *Message) error {
+	var auth smtp.Auth
+	if n.user != "" {
+		auth = smtp.PlainAuth("", n.user, n.password, n.host)
+	}
+
+	body := "From: " + n.from + "\r\n" +
+		"To: " + message.To + "\r\n" +
+		"Subject: " + message.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n" +
+		message.Body
+
+	return smtp.SendMail(n.host+":"+n.port, auth, n.from, []string{message.To}, []byte(body))
+}
+
+// Sends SMS through the HTTP API of the provider.
+// The provider receives a JSON body with 'from', 'to' and 'message'.
+type smsNotifier struct {
+	url    string
+	apiKey string
+	from   string
+	client *http.Client
+}
+
+// Sends a single SMS.
+func (n *smsNotifier) Notify(ctx context.Context, message *Message) error {
+	body, err := json.Marshal(map[string]string{
+		"from":    n.from,
+		"to":      message.To,
+		"message": message.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+n.apiKey)
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("sms provider answered with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// Writes notifications to a file, or to the application log when no file is set.
+// Used for local runs.
+type logNotifier struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// Writes a single notification.
+func (n *logNotifier) Notify(ctx context.Context, message *Message) error {
+	line := fmt.Sprintf("%s [%s] to=%s subject=%q body=%q", time.Now().Format(time.RFC3339), message.Channel, message.To,
+		message.Subject, strings.ReplaceAll(message.Body, "\n", " "))
+
+	if n.path == "" {
+		log.Println(line)
+		return nil
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(line + "\n")
+	return err
+}
@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Implementation of the notifiers in this service.
+type notificationService struct {
+	emailNotifier Notifier
+	smsNotifier   Notifier
+}
+
+// Create a new 'service' or 'use-case' for notifications.
+func NewNotificationService(email Notifier, sms Notifier) NotificationService {
+	return &notificationService{
+		emailNotifier: email,
+		smsNotifier:   sms,
+	}
+}
+
+// Implementation of 'NotifyStatusChange'.
+// Sender and recipient are notified by email and SMS unless they opted out.
+// Every message is attempted, the errors are returned together.
+func (s *notificationService) NotifyStatusChange(ctx context.Context, statusChange *StatusChange) error {
+	var errs []string
+
+	for _, contact := range []*Contact{statusChange.Sender, statusChange.Recipient} {
+		if contact == nil || contact.OptOut {
+			continue
+		}
+
+		subject, body, ok, err := renderTemplate(statusChange.Language, statusChange.OrderStatus, &templateData{
+			FullName:        contact.FullName,
+			ShippingOrderID: statusChange.ShippingOrderID,
+			OrderStatus:     statusChange.OrderStatus,
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		if contact.Email != "" {
+			err = s.emailNotifier.Notify(ctx, &Message{Channel: CHANNEL_EMAIL, To: contact.Email, Subject: subject, Body: body})
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+
+		if contact.Phone != "" {
+			err = s.smsNotifier.Notify(ctx, &Message{Channel: CHANNEL_SMS, To: contact.Phone, Subject: subject, Body: body})
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("some notifications could not be sent: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Implementation of 'NotifyVerificationCode'.
+// The code is sent through its channel even when the contact opted out of the notifications.
+func (s *notificationService) NotifyVerificationCode(ctx context.Context, verificationCode *VerificationCode) error {
+	subject, body, _, err := renderTemplate(verificationCode.Language, TEMPLATE_VERIFICATION_CODE, &templateData{
+		FullName:        verificationCode.FullName,
+		ShippingOrderID: verificationCode.ShippingOrderID,
+		Code:            verificationCode.Code,
+		ExpiresMinutes:  verificationCode.ExpiresMinutes,
+	})
+	if err != nil {
+		return err
+	}
+
+	notifier := s.emailNotifier
+	if verificationCode.Channel == CHANNEL_SMS {
+		notifier = s.smsNotifier
+	}
+
+	return notifier.Notify(ctx, &Message{Channel: verificationCode.Channel, To: verificationCode.To, Subject: subject, Body: body})
+}
@@ -0,0 +1,94 @@
+package notification
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// Text of the notification sent for a status, in one language.
+type messageTemplate struct {
+	Subject string
+	Body    string
+}
+
+// Template of the verification codes, kept with the templates of the statuses.
+const TEMPLATE_VERIFICATION_CODE = "codigo_verificacion"
+
+// Templates per language and per order status.
+// Available fields: .FullName, .ShippingOrderID, .OrderStatus, and .Code and .ExpiresMinutes for the verification codes.
+var templates = map[string]map[string]messageTemplate{
+	"es": {
+		"recolectado":     {"Tu pedido {{.ShippingOrderID}} fue recolectado", "Hola {{.FullName}}, el pedido {{.ShippingOrderID}} fue recolectado y está en camino a nuestra estación."},
+		"en_estacion":     {"Tu pedido {{.ShippingOrderID}} está en estación", "Hola {{.FullName}}, el pedido {{.ShippingOrderID}} llegó a nuestra estación y pronto saldrá a reparto."},
+		"en_ruta":         {"Tu pedido {{.ShippingOrderID}} está en ruta", "Hola {{.FullName}}, el pedido {{.ShippingOrderID}} salió a reparto y llegará hoy."},
+		"entregado":       {"Tu pedido {{.ShippingOrderID}} fue entregado", "Hola {{.FullName}}, el pedido {{.ShippingOrderID}} fue entregado. ¡Gracias por confiar en nosotros!"},
+		"intento_fallido": {"No pudimos entregar tu pedido {{.ShippingOrderID}}", "Hola {{.FullName}}, no pudimos entregar el pedido {{.ShippingOrderID}}. Lo intentaremos nuevamente."},
+		"devolucion":      {"Tu pedido {{.ShippingOrderID}} será devuelto", "Hola {{.FullName}}, no pudimos entregar el pedido {{.ShippingOrderID}} y será devuelto al remitente."},
+		"devuelto":        {"Tu pedido {{.ShippingOrderID}} fue devuelto", "Hola {{.FullName}}, el pedido {{.ShippingOrderID}} fue devuelto al remitente."},
+		"cancelado":       {"Tu pedido {{.ShippingOrderID}} fue cancelado", "Hola {{.FullName}}, el pedido {{.ShippingOrderID}} fue cancelado."},
+
+		TEMPLATE_VERIFICATION_CODE: {"Tu código de verificación", "Hola {{.FullName}}, tu código para gestionar el pedido {{.ShippingOrderID}} es {{.Code}}. Vence en {{.ExpiresMinutes}} minutos."},
+	},
+	"en": {
+		"recolectado":     {"Your order {{.ShippingOrderID}} has been picked up", "Hi {{.FullName}}, order {{.ShippingOrderID}} has been picked up and is on its way to our station."},
+		"en_estacion":     {"Your order {{.ShippingOrderID}} is at the station", "Hi {{.FullName}}, order {{.ShippingOrderID}} has arrived at our station and will be out for delivery soon."},
+		"en_ruta":         {"Your order {{.ShippingOrderID}} is out for delivery", "Hi {{.FullName}}, order {{.ShippingOrderID}} is out for delivery and will arrive today."},
+		"entregado":       {"Your order {{.ShippingOrderID}} has been delivered", "Hi {{.FullName}}, order {{.ShippingOrderID}} has been delivered. Thank you for trusting us!"},
+		"intento_fallido": {"We could not deliver your order {{.ShippingOrderID}}", "Hi {{.FullName}}, we could not deliver order {{.ShippingOrderID}}. We will try again."},
+		"devolucion":      {"Your order {{.ShippingOrderID}} is being returned", "Hi {{.FullName}}, we could not deliver order {{.ShippingOrderID}} and it is being returned to the sender."},
+		"devuelto":        {"Your order {{.ShippingOrderID}} has been returned", "Hi {{.FullName}}, order {{.ShippingOrderID}} has been returned to the sender."},
+		"cancelado":       {"Your order {{.ShippingOrderID}} has been cancelled", "Hi {{.FullName}}, order {{.ShippingOrderID}} has been cancelled."},
+
+		TEMPLATE_VERIFICATION_CODE: {"Your verification code", "Hi {{.FullName}}, your code to manage order {{.ShippingOrderID}} is {{.Code}}. It expires in {{.ExpiresMinutes}} minutes."},
+	},
+}
+
+// Data available to the templates.
+type templateData struct {
+	FullName        string
+	ShippingOrderID int
+	OrderStatus     string
+	Code            string
+	ExpiresMinutes  int
+}
+
+// Renders the subject and body of a status in the given language.
+// It returns false when there is no template for the status.
+func renderTemplate(language string, orderStatus string, data *templateData) (string, string, bool, error) {
+	languageTemplates, ok := templates[language]
+	if !ok {
+		languageTemplates = templates["es"]
+	}
+
+	messageTemplate, ok := languageTemplates[orderStatus]
+	if !ok {
+		return "", "", false, nil
+	}
+
+	subject, err := render(messageTemplate.Subject, data)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	body, err := render(messageTemplate.Body, data)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	return subject, body, true, nil
+}
+
+// Executes a single template.
+func render(text string, data *templateData) (string, error) {
+	t, err := template.New("notification").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	if err = t.Execute(&output, data); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
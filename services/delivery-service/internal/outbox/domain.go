@@ -0,0 +1,44 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// States of an event stored in the outbox.
+const (
+	EVENT_STATUS_PENDING   = "pending"
+	EVENT_STATUS_PUBLISHED = "published"
+	EVENT_STATUS_FAILED    = "failed"
+)
+
+// Event struct to describe a domain event stored in the outbox.
+type Event struct {
+	ID            int       `db:"id"`
+	AggregateType string    `db:"aggregate_type"`
+	AggregateID   int       `db:"aggregate_id"`
+	Application   string    `db:"application"`
+	EventType     string    `db:"event_type"`
+	Payload       string    `db:"payload"`
+	EventStatus   string    `db:"event_status"`
+	Attempts      int       `db:"attempts"`
+	LastError     string    `db:"last_error"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// Our repository will implement these methods.
+// 'SaveEvent' runs inside the transaction of the aggregate that emits the event.
+// The pending events stay locked by the transaction of the relay until it marks them.
+type OutboxRepository interface {
+	SaveEvent(ctx context.Context, tx *sql.Tx, event *Event) error
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+	GetPendingEvents(ctx context.Context, tx *sql.Tx, limit int) (*[]Event, error)
+	MarkEventPublished(ctx context.Context, tx *sql.Tx, eventID int) error
+	MarkEventFailed(ctx context.Context, tx *sql.Tx, eventID int, eventStatus string, lastError string) error
+}
+
+// Our sinks will implement these methods.
+type Publisher interface {
+	Publish(ctx context.Context, event *Event) error
+}
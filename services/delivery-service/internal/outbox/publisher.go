@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"context"
+	"delivery-service/internal/utils"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NewPublisher func for building the sink configured for the relay.
+func NewPublisher(n string) (Publisher, error) {
+	// Switch given names.
+	switch n {
+	case "", "redis":
+		// Create a new Redis connection.
+		connRedis, err := utils.RedisConnection()
+		if err != nil {
+			return nil, err
+		}
+
+		stream := os.Getenv("OUTBOX_REDIS_STREAM")
+		if stream == "" {
+			stream = "delivery-service.events"
+		}
+
+		return &redisStreamPublisher{redis: connRedis, stream: stream}, nil
+	case "log":
+		return &logPublisher{}, nil
+	default:
+		// Return error message.
+		return nil, fmt.Errorf("outbox sink '%v' is not supported", n)
+	}
+}
+
+// Publishes events to a Redis Stream.
+type redisStreamPublisher struct {
+	redis  *redis.Client
+	stream string
+}
+
+// Adds a single event to the stream.
+func (p *redisStreamPublisher) Publish(ctx context.Context, event *Event) error {
+	return p.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		Values: map[string]interface{}{
+			"event_id":       event.ID,
+			"event_type":     event.EventType,
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   event.AggregateID,
+			"application":    event.Application,
+			"payload":        event.Payload,
+			"created_at":     event.CreatedAt.Format(time.RFC3339),
+		},
+	}).Err()
+}
+
+// Writes events to the application log, used for local runs.
+type logPublisher struct{}
+
+// Logs a single event.
+func (p *logPublisher) Publish(ctx context.Context, event *Event) error {
+	log.Printf("Event %d %s %s/%d: %s", event.ID, event.EventType, event.AggregateType, event.AggregateID, event.Payload)
+	return nil
+}
@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Relay moves the events stored in the outbox to the configured sinks.
+type Relay struct {
+	outboxRepository OutboxRepository
+	publishers       []Publisher
+	interval         time.Duration
+	batchSize        int
+	maxAttempts      int
+}
+
+// Create a new relay for the outbox.
+// An event is published to every sink, in the given order.
+func NewRelay(r OutboxRepository, p ...Publisher) *Relay {
+	// Define relay settings.
+	intervalSecondsCount, _ := strconv.Atoi(os.Getenv("OUTBOX_RELAY_INTERVAL"))
+	if intervalSecondsCount <= 0 {
+		intervalSecondsCount = 5
+	}
+
+	batchSize, _ := strconv.Atoi(os.Getenv("OUTBOX_RELAY_BATCH_SIZE"))
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	maxAttempts, _ := strconv.Atoi(os.Getenv("OUTBOX_RELAY_MAX_ATTEMPTS"))
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+
+	return &Relay{
+		outboxRepository: r,
+		publishers:       p,
+		interval:         time.Second * time.Duration(intervalSecondsCount),
+		batchSize:        batchSize,
+		maxAttempts:      maxAttempts,
+	}
+}
+
+// Start launches the relay goroutine.
+func (r *Relay) Start() {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			r.publishPendingEvents(context.Background())
+		}
+	}()
+}
+
+// Publishes the pending events in the order they were stored.
+// Delivery is at-least-once: when a sink fails the event is published again to every sink.
+// The events are locked while they are published, another relay skips them.
+func (r *Relay) publishPendingEvents(ctx context.Context) {
+	tx, err := r.outboxRepository.BeginTx(ctx)
+	if err != nil {
+		log.Printf("Oops... Outbox events could not be retrieved! Reason: %v", err)
+		return
+	}
+	defer tx.Rollback()
+
+	events, err := r.outboxRepository.GetPendingEvents(ctx, tx, r.batchSize)
+	if err != nil {
+		log.Printf("Oops... Outbox events could not be retrieved! Reason: %v", err)
+		return
+	}
+
+	for _, event := range *events {
+		if err := r.publish(ctx, &event); err != nil {
+			log.Printf("Oops... Event %d could not be published! Reason: %v", event.ID, err)
+
+			// An event that keeps failing is set aside, so the ones after it are not held back forever.
+			if event.Attempts+1 >= r.maxAttempts {
+				if err = r.outboxRepository.MarkEventFailed(ctx, tx, event.ID, EVENT_STATUS_FAILED, err.Error()); err != nil {
+					log.Printf("Oops... Event %d could not be updated! Reason: %v", event.ID, err)
+					break
+				}
+				continue
+			}
+
+			// Stop here to keep the order, the event is retried on the next tick.
+			if err = r.outboxRepository.MarkEventFailed(ctx, tx, event.ID, EVENT_STATUS_PENDING, err.Error()); err != nil {
+				log.Printf("Oops... Event %d could not be updated! Reason: %v", event.ID, err)
+			}
+			break
+		}
+
+		if err := r.outboxRepository.MarkEventPublished(ctx, tx, event.ID); err != nil {
+			log.Printf("Oops... Event %d could not be updated! Reason: %v", event.ID, err)
+			break
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Oops... Outbox events could not be updated! Reason: %v", err)
+	}
+}
+
+// Publishes a single event to every sink.
+func (r *Relay) publish(ctx context.Context, event *Event) error {
+	for _, publisher := range r.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Queries that we will use.
+const (
+	QUERY_CREATE_EVENT = "INSERT INTO outbox_event (aggregate_type, aggregate_id, application, event_type, payload, event_status, attempts, last_error, created_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_GET_PENDING_EVENTS = "SELECT id, aggregate_type, aggregate_id, application, event_type, payload, event_status, attempts, last_error, created_at " +
+		"FROM outbox_event WHERE event_status = ? order by id asc limit ? FOR UPDATE SKIP LOCKED"
+	QUERY_PUBLISH_EVENT = "UPDATE outbox_event SET event_status = ?, published_at = ? WHERE id = ?"
+	QUERY_FAIL_EVENT    = "UPDATE outbox_event SET event_status = ?, attempts = attempts + 1, last_error = ? WHERE id = ?"
+)
+
+// Represents that we will use MariaDB in order to implement the methods.
+type mariaDBRepository struct {
+	mariadb *sql.DB
+}
+
+// Create a new repository with MariaDB as the driver.
+func NewOutboxRepository(mariaDBConnection *sql.DB) OutboxRepository {
+	return &mariaDBRepository{
+		mariadb: mariaDBConnection,
+	}
+}
+
+// Stores a single event as part of the given transaction.
+func (r *mariaDBRepository) SaveEvent(ctx context.Context, tx *sql.Tx, event *Event) error {
+	// Insert one event.
+	result, err := tx.ExecContext(ctx, QUERY_CREATE_EVENT, event.AggregateType, event.AggregateID, event.Application, event.EventType, event.Payload,
+		EVENT_STATUS_PENDING, 0, "", event.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	event.ID = int(insertedID)
+	event.EventStatus = EVENT_STATUS_PENDING
+	return nil
+}
+
+// Starts the transaction that holds the pending events of a relay.
+func (r *mariaDBRepository) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return r.mariadb.BeginTx(ctx, nil)
+}
+
+// Gets and locks the oldest events that have not been published yet, skipping the ones locked by another relay.
+func (r *mariaDBRepository) GetPendingEvents(ctx context.Context, tx *sql.Tx, limit int) (*[]Event, error) {
+	// Initialize variables.
+	var events []Event
+
+	// Get pending events.
+	res, err := tx.QueryContext(ctx, QUERY_GET_PENDING_EVENTS, EVENT_STATUS_PENDING, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'events' array.
+	for res.Next() {
+		event := &Event{}
+		err = res.Scan(&event.ID, &event.AggregateType, &event.AggregateID, &event.Application, &event.EventType, &event.Payload, &event.EventStatus,
+			&event.Attempts, &event.LastError, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+
+	// Return all of our events.
+	return &events, nil
+}
+
+// Marks a single event as published.
+func (r *mariaDBRepository) MarkEventPublished(ctx context.Context, tx *sql.Tx, eventID int) error {
+	_, err := tx.ExecContext(ctx, QUERY_PUBLISH_EVENT, EVENT_STATUS_PUBLISHED, time.Now(), eventID)
+	return err
+}
+
+// Records a failed attempt to publish a single event, a failed status ends its retries.
+func (r *mariaDBRepository) MarkEventFailed(ctx context.Context, tx *sql.Tx, eventID int, eventStatus string, lastError string) error {
+	_, err := tx.ExecContext(ctx, QUERY_FAIL_EVENT, eventStatus, lastError, eventID)
+	return err
+}
@@ -0,0 +1,74 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Product struct to describe Product object.
+type Product struct {
+	ID               int       `db:"id"`
+	Name             string    `db:"name"`
+	ShortDescription string    `db:"short_description"`
+	UnitValue        float64   `db:"unit_value"`
+	Application      string    `db:"application"`
+	CreatedUser      string    `db:"created_user"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedUser      string    `db:"updated_user"`
+	UpdatedAt        time.Time `db:"updated_at"`
+	Status           string    `db:"status"`
+}
+
+// ProductInsert struct to describe register a new product.
+type ProductInsert struct {
+	Name             string  `json:"name" validate:"required,lte=100"`
+	ShortDescription string  `json:"shortDescription" validate:"required,lte=100"`
+	UnitValue        float64 `json:"unitValue" validate:"gte=0"`
+	Application      string  `json:"-"`
+	CreatedUser      string  `json:"createdUser" validate:"required,lte=100"`
+}
+
+// ProductUpdate struct to describe update product.
+type ProductUpdate struct {
+	Name             string  `json:"name" validate:"required,lte=100"`
+	ShortDescription string  `json:"shortDescription" validate:"required,lte=100"`
+	UnitValue        float64 `json:"unitValue" validate:"gte=0"`
+	UpdatedUser      string  `json:"updatedUser" validate:"required,lte=100"`
+}
+
+// ProductDelete struct to describe delete product.
+type ProductDelete struct {
+	UpdatedUser string `json:"updatedUser" validate:"required,lte=100"`
+}
+
+type ProductOut struct {
+	ID               int       `json:"id"`
+	Name             string    `json:"name"`
+	ShortDescription string    `json:"shortDescription"`
+	UnitValue        float64   `json:"unitValue"`
+	Application      string    `json:"application"`
+	CreatedUser      string    `json:"created_user"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedUser      string    `json:"updated_user"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	Status           string    `json:"status"`
+}
+
+// Our repository will implement these methods.
+type ProductRepository interface {
+	GetProducts(ctx context.Context, application string) (*[]ProductOut, error)
+	GetProduct(ctx context.Context, productID int, application string) (*ProductOut, error)
+	CreateProduct(ctx context.Context, product *Product) (sql.Result, error)
+	UpdateProduct(ctx context.Context, productID int, product *Product) error
+	DeleteProduct(ctx context.Context, productID int, product *Product) error
+}
+
+// Our use-case or service will implement these methods.
+type ProductService interface {
+	GetProducts(ctx context.Context, application string) (*[]ProductOut, error)
+	GetProduct(ctx context.Context, productID int, application string) (*ProductOut, error)
+	CreateProduct(ctx context.Context, productInsert *ProductInsert) (*ProductOut, error)
+	UpdateProduct(ctx context.Context, productID int, application string, productUpdate *ProductUpdate) (*ProductOut, error)
+	DeleteProduct(ctx context.Context, productID int, application string, productDelete *ProductDelete) error
+}
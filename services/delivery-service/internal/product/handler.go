@@ -0,0 +1,273 @@
+package product
+
+import (
+	"context"
+	"delivery-service/internal/middleware"
+	"delivery-service/internal/utils"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Represents our handler with our use-case / service.
+type ProductHandler struct {
+	productService ProductService
+}
+
+// Creates a new handler.
+func NewProductHandler(productRoute fiber.Router, ps ProductService) {
+	// Create a handler based on our created service / use-case.
+	handler := &ProductHandler{
+		productService: ps,
+	}
+
+	// We will restrict this route with our JWT middleware.
+	// Products are scoped to the application of the token.
+	productRoute.Use(middleware.JWTProtected(), middleware.ExtractTokenMetadata)
+
+	// Declare routing endpoints for general routes.
+	productRoute.Get("", handler.getProducts)
+	productRoute.Post("", handler.createProduct)
+
+	// Declare routing endpoints for specific routes.
+	productRoute.Get("/:productID", handler.getProduct)
+	productRoute.Put("/:productID", handler.updateProduct)
+	productRoute.Delete("/:productID", handler.deleteProduct)
+}
+
+// Gets all products.
+func (h *ProductHandler) getProducts(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Get all products of the application.
+	products, err := h.productService.GetProducts(customContext, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Products obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      products,
+	})
+}
+
+// Gets a single product.
+func (h *ProductHandler) getProduct(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedProductID, err := c.ParamsInt("productID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid product ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get one product.
+	product, err := h.productService.GetProduct(customContext, targetedProductID, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if product == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("Product of ID {%d} does not exist.", targetedProductID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Product obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      product,
+	})
+}
+
+// Creates a single product.
+func (h *ProductHandler) createProduct(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	productInsert := &ProductInsert{}
+
+	// Parse request body.
+	if err := c.BodyParser(productInsert); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Product model.
+	validate := utils.NewValidator()
+
+	// Validate product fields.
+	if err := validate.Struct(productInsert); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create one product for the application.
+	productInsert.Application = c.Locals("application").(string)
+	product, err := h.productService.CreateProduct(customContext, productInsert)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusCreated).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Product has been created successfully!",
+		"http_code": fiber.StatusCreated,
+		"data":      product,
+	})
+}
+
+// Updates a single product.
+func (h *ProductHandler) updateProduct(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	productUpdate := &ProductUpdate{}
+
+	// Fetch parameter.
+	targetedProductID, err := c.ParamsInt("productID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid product ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(productUpdate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Product model.
+	validate := utils.NewValidator()
+
+	// Validate product fields.
+	if err := validate.Struct(productUpdate); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Update one product.
+	product, err := h.productService.UpdateProduct(customContext, targetedProductID, c.Locals("application").(string), productUpdate)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Product has been updated successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      product,
+	})
+}
+
+// Deletes a single product.
+func (h *ProductHandler) deleteProduct(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	productDelete := &ProductDelete{}
+
+	// Fetch parameter.
+	targetedProductID, err := c.ParamsInt("productID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid product ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(productDelete); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Product model.
+	validate := utils.NewValidator()
+
+	// Validate product fields.
+	if err := validate.Struct(productDelete); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Delete one product.
+	err = h.productService.DeleteProduct(customContext, targetedProductID, c.Locals("application").(string), productDelete)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Product has been deleted successfully!",
+		"http_code": fiber.StatusOK,
+	})
+}
@@ -0,0 +1,146 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Queries that we will use.
+const (
+	QUERY_PRODUCT_COLUMNS = "id, name, short_description, unit_value, application, created_user, created_at, updated_user, updated_at, status"
+	QUERY_GET_PRODUCTS    = "SELECT " + QUERY_PRODUCT_COLUMNS + " FROM product WHERE application = ? and status = ? order by name asc"
+	QUERY_GET_PRODUCT     = "SELECT " + QUERY_PRODUCT_COLUMNS + " FROM product WHERE id = ? and application = ? and status = ?"
+	QUERY_CREATE_PRODUCT  = "INSERT INTO product (name, short_description, unit_value, application, created_user, created_at, updated_user, updated_at, status) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_UPDATE_PRODUCT = "UPDATE product SET name = ?, short_description = ?, unit_value = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_DELETE_PRODUCT = "UPDATE product SET status = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+)
+
+// Represents that we will use MariaDB in order to implement the methods.
+type mariaDBRepository struct {
+	mariadb *sql.DB
+}
+
+// Create a new repository with MariaDB as the driver.
+func NewProductRepository(mariaDBConnection *sql.DB) ProductRepository {
+	return &mariaDBRepository{
+		mariadb: mariaDBConnection,
+	}
+}
+
+// Row of any query that selects 'QUERY_PRODUCT_COLUMNS'.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a product row into the 'ProductOut' struct.
+func scanProduct(row rowScanner) (*ProductOut, error) {
+	product := &ProductOut{}
+	err := row.Scan(&product.ID, &product.Name, &product.ShortDescription, &product.UnitValue, &product.Application,
+		&product.CreatedUser, &product.CreatedAt, &product.UpdatedUser, &product.UpdatedAt, &product.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Gets all products of an application in the database.
+func (r *mariaDBRepository) GetProducts(ctx context.Context, application string) (*[]ProductOut, error) {
+	// Initialize variables.
+	products := []ProductOut{}
+
+	// Get all products.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_PRODUCTS, application, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'products' array.
+	for res.Next() {
+		product, err := scanProduct(res)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, *product)
+	}
+
+	// Return all of our products.
+	return &products, res.Err()
+}
+
+// Gets a single product of an application in the database.
+func (r *mariaDBRepository) GetProduct(ctx context.Context, productID int, application string) (*ProductOut, error) {
+	// Get one product.
+	// If it's empty, return null.
+	product, err := scanProduct(r.mariadb.QueryRowContext(ctx, QUERY_GET_PRODUCT, productID, application, "A"))
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return product, nil
+}
+
+// Creates a single product in the database.
+func (r *mariaDBRepository) CreateProduct(ctx context.Context, product *Product) (sql.Result, error) {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_CREATE_PRODUCT)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Insert one product.
+	result, err := stmt.ExecContext(ctx, product.Name, product.ShortDescription, product.UnitValue, product.Application,
+		product.CreatedUser, product.CreatedAt, product.UpdatedUser, product.UpdatedAt, product.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return result, nil
+}
+
+// Updates a single product in the database.
+func (r *mariaDBRepository) UpdateProduct(ctx context.Context, productID int, product *Product) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_PRODUCT)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one product.
+	_, err = stmt.ExecContext(ctx, product.Name, product.ShortDescription, product.UnitValue,
+		product.UpdatedUser, product.UpdatedAt, productID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Deletes a single product in the database.
+func (r *mariaDBRepository) DeleteProduct(ctx context.Context, productID int, product *Product) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_DELETE_PRODUCT)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Delete one product.
+	_, err = stmt.ExecContext(ctx, product.Status, product.UpdatedUser, product.UpdatedAt, productID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
@@ -0,0 +1,113 @@
+package product
+
+import (
+	"context"
+	"delivery-service/internal/utils"
+	"fmt"
+	"time"
+)
+
+// Implementation of the repository in this service.
+type productService struct {
+	productRepository ProductRepository
+}
+
+// Create a new 'service' or 'use-case' for 'Product' entity.
+func NewProductService(r ProductRepository) ProductService {
+	return &productService{
+		productRepository: r,
+	}
+}
+
+// Implementation of 'GetProducts'.
+func (s *productService) GetProducts(ctx context.Context, application string) (*[]ProductOut, error) {
+	return s.productRepository.GetProducts(ctx, application)
+}
+
+// Implementation of 'GetProduct'.
+func (s *productService) GetProduct(ctx context.Context, productID int, application string) (*ProductOut, error) {
+	return s.productRepository.GetProduct(ctx, productID, application)
+}
+
+// Implementation of 'CreateProduct'.
+func (s *productService) CreateProduct(ctx context.Context, productInsert *ProductInsert) (*ProductOut, error) {
+	// Set initialized default data for product.
+	product := &Product{
+		Name:             productInsert.Name,
+		ShortDescription: productInsert.ShortDescription,
+		UnitValue:        productInsert.UnitValue,
+		Application:      productInsert.Application,
+		CreatedUser:      productInsert.CreatedUser,
+		CreatedAt:        time.Now(),
+		UpdatedUser:      productInsert.CreatedUser,
+		UpdatedAt:        time.Now(),
+		Status:           "A",
+	}
+
+	// Pass to the repository layer.
+	result, err := s.productRepository.CreateProduct(ctx, product)
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not insert record")
+	}
+
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not get inserted id")
+	}
+
+	return s.productRepository.GetProduct(ctx, int(insertedID), productInsert.Application)
+}
+
+// Implementation of 'UpdateProduct'.
+func (s *productService) UpdateProduct(ctx context.Context, productID int, application string, productUpdate *ProductUpdate) (*ProductOut, error) {
+	// Check if product exists.
+	searchedProduct, err := s.productRepository.GetProduct(ctx, productID, application)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedProduct == nil {
+		return nil, fmt.Errorf("There is no product with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	product := &Product{
+		Name:             productUpdate.Name,
+		ShortDescription: productUpdate.ShortDescription,
+		UnitValue:        productUpdate.UnitValue,
+		UpdatedUser:      productUpdate.UpdatedUser,
+		UpdatedAt:        time.Now(),
+	}
+
+	// Pass to the repository layer.
+	if err = s.productRepository.UpdateProduct(ctx, productID, product); err != nil {
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	return s.productRepository.GetProduct(ctx, productID, application)
+}
+
+// Implementation of 'DeleteProduct'.
+func (s *productService) DeleteProduct(ctx context.Context, productID int, application string, productDelete *ProductDelete) error {
+	// Check if product exists.
+	searchedProduct, err := s.productRepository.GetProduct(ctx, productID, application)
+	if err != nil {
+		return utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedProduct == nil {
+		return fmt.Errorf("There is no product with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	product := &Product{
+		UpdatedUser: productDelete.UpdatedUser,
+		UpdatedAt:   time.Now(),
+		Status:      "I",
+	}
+
+	// Pass to the repository layer.
+	if err = s.productRepository.DeleteProduct(ctx, productID, product); err != nil {
+		return utils.FailOnError(err, "could not delete record")
+	}
+
+	return nil
+}
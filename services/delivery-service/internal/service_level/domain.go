@@ -0,0 +1,56 @@
+package service_level
+
+import (
+	"context"
+	"time"
+)
+
+// Service levels offered to the senders.
+const (
+	SERVICE_LEVEL_STANDARD = "standard"
+	SERVICE_LEVEL_EXPRESS  = "express"
+	SERVICE_LEVEL_SAME_DAY = "same_day"
+)
+
+// ServiceLevel struct to describe ServiceLevel object.
+type ServiceLevel struct {
+	ID              int       `db:"id"`
+	Name            string    `db:"name"`
+	Nemo            string    `db:"nemo"`
+	CutoffTime      string    `db:"cutoff_time"`
+	MaxDistanceKm   float64   `db:"max_distance_km"`
+	AllowedSizes    string    `db:"allowed_sizes"`
+	PriceMultiplier float64   `db:"price_multiplier"`
+	HandlingMinutes int       `db:"handling_minutes"`
+	Priority        int       `db:"priority"`
+	CreatedUser     string    `db:"created_user"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedUser     string    `db:"updated_user"`
+	UpdatedAt       time.Time `db:"updated_at"`
+	Status          string    `db:"status"`
+}
+
+// An empty 'CutoffTime' means orders are taken all day,
+// a zero 'MaxDistanceKm' means there is no distance limit.
+type ServiceLevelOut struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	Nemo            string    `json:"nemo"`
+	CutoffTime      string    `json:"cutoffTime"`
+	MaxDistanceKm   float64   `json:"maxDistanceKm"`
+	AllowedSizes    string    `json:"allowedSizes"`
+	PriceMultiplier float64   `json:"priceMultiplier"`
+	HandlingMinutes int       `json:"handlingMinutes"`
+	Priority        int       `json:"priority"`
+	CreatedUser     string    `json:"created_user"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedUser     string    `json:"updated_user"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	Status          string    `json:"status"`
+}
+
+// Our repository will implement these methods.
+type ServiceLevelRepository interface {
+	GetServiceLevels(ctx context.Context) (*[]ServiceLevelOut, error)
+	GetServiceLevel(ctx context.Context, serviceLevelNemo string) (*ServiceLevelOut, error)
+}
@@ -0,0 +1,86 @@
+package service_level
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Queries that we will use.
+const (
+	QUERY_SERVICELEVEL_COLUMNS = "id, name, nemo, cutoff_time, max_distance_km, allowed_sizes, price_multiplier, handling_minutes, priority, " +
+		"created_user, created_at, updated_user, updated_at, status"
+	QUERY_GET_SERVICELEVELS = "SELECT " + QUERY_SERVICELEVEL_COLUMNS + " FROM service_level " +
+		"WHERE status = ? order by priority asc"
+	QUERY_GET_SERVICELEVEL = "SELECT " + QUERY_SERVICELEVEL_COLUMNS + " FROM service_level " +
+		"WHERE nemo = ? and status = ?"
+)
+
+// Represents that we will use MariaDB in order to implement the methods.
+type mariaDBRepository struct {
+	mariadb *sql.DB
+}
+
+// Create a new repository with MariaDB as the driver.
+func NewServiceLevelRepository(mariaDBConnection *sql.DB) ServiceLevelRepository {
+	return &mariaDBRepository{
+		mariadb: mariaDBConnection,
+	}
+}
+
+// Scanner of a single row, satisfied by both '*sql.Row' and '*sql.Rows'.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans the columns of 'QUERY_SERVICELEVEL_COLUMNS' into a new serviceLevel.
+func scanServiceLevel(row rowScanner) (*ServiceLevelOut, error) {
+	serviceLevel := &ServiceLevelOut{}
+	err := row.Scan(&serviceLevel.ID, &serviceLevel.Name, &serviceLevel.Nemo, &serviceLevel.CutoffTime, &serviceLevel.MaxDistanceKm, &serviceLevel.AllowedSizes,
+		&serviceLevel.PriceMultiplier, &serviceLevel.HandlingMinutes, &serviceLevel.Priority, &serviceLevel.CreatedUser, &serviceLevel.CreatedAt,
+		&serviceLevel.UpdatedUser, &serviceLevel.UpdatedAt, &serviceLevel.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return serviceLevel, nil
+}
+
+// Gets all serviceLevels in the database.
+func (r *mariaDBRepository) GetServiceLevels(ctx context.Context) (*[]ServiceLevelOut, error) {
+	// Initialize variables.
+	var serviceLevels []ServiceLevelOut
+
+	// Get all serviceLevels.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_SERVICELEVELS, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'serviceLevels' array.
+	for res.Next() {
+		serviceLevel, err := scanServiceLevel(res)
+		if err != nil {
+			return nil, err
+		}
+		serviceLevels = append(serviceLevels, *serviceLevel)
+	}
+
+	// Return all of our serviceLevels.
+	return &serviceLevels, nil
+}
+
+// Gets a single serviceLevel in the database.
+func (r *mariaDBRepository) GetServiceLevel(ctx context.Context, serviceLevelNemo string) (*ServiceLevelOut, error) {
+	// Get serviceLevel.
+	serviceLevel, err := scanServiceLevel(r.mariadb.QueryRowContext(ctx, QUERY_GET_SERVICELEVEL, serviceLevelNemo, "A"))
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return serviceLevel, nil
+}
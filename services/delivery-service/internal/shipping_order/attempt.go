@@ -0,0 +1,52 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/utils"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Attempts to deliver an order before it is returned to its sender.
+func maxDeliveryAttempts() int {
+	maxAttempts, _ := strconv.Atoi(os.Getenv("DELIVERY_MAX_ATTEMPTS"))
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	return maxAttempts
+}
+
+// Records a failed delivery attempt on the change of an order.
+// The order waits to be rescheduled en route, or goes back to its sender after its last attempt.
+func failDeliveryAttempt(searchedShippingOrder *ShippingOrderOut, shippingOrder *ShippingOrder, reason string, notes string) {
+	shippingOrder.DeliveryAttempts = searchedShippingOrder.DeliveryAttempts + 1
+	shippingOrder.DeliveryAttempt = &DeliveryAttempt{
+		ShippingOrderID: searchedShippingOrder.ID,
+		Attempt:         shippingOrder.DeliveryAttempts,
+		Reason:          reason,
+		Notes:           notes,
+		CourierID:       searchedShippingOrder.CourierID,
+		CreatedUser:     shippingOrder.UpdatedUser,
+		CreatedAt:       shippingOrder.UpdatedAt,
+	}
+
+	if shippingOrder.DeliveryAttempts >= maxDeliveryAttempts() {
+		shippingOrder.OrderStatus = "devolucion"
+	}
+}
+
+// Implementation of 'GetDeliveryAttempts'.
+func (s *shippingOrderService) GetDeliveryAttempts(ctx context.Context, shippingOrderID int) (*[]DeliveryAttemptOut, error) {
+	// Check if shippingOrder exists.
+	searchedShippingOrder, err := s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil {
+		return nil, fmt.Errorf("There is no shippingOrder with this ID")
+	}
+
+	return s.shippingOrderRepository.GetDeliveryAttempts(ctx, shippingOrderID)
+}
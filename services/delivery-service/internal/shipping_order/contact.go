@@ -0,0 +1,94 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/utils"
+	"fmt"
+)
+
+// Fills the sender, recipient, origin and destination of the order from the address book of its user.
+// The values are copied, later changes to the saved entries do not reach the order.
+func (s *shippingOrderService) snapshotContacts(ctx context.Context, shippingOrderInsert *ShippingOrderInsert) error {
+	if shippingOrderInsert.SenderContactID != nil {
+		if shippingOrderInsert.Sender != nil {
+			return fmt.Errorf("the sender can not be sent together with a saved one")
+		}
+		sender, err := s.contactRepository.GetContact(ctx, *shippingOrderInsert.SenderContactID, shippingOrderInsert.UserID)
+		if err != nil {
+			return utils.FailOnError(err, "contact information could not be retrieved")
+		}
+		if sender == nil {
+			return fmt.Errorf("There is no contact with the ID %d", *shippingOrderInsert.SenderContactID)
+		}
+		shippingOrderInsert.Sender = &ShippingOrderSender{
+			IdSender:       sender.DocumentID,
+			FullNameSender: sender.FullName,
+			PhoneSender:    sender.Phone,
+			EmailSender:    sender.Email,
+		}
+	}
+
+	if shippingOrderInsert.RecipientContactID != nil {
+		if shippingOrderInsert.Recipient != nil {
+			return fmt.Errorf("the recipient can not be sent together with a saved one")
+		}
+		recipient, err := s.contactRepository.GetContact(ctx, *shippingOrderInsert.RecipientContactID, shippingOrderInsert.UserID)
+		if err != nil {
+			return utils.FailOnError(err, "contact information could not be retrieved")
+		}
+		if recipient == nil {
+			return fmt.Errorf("There is no contact with the ID %d", *shippingOrderInsert.RecipientContactID)
+		}
+		shippingOrderInsert.Recipient = &ShippingOrderRecipient{
+			IdRecipient:       recipient.DocumentID,
+			FullNameRecipient: recipient.FullName,
+			PhoneRecipient:    recipient.Phone,
+			EmailRecipient:    recipient.Email,
+		}
+	}
+
+	if shippingOrderInsert.OriginAddressID != nil {
+		if shippingOrderInsert.Origin != nil {
+			return fmt.Errorf("the origin can not be sent together with a saved one")
+		}
+		savedAddress, err := s.contactRepository.GetSavedAddress(ctx, *shippingOrderInsert.OriginAddressID, shippingOrderInsert.UserID)
+		if err != nil {
+			return utils.FailOnError(err, "address information could not be retrieved")
+		}
+		if savedAddress == nil {
+			return fmt.Errorf("There is no saved address with the ID %d", *shippingOrderInsert.OriginAddressID)
+		}
+		// Saved addresses without coordinates are geocoded like any other.
+		origin := &ShippingOrderOrigin{
+			AddressOrigin:   savedAddress.Address,
+			ReferenceOrigin: savedAddress.Reference,
+		}
+		if savedAddress.Lat != nil && savedAddress.Lng != nil {
+			origin.LatOrigin, origin.LngOrigin = savedAddress.Lat, savedAddress.Lng
+		}
+		shippingOrderInsert.Origin = origin
+	}
+
+	if shippingOrderInsert.DestinationAddressID != nil {
+		if shippingOrderInsert.Destination != nil {
+			return fmt.Errorf("the destination can not be sent together with a saved one")
+		}
+		savedAddress, err := s.contactRepository.GetSavedAddress(ctx, *shippingOrderInsert.DestinationAddressID, shippingOrderInsert.UserID)
+		if err != nil {
+			return utils.FailOnError(err, "address information could not be retrieved")
+		}
+		if savedAddress == nil {
+			return fmt.Errorf("There is no saved address with the ID %d", *shippingOrderInsert.DestinationAddressID)
+		}
+		destination := &ShippingOrderDestination{
+			AddressDestination:   savedAddress.Address,
+			ReferenceDestination: savedAddress.Reference,
+		}
+		if savedAddress.Lat != nil && savedAddress.Lng != nil {
+			destination.LatDestination, destination.LngDestination = savedAddress.Lat, savedAddress.Lng
+		}
+		shippingOrderInsert.Destination = destination
+	}
+
+	return nil
+}
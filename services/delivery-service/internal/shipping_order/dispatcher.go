@@ -0,0 +1,278 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/courier"
+	"delivery-service/internal/utils"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// State shared by every order of a single dispatch run.
+type dispatcher struct {
+	couriers       []courier.CourierOut
+	workloads      map[int]*CourierWorkload
+	packageLimits  map[string]int
+	weightDistance float64
+	weightWorkload float64
+	weightCapacity float64
+	maxDistanceKm  float64
+}
+
+// Implementation of 'DispatchShippingOrder'.
+func (s *shippingOrderService) DispatchShippingOrder(ctx context.Context, shippingOrderID int, shippingOrderDispatch *ShippingOrderDispatch) (*ShippingOrderDispatchOut, error) {
+	// Check if shippingOrder exists.
+	searchedShippingOrder, err := s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	// Orders of other applications are not dispatched by the caller.
+	if searchedShippingOrder == nil || searchedShippingOrder.Application != shippingOrderDispatch.Application {
+		return nil, fmt.Errorf("There is no shippingOrder with this ID")
+	}
+
+	if searchedShippingOrder.OrderStatus != "creado" {
+		return nil, fmt.Errorf("only created orders can be dispatched")
+	}
+	if searchedShippingOrder.CourierID != nil {
+		return nil, fmt.Errorf("the order already has a courier assigned")
+	}
+	if pickupScheduledLater(searchedShippingOrder, time.Now()) {
+		return nil, fmt.Errorf("the pickup of the order is scheduled for a later day")
+	}
+
+	d, err := s.newDispatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dispatch(ctx, d, searchedShippingOrder, shippingOrderDispatch)
+}
+
+// Implementation of 'DispatchShippingOrders'.
+func (s *shippingOrderService) DispatchShippingOrders(ctx context.Context, shippingOrderDispatch *ShippingOrderDispatch) (*[]ShippingOrderDispatchOut, error) {
+	// Define the number of orders of a run.
+	batchSize, _ := strconv.Atoi(os.Getenv("DISPATCH_BATCH_SIZE"))
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	// The oldest orders of the application are served first, orders picked up on a later day wait for it.
+	shippingOrders, err := s.shippingOrderRepository.GetUndispatchedShippingOrders(ctx, shippingOrderDispatch.Application, endOfDay(time.Now()), batchSize)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+
+	d, err := s.newDispatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every order gets its own result, an order that fails does not stop the ones after it.
+	dispatchOuts := make([]ShippingOrderDispatchOut, 0, len(*shippingOrders))
+	for i := range *shippingOrders {
+		dispatchOut, err := s.dispatch(ctx, d, &(*shippingOrders)[i], shippingOrderDispatch)
+		if err != nil && errors.Is(err, ErrShippingOrderChanged) {
+			dispatchOut.Message = "the order was assigned or changed while processing the batch"
+		} else if err != nil {
+			dispatchOut.Message = err.Error()
+		}
+		dispatchOuts = append(dispatchOuts, *dispatchOut)
+	}
+
+	return &dispatchOuts, nil
+}
+
+// Loads the couriers, their workload and the dispatch settings.
+func (s *shippingOrderService) newDispatcher(ctx context.Context) (*dispatcher, error) {
+	couriers, err := s.courierRepository.GetAvailableCouriers(ctx)
+	if err != nil {
+		return nil, utils.FailOnError(err, "courier information could not be retrieved")
+	}
+
+	// The live location is fresher than the persisted one.
+	for i := range *couriers {
+		position, err := s.locationRepository.GetLocation(ctx, (*couriers)[i].ID)
+		if err != nil {
+			return nil, utils.FailOnError(err, "courier location could not be retrieved")
+		}
+		if position != nil {
+			(*couriers)[i].LastLat = &position.Lat
+			(*couriers)[i].LastLng = &position.Lng
+		}
+	}
+
+	workloads, err := s.shippingOrderRepository.GetCourierWorkloads(ctx)
+	if err != nil {
+		return nil, utils.FailOnError(err, "courier workload could not be retrieved")
+	}
+
+	// Every package size has a limit of at least zero.
+	packageSizes, err := s.packageSizeRepository.GetPackageSize(ctx, 0)
+	if err != nil {
+		return nil, utils.FailOnError(err, "packet size information could not be retrieved")
+	}
+	packageLimits := make(map[string]int)
+	for _, packageSize := range *packageSizes {
+		packageLimits[packageSize.Nemo] = packageSize.Limitvalue
+	}
+
+	// Define dispatch settings, the score adds kilometers, active orders and used capacity.
+	weightDistance, err := strconv.ParseFloat(os.Getenv("DISPATCH_WEIGHT_DISTANCE"), 64)
+	if err != nil {
+		weightDistance = 1
+	}
+	weightWorkload, err := strconv.ParseFloat(os.Getenv("DISPATCH_WEIGHT_WORKLOAD"), 64)
+	if err != nil {
+		weightWorkload = 2
+	}
+	weightCapacity, err := strconv.ParseFloat(os.Getenv("DISPATCH_WEIGHT_CAPACITY"), 64)
+	if err != nil {
+		weightCapacity = 5
+	}
+	maxDistanceKm, _ := strconv.ParseFloat(os.Getenv("DISPATCH_MAX_DISTANCE_KM"), 64)
+
+	return &dispatcher{
+		couriers:       *couriers,
+		workloads:      workloads,
+		packageLimits:  packageLimits,
+		weightDistance: weightDistance,
+		weightWorkload: weightWorkload,
+		weightCapacity: weightCapacity,
+		maxDistanceKm:  maxDistanceKm,
+	}, nil
+}
+
+// Ranks the couriers for one order and assigns the best one if requested.
+// The result is returned even on error, so a batch can report it.
+func (s *shippingOrderService) dispatch(ctx context.Context, d *dispatcher, shippingOrder *ShippingOrderOut, shippingOrderDispatch *ShippingOrderDispatch) (*ShippingOrderDispatchOut, error) {
+	dispatchOut := &ShippingOrderDispatchOut{
+		ShippingOrderID: shippingOrder.ID,
+		Suggestions:     []*DispatchSuggestion{},
+	}
+
+	suggestions := d.rank(shippingOrder)
+	if len(suggestions) == 0 {
+		dispatchOut.Message = "there is no available courier for this order"
+		return dispatchOut, nil
+	}
+
+	limit := shippingOrderDispatch.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	dispatchOut.Suggestions = suggestions
+
+	if shippingOrderDispatch.Mode != "assign" {
+		return dispatchOut, nil
+	}
+
+	// Assign the best ranked courier.
+	best := suggestions[0]
+	shippingOrderAssigned := &ShippingOrder{
+		CourierID:   &best.CourierID,
+		UpdatedUser: shippingOrderDispatch.UpdatedUser,
+		UpdatedAt:   time.Now(),
+	}
+	err := s.shippingOrderRepository.DispatchShippingOrderCourier(ctx, shippingOrder.ID, shippingOrderAssigned)
+	if err != nil && errors.Is(err, ErrShippingOrderChanged) {
+		return dispatchOut, err
+	}
+	if err != nil {
+		return dispatchOut, utils.FailOnError(err, "could not update record")
+	}
+
+	d.reserve(best.CourierID, d.load(shippingOrder))
+	dispatchOut.AssignedCourierID = &best.CourierID
+
+	return dispatchOut, nil
+}
+
+// Scores the couriers that can carry the order, the lower the score the better.
+func (d *dispatcher) rank(shippingOrder *ShippingOrderOut) []*DispatchSuggestion {
+	required := d.load(shippingOrder)
+
+	var suggestions []*DispatchSuggestion
+	for i := range d.couriers {
+		candidate := &d.couriers[i]
+
+		// Couriers of other applications do not carry the order.
+		if candidate.Application != shippingOrder.Application {
+			continue
+		}
+
+		// Couriers that never reported their position or have no capacity can not be ranked.
+		if candidate.LastLat == nil || candidate.LastLng == nil || candidate.CapacityKg <= 0 {
+			continue
+		}
+
+		workload := d.workloads[candidate.ID]
+		if workload == nil {
+			workload = &CourierWorkload{CourierID: candidate.ID}
+		}
+
+		remainingCapacityKg := candidate.CapacityKg - workload.LoadKg
+		if remainingCapacityKg < required {
+			continue
+		}
+
+		distanceKm := utils.Haversine(*candidate.LastLat, *candidate.LastLng, *shippingOrder.Origin.LatOrigin, *shippingOrder.Origin.LngOrigin)
+		if d.maxDistanceKm > 0 && distanceKm > d.maxDistanceKm {
+			continue
+		}
+
+		usedCapacity := float64(workload.LoadKg+required) / float64(candidate.CapacityKg)
+		score := d.weightDistance*distanceKm + d.weightWorkload*float64(workload.ActiveOrders) + d.weightCapacity*usedCapacity
+
+		suggestions = append(suggestions, &DispatchSuggestion{
+			CourierID:           candidate.ID,
+			FullName:            candidate.FullName,
+			VehicleType:         candidate.VehicleType,
+			DistanceKm:          distanceKm,
+			ActiveOrders:        workload.ActiveOrders,
+			RemainingCapacityKg: remainingCapacityKg,
+			Score:               score,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score < suggestions[j].Score
+	})
+
+	return suggestions
+}
+
+// Kilograms a courier needs for the order, the limit of the size of every parcel not cancelled.
+func (d *dispatcher) load(shippingOrder *ShippingOrderOut) int {
+	if len(shippingOrder.Parcels) == 0 {
+		return d.packageLimits[shippingOrder.Package.PackageSize]
+	}
+
+	loadKg := 0
+	for _, parcel := range shippingOrder.Parcels {
+		if parcel.ParcelStatus != "cancelado" {
+			loadKg += d.packageLimits[parcel.PackageSize]
+		}
+	}
+
+	return loadKg
+}
+
+// Counts an assigned order in the workload of its courier for the rest of the run.
+func (d *dispatcher) reserve(courierID int, loadKg int) {
+	workload := d.workloads[courierID]
+	if workload == nil {
+		workload = &CourierWorkload{CourierID: courierID}
+		d.workloads[courierID] = workload
+	}
+
+	workload.ActiveOrders++
+	workload.LoadKg += loadKg
+}
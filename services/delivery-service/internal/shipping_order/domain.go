@@ -3,51 +3,122 @@ package shipping_order
 import (
 	"context"
 	"database/sql"
+	"delivery-service/internal/address"
+	"delivery-service/internal/courier"
+	"delivery-service/internal/outbox"
+	"delivery-service/internal/verification"
+	"io"
+	"mime/multipart"
 	"time"
 )
 
 // ShippingOrder struct to describe ShippingOrder object.
 type ShippingOrder struct {
-	ID                   int       `db:"id"`
-	IdSender             string    `db:"idSender"`
-	FullNameSender       string    `db:"fullNameSender"`
-	PhoneSender          string    `db:"phoneSender"`
-	EmailSender          string    `db:"emailSender"`
-	IdRecipient          string    `db:"idRecipient"`
-	FullNameRecipient    string    `db:"fullNameRecipient"`
-	PhoneRecipient       string    `db:"phoneRecipient"`
-	EmailRecipient       string    `db:"emailRecipient"`
-	LatOrigin            string    `db:"latOrigin"`
-	LngOrigin            string    `db:"lngOrigin"`
-	AddressOrigin        string    `db:"addressOrigin"`
-	CountryOrigin        string    `db:"countryOrigin"`
-	ZipcodeOrigin        string    `db:"zipcodeOrigin"`
-	ReferenceOrigin      string    `db:"referenceOrigin"`
-	LatDestination       string    `db:"latDestination"`
-	LngDestination       string    `db:"lngDestination"`
-	AddressDestination   string    `db:"addressDestination"`
-	CountryDestination   string    `db:"countryDestination"`
-	ZipcodeDestination   string    `db:"zipcodeDestination"`
-	ReferenceDestination string    `db:"referenceDestination"`
-	PackageSize          string    `db:"packageSize"`
-	QuantityProduct      int       `db:"quantityProduct"`
-	WeightProduct        int       `db:"weightProduct"`
-	OrderStatus          string    `db:"orderStatus"`
-	CreatedUser          string    `db:"created_user"`
-	CreatedAt            time.Time `db:"created_at"`
-	UpdatedUser          string    `db:"updated_user"`
-	UpdatedAt            time.Time `db:"updated_at"`
-	Status               string    `db:"status"`
+	ID                    int                    `db:"id"`
+	IdSender              string                 `db:"idSender"`
+	FullNameSender        string                 `db:"fullNameSender"`
+	PhoneSender           string                 `db:"phoneSender"`
+	EmailSender           string                 `db:"emailSender"`
+	IdRecipient           string                 `db:"idRecipient"`
+	FullNameRecipient     string                 `db:"fullNameRecipient"`
+	PhoneRecipient        string                 `db:"phoneRecipient"`
+	EmailRecipient        string                 `db:"emailRecipient"`
+	LatOrigin             float64                `db:"latOrigin"`
+	LngOrigin             float64                `db:"lngOrigin"`
+	AddressOrigin         string                 `db:"addressOrigin"`
+	NumberOrigin          string                 `db:"numberOrigin"`
+	DistrictOrigin        string                 `db:"districtOrigin"`
+	CityOrigin            string                 `db:"cityOrigin"`
+	RegionOrigin          string                 `db:"regionOrigin"`
+	CountryOrigin         string                 `db:"countryOrigin"`
+	ZipcodeOrigin         string                 `db:"zipcodeOrigin"`
+	ReferenceOrigin       string                 `db:"referenceOrigin"`
+	LatDestination        float64                `db:"latDestination"`
+	LngDestination        float64                `db:"lngDestination"`
+	AddressDestination    string                 `db:"addressDestination"`
+	NumberDestination     string                 `db:"numberDestination"`
+	DistrictDestination   string                 `db:"districtDestination"`
+	CityDestination       string                 `db:"cityDestination"`
+	RegionDestination     string                 `db:"regionDestination"`
+	CountryDestination    string                 `db:"countryDestination"`
+	ZipcodeDestination    string                 `db:"zipcodeDestination"`
+	ReferenceDestination  string                 `db:"referenceDestination"`
+	PackageSize           string                 `db:"packageSize"`
+	QuantityProduct       int                    `db:"quantityProduct"`
+	WeightProduct         int                    `db:"weightProduct"`
+	OriginZoneID          string                 `db:"originZoneId"`
+	DestinationZoneID     string                 `db:"destinationZoneId"`
+	ServiceLevel          string                 `db:"serviceLevel"`
+	Price                 float64                `db:"price"`
+	DeclaredValue         float64                `db:"declaredValue"`
+	Currency              string                 `db:"currency"`
+	Insured               bool                   `db:"insured"`
+	InsurancePremium      float64                `db:"insurancePremium"`
+	CashOnDelivery        float64                `db:"cashOnDelivery"`
+	CashCollected         *float64               `db:"cashCollected"`
+	CashCollectedAt       *time.Time             `db:"cashCollectedAt"`
+	DeliveryAttempts      int                    `db:"deliveryAttempts"`
+	PickupFrom            *time.Time             `db:"pickupFrom"`
+	PickupTo              *time.Time             `db:"pickupTo"`
+	TrackingCode          string                 `db:"trackingCode"`
+	IdempotencyKey        *string                `db:"idempotencyKey"`
+	OrderStatus           string                 `db:"orderStatus"`
+	StationID             *int                   `db:"-"`
+	CourierID             *int                   `db:"courierId"`
+	EstimatedDeliveryFrom *time.Time             `db:"estimatedDeliveryFrom"`
+	EstimatedDeliveryTo   *time.Time             `db:"estimatedDeliveryTo"`
+	OptOutSender          bool                   `db:"optOutSender"`
+	OptOutRecipient       bool                   `db:"optOutRecipient"`
+	Language              string                 `db:"language"`
+	Application           string                 `db:"application"`
+	CreatedUser           string                 `db:"created_user"`
+	CreatedAt             time.Time              `db:"created_at"`
+	UpdatedUser           string                 `db:"updated_user"`
+	UpdatedAt             time.Time              `db:"updated_at"`
+	Status                string                 `db:"status"`
+	Parcels               []*ShippingOrderParcel `db:"-"`
+	ProofOfDelivery       *ProofOfDelivery       `db:"-"`
+	DeliveryAttempt       *DeliveryAttempt       `db:"-"`
+}
+
+// ShippingOrderParcel struct to describe one of the boxes of a shipping_order.
+type ShippingOrderParcel struct {
+	ID              int                     `db:"id"`
+	ShippingOrderID int                     `db:"shippingOrderId"`
+	Sequence        int                     `db:"sequence"`
+	PackageSize     string                  `db:"packageSize"`
+	QuantityProduct int                     `db:"quantityProduct"`
+	WeightProduct   int                     `db:"weightProduct"`
+	DeclaredValue   float64                 `db:"declaredValue"`
+	ParcelStatus    string                  `db:"parcelStatus"`
+	StationID       *int                    `db:"stationId"`
+	CreatedAt       time.Time               `db:"created_at"`
+	UpdatedUser     string                  `db:"updated_user"`
+	UpdatedAt       time.Time               `db:"updated_at"`
+	Items           []*ShippingOrderItemOut `db:"-"`
 }
 
 // struct to describe register a new shipping_order.
 type ShippingOrderInsert struct {
-	Sender      *ShippingOrderSender      `json:"sender"`
-	Recipient   *ShippingOrderRecipient   `json:"recipient"`
-	Origin      *ShippingOrderOrigin      `json:"origin"`
-	Destination *ShippingOrderDestination `json:"destination"`
-	Package     *ShippingOrderPackage     `json:"package"`
-	CreatedUser string                    `json:"createdUser" validate:"required,lte=200"`
+	Sender               *ShippingOrderSender        `json:"sender" validate:"required_without=SenderContactID"`
+	Recipient            *ShippingOrderRecipient     `json:"recipient" validate:"required_without=RecipientContactID"`
+	Origin               *ShippingOrderOrigin        `json:"origin" validate:"required_without=OriginAddressID"`
+	Destination          *ShippingOrderDestination   `json:"destination" validate:"required_without=DestinationAddressID"`
+	SenderContactID      *int                        `json:"senderContactId" validate:"omitempty,gt=0"`
+	RecipientContactID   *int                        `json:"recipientContactId" validate:"omitempty,gt=0"`
+	OriginAddressID      *int                        `json:"originAddressId" validate:"omitempty,gt=0"`
+	DestinationAddressID *int                        `json:"destinationAddressId" validate:"omitempty,gt=0"`
+	Package              *ShippingOrderPackage       `json:"package" validate:"required_without=Packages"`
+	Packages             []*ShippingOrderPackage     `json:"packages" validate:"required_without=Package,omitempty,max=20,dive,required"`
+	Notifications        *ShippingOrderNotifications `json:"notifications"`
+	ServiceLevel         string                      `json:"serviceLevel" validate:"omitempty,eq=standard|eq=express|eq=same_day"`
+	Insurance            bool                        `json:"insurance"`
+	CashOnDelivery       float64                     `json:"cashOnDelivery" validate:"omitempty,gt=0"`
+	Pickup               *ShippingOrderWindow        `json:"pickup"`
+	IdempotencyKey       string                      `json:"-"`
+	UserID               int                         `json:"-"`
+	Application          string                      `json:"-"`
+	CreatedUser          string                      `json:"createdUser" validate:"required,lte=200"`
 }
 
 type ShippingOrderSender struct {
@@ -65,32 +136,161 @@ type ShippingOrderRecipient struct {
 }
 
 type ShippingOrderOrigin struct {
-	LatOrigin       string `json:"latOrigin" validate:"required,latitude"`
-	LngOrigin       string `json:"lngOrigin" validate:"required,longitude"`
-	AddressOrigin   string `json:"addressOrigin" validate:"required,lte=200"`
-	CountryOrigin   string `json:"countryOrigin" validate:"required,lte=200"`
-	ZipcodeOrigin   string `json:"zipcodeOrigin" validate:"required,lte=200"`
-	ReferenceOrigin string `json:"referenceOrigin" validate:"required,lte=200"`
+	LatOrigin       *float64         `json:"latOrigin" validate:"required_without=AddressOrigin,required_with=LngOrigin,omitempty,latitude"`
+	LngOrigin       *float64         `json:"lngOrigin" validate:"required_without=AddressOrigin,required_with=LatOrigin,omitempty,longitude"`
+	AddressOrigin   *address.Address `json:"addressOrigin" validate:"required_without=LatOrigin"`
+	ReferenceOrigin string           `json:"referenceOrigin" validate:"required,lte=200"`
 }
 
 type ShippingOrderDestination struct {
-	LatDestination       string `json:"latDestination" validate:"required,latitude"`
-	LngDestination       string `json:"lngDestination" validate:"required,longitude"`
-	AddressDestination   string `json:"addressDestination" validate:"required,lte=200"`
-	CountryDestination   string `json:"countryDestination" validate:"required,lte=200"`
-	ZipcodeDestination   string `json:"zipcodeDestination" validate:"required,lte=200"`
-	ReferenceDestination string `json:"referenceDestination" validate:"required,lte=200"`
+	LatDestination       *float64         `json:"latDestination" validate:"required_without=AddressDestination,required_with=LngDestination,omitempty,latitude"`
+	LngDestination       *float64         `json:"lngDestination" validate:"required_without=AddressDestination,required_with=LatDestination,omitempty,longitude"`
+	AddressDestination   *address.Address `json:"addressDestination" validate:"required_without=LatDestination"`
+	ReferenceDestination string           `json:"referenceDestination" validate:"required,lte=200"`
 }
 
 type ShippingOrderPackage struct {
-	PackageSize     string `json:"packageSize" validate:"required,lte=1,eq=S|eq=M|eq=L"`
-	QuantityProduct int    `json:"quantityProduct" validate:"required,numeric,gt=0"`
-	WeightProduct   int    `json:"weightProduct" validate:"required,numeric,gt=0"`
+	PackageSize     string               `json:"packageSize" validate:"required,lte=1,eq=S|eq=M|eq=L"`
+	QuantityProduct int                  `json:"quantityProduct" validate:"required_without=Items,omitempty,numeric,gt=0"`
+	WeightProduct   int                  `json:"weightProduct" validate:"required,numeric,gt=0"`
+	DeclaredValue   float64              `json:"declaredValue" validate:"omitempty,gte=0"`
+	Currency        string               `json:"currency" validate:"omitempty,len=3,alpha,uppercase"`
+	Items           []*ShippingOrderItem `json:"items,omitempty" validate:"omitempty,max=100,dive,required"`
+}
+
+// ShippingOrderWindow struct to describe a window of a day, the pickup scheduled by the sender or the delivery chosen by the recipient.
+// Times are in the time zone of the country of that end of the order.
+type ShippingOrderWindow struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+	From string `json:"from" validate:"required,datetime=15:04"`
+	To   string `json:"to" validate:"required,datetime=15:04"`
+}
+
+// ShippingOrderItem struct to describe a product of the catalog carried in a package.
+type ShippingOrderItem struct {
+	ProductID int `json:"productId" validate:"required,gt=0"`
+	Quantity  int `json:"quantity" validate:"required,gt=0"`
+}
+
+// ShippingOrderItemOut struct to describe a product as it was when the order was created.
+type ShippingOrderItemOut struct {
+	ProductID int     `json:"productId"`
+	Name      string  `json:"name"`
+	Quantity  int     `json:"quantity"`
+	UnitValue float64 `json:"unitValue"`
+}
+
+// ShippingOrderNotifications struct to describe the notification preferences of a shipping_order.
+type ShippingOrderNotifications struct {
+	OptOutSender    bool   `json:"optOutSender"`
+	OptOutRecipient bool   `json:"optOutRecipient"`
+	Language        string `json:"language" validate:"omitempty,eq=es|eq=en"`
+}
+
+// ShippingOrderNotificationsUpdate struct to describe update the notification preferences.
+type ShippingOrderNotificationsUpdate struct {
+	ShippingOrderNotifications
+	UpdatedUser string `json:"updatedUser" validate:"required,lte=200"`
 }
 
 // ShippingOrderUpdate struct to describe update shipping_order.
+// Orders are delivered with their proof of delivery, sent as a multipart form,
+// and with the amount the courier collected when they carry cash on delivery.
+// A failed delivery attempt states its reason, an order arriving at a station states which one.
 type ShippingOrderUpdate struct {
-	OrderStatus string `json:"orderStatus" validate:"required,lte=200,eq=recolectado|eq=en_estacion|eq=en_ruta|eq=entregado"`
+	OrderStatus     string                 `json:"orderStatus" form:"orderStatus" validate:"required,lte=200,eq=recolectado|eq=en_estacion|eq=en_ruta|eq=entregado|eq=intento_fallido|eq=devuelto"`
+	StationID       int                    `json:"stationId" form:"stationId" validate:"required_if=OrderStatus en_estacion,omitempty,gt=0"`
+	AttemptReason   string                 `json:"attemptReason" form:"attemptReason" validate:"required_if=OrderStatus intento_fallido,omitempty,eq=destinatario_ausente|eq=direccion_incorrecta|eq=rechazado|eq=inaccesible|eq=otro"`
+	AttemptNotes    string                 `json:"attemptNotes" form:"attemptNotes" validate:"lte=500"`
+	CashCollected   *float64               `json:"cashCollected" form:"cashCollected" validate:"omitempty,gte=0"`
+	ProofOfDelivery *ProofOfDeliveryInsert `json:"-" form:"-"`
+	UserID          int                    `json:"-" form:"-"`
+	UpdatedUser     string                 `json:"updatedUser" form:"updatedUser" validate:"required,lte=200"`
+}
+
+// ShippingOrderParcelUpdate struct to describe update one parcel of a shipping_order.
+type ShippingOrderParcelUpdate struct {
+	ParcelStatus    string                 `json:"parcelStatus" form:"parcelStatus" validate:"required,lte=200,eq=recolectado|eq=en_estacion|eq=en_ruta|eq=entregado|eq=cancelado"`
+	StationID       int                    `json:"stationId" form:"stationId" validate:"required_if=ParcelStatus en_estacion,omitempty,gt=0"`
+	CashCollected   *float64               `json:"cashCollected" form:"cashCollected" validate:"omitempty,gte=0"`
+	ProofOfDelivery *ProofOfDeliveryInsert `json:"-" form:"-"`
+	UserID          int                    `json:"-" form:"-"`
+	UpdatedUser     string                 `json:"updatedUser" form:"updatedUser" validate:"required,lte=200"`
+}
+
+// DeliveryAttempt struct to describe a failed attempt to deliver a shipping_order.
+type DeliveryAttempt struct {
+	ShippingOrderID int       `db:"shippingOrderId"`
+	Attempt         int       `db:"attempt"`
+	Reason          string    `db:"reason"`
+	Notes           string    `db:"notes"`
+	CourierID       *int      `db:"courierId"`
+	CreatedUser     string    `db:"created_user"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+type DeliveryAttemptOut struct {
+	Attempt     int       `json:"attempt"`
+	Reason      string    `json:"reason"`
+	Notes       string    `json:"notes"`
+	CourierID   *int      `json:"courierId"`
+	CreatedUser string    `json:"created_user"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ProofOfDeliveryInsert struct to describe the handover of an order, a signature and/or a photo are required.
+type ProofOfDeliveryInsert struct {
+	ReceiverName     string                `form:"receiverName" validate:"required,lte=200"`
+	ReceiverDocument string                `form:"receiverDocument" validate:"required,lte=200"`
+	Lat              *float64              `form:"lat" validate:"required,latitude"`
+	Lng              *float64              `form:"lng" validate:"required,longitude"`
+	Signature        *multipart.FileHeader `form:"-"`
+	Photo            *multipart.FileHeader `form:"-"`
+}
+
+// ProofOfDelivery struct to describe the stored handover of an order, its images live in the blob store.
+type ProofOfDelivery struct {
+	ShippingOrderID  int       `db:"shippingOrderId"`
+	ReceiverName     string    `db:"receiverName"`
+	ReceiverDocument string    `db:"receiverDocument"`
+	Lat              float64   `db:"lat"`
+	Lng              float64   `db:"lng"`
+	SignatureKey     string    `db:"signatureKey"`
+	PhotoKey         string    `db:"photoKey"`
+	CreatedUser      string    `db:"created_user"`
+	CreatedAt        time.Time `db:"created_at"`
+}
+
+type ProofOfDeliveryOut struct {
+	ShippingOrderID  int       `json:"shippingOrderId"`
+	ReceiverName     string    `json:"receiverName"`
+	ReceiverDocument string    `json:"receiverDocument"`
+	Lat              float64   `json:"lat"`
+	Lng              float64   `json:"lng"`
+	DistanceKm       float64   `json:"distanceKm"`
+	SignatureURL     string    `json:"signatureUrl,omitempty"`
+	PhotoURL         string    `json:"photoUrl,omitempty"`
+	CreatedUser      string    `json:"created_user"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type ShippingOrderParcelOut struct {
+	ID              int                     `json:"id"`
+	Sequence        int                     `json:"sequence"`
+	PackageSize     string                  `json:"packageSize"`
+	QuantityProduct int                     `json:"quantityProduct"`
+	WeightProduct   int                     `json:"weightProduct"`
+	DeclaredValue   float64                 `json:"declaredValue"`
+	Items           []*ShippingOrderItemOut `json:"items"`
+	ParcelStatus    string                  `json:"parcelStatus"`
+	StationID       *int                    `json:"stationId"`
+	UpdatedUser     string                  `json:"updated_user"`
+	UpdatedAt       time.Time               `json:"updated_at"`
+}
+
+// ShippingOrderCourierAssign struct to describe assign a courier to a shipping_order.
+type ShippingOrderCourierAssign struct {
+	CourierID   int    `json:"courierId" validate:"required,gt=0"`
 	UpdatedUser string `json:"updatedUser" validate:"required,lte=200"`
 }
 
@@ -101,27 +301,334 @@ type ShippingOrderCancel struct {
 	UpdatedUser string `json:"updatedUser" validate:"required,lte=200"`
 }
 
+// ShippingOrderStatusBatch struct to describe a status update over several shipping_orders.
+type ShippingOrderStatusBatch struct {
+	ShippingOrderIDs []int  `json:"shippingOrderIds" validate:"required,min=1,max=500,dive,gt=0"`
+	OrderStatus      string `json:"orderStatus" validate:"required,lte=200,eq=recolectado|eq=en_estacion|eq=en_ruta|eq=entregado|eq=devuelto"`
+	StationID        int    `json:"stationId" validate:"required_if=OrderStatus en_estacion,omitempty,gt=0"`
+	Mode             string `json:"mode" validate:"required,eq=atomic|eq=partial"`
+	UserID           int    `json:"-"`
+	UpdatedUser      string `json:"updatedUser" validate:"required,lte=200"`
+}
+
+// ShippingOrderStatusResult struct to describe the outcome of one order in a batch.
+type ShippingOrderStatusResult struct {
+	ShippingOrderID int    `json:"shippingOrderId"`
+	Success         bool   `json:"success"`
+	Message         string `json:"message,omitempty"`
+}
+
+type ShippingOrderStatusBatchOut struct {
+	Committed bool                         `json:"committed"`
+	Updated   int                          `json:"updated"`
+	Failed    int                          `json:"failed"`
+	Results   []*ShippingOrderStatusResult `json:"results"`
+}
+
+// ShippingOrderDispatch struct to describe a request to the dispatcher.
+type ShippingOrderDispatch struct {
+	Mode        string `json:"mode" validate:"required,eq=suggest|eq=assign"`
+	Limit       int    `json:"limit" validate:"omitempty,gt=0,lte=50"`
+	Application string `json:"-"`
+	UpdatedUser string `json:"updatedUser" validate:"required,lte=200"`
+}
+
+// CourierWorkload struct to describe the orders a courier is carrying.
+type CourierWorkload struct {
+	CourierID    int `db:"courierId"`
+	ActiveOrders int `db:"activeOrders"`
+	LoadKg       int `db:"loadKg"`
+}
+
+// DispatchSuggestion struct to describe a courier proposed for a shipping_order, lower scores first.
+type DispatchSuggestion struct {
+	CourierID           int     `json:"courierId"`
+	FullName            string  `json:"fullName"`
+	VehicleType         string  `json:"vehicleType"`
+	DistanceKm          float64 `json:"distanceKm"`
+	ActiveOrders        int     `json:"activeOrders"`
+	RemainingCapacityKg int     `json:"remainingCapacityKg"`
+	Score               float64 `json:"score"`
+}
+
+type ShippingOrderDispatchOut struct {
+	ShippingOrderID   int                   `json:"shippingOrderId"`
+	AssignedCourierID *int                  `json:"assignedCourierId,omitempty"`
+	Suggestions       []*DispatchSuggestion `json:"suggestions"`
+	Message           string                `json:"message,omitempty"`
+}
+
+type ShippingOrderLocationOut struct {
+	ShippingOrderID       int                      `json:"shippingOrderId"`
+	OrderStatus           string                   `json:"orderStatus"`
+	Position              *courier.CourierPosition `json:"position"`
+	EstimatedDeliveryFrom *time.Time               `json:"estimatedDeliveryFrom"`
+	EstimatedDeliveryTo   *time.Time               `json:"estimatedDeliveryTo"`
+}
+
+// RouteStop struct to describe a pickup or drop of a courier route.
+type RouteStop struct {
+	Sequence        int     `json:"sequence"`
+	ShippingOrderID int     `json:"shippingOrderId"`
+	StopType        string  `json:"stopType"`
+	Lat             float64 `json:"lat"`
+	Lng             float64 `json:"lng"`
+	Address         string  `json:"address"`
+	DistanceKm      float64 `json:"distanceKm"`
+	CumulativeKm    float64 `json:"cumulativeKm"`
+
+	// Pickup window agreed with the sender, only on pickups.
+	WindowFrom *time.Time `json:"windowFrom,omitempty"`
+	WindowTo   *time.Time `json:"windowTo,omitempty"`
+
+	// Preferences of the recipient, only on drops.
+	Preferences *DeliveryPreferencesOut `json:"preferences,omitempty"`
+}
+
+type CourierRouteOut struct {
+	CourierID       int                      `json:"courierId"`
+	Start           *courier.CourierPosition `json:"start"`
+	TotalDistanceKm float64                  `json:"totalDistanceKm"`
+	Stops           []*RouteStop             `json:"stops"`
+}
+
+// CourierCashQuery struct to describe the period of a cash-on-delivery report.
+type CourierCashQuery struct {
+	From string `query:"from" validate:"required,datetime=2006-01-02"`
+	To   string `query:"to" validate:"required,datetime=2006-01-02"`
+}
+
+// CashOnDeliveryOrder struct to describe the cash a courier collects for an order.
+type CashOnDeliveryOrder struct {
+	ShippingOrderID int        `json:"shippingOrderId"`
+	OrderStatus     string     `json:"orderStatus"`
+	Currency        string     `json:"currency"`
+	CashOnDelivery  float64    `json:"cashOnDelivery"`
+	CashCollected   *float64   `json:"cashCollected"`
+	CashCollectedAt *time.Time `json:"cashCollectedAt"`
+}
+
+// CashOnDeliveryTotal struct to describe the cash of a courier in one currency.
+type CashOnDeliveryTotal struct {
+	Currency  string  `json:"currency"`
+	Collected float64 `json:"collected"`
+	Pending   float64 `json:"pending"`
+}
+
+// CourierCashReportOut struct to describe the cash a courier must hand over.
+// Collected holds the orders delivered in the period, pending the ones still to be delivered.
+type CourierCashReportOut struct {
+	CourierID int                    `json:"courierId"`
+	From      string                 `json:"from"`
+	To        string                 `json:"to"`
+	Collected []*CashOnDeliveryOrder `json:"collected"`
+	Pending   []*CashOnDeliveryOrder `json:"pending"`
+	Totals    []*CashOnDeliveryTotal `json:"totals"`
+}
+
+// StationParcelOut struct to describe a parcel waiting in a station.
+type StationParcelOut struct {
+	ShippingOrderID   int       `json:"shippingOrderId"`
+	TrackingCode      string    `json:"trackingCode"`
+	ParcelID          int       `json:"parcelId"`
+	Sequence          int       `json:"sequence"`
+	PackageSize       string    `json:"packageSize"`
+	WeightProduct     int       `json:"weightProduct"`
+	DestinationZoneID string    `json:"destinationZoneId"`
+	ServiceLevel      string    `json:"serviceLevel"`
+	ArrivedAt         time.Time `json:"arrivedAt"`
+}
+
+// StationInventoryOut struct to describe the parcels a station holds, the oldest arrivals first.
+type StationInventoryOut struct {
+	StationID int                 `json:"stationId"`
+	Code      string              `json:"code"`
+	Name      string              `json:"name"`
+	Capacity  int                 `json:"capacity"`
+	Occupancy int                 `json:"occupancy"`
+	Parcels   []*StationParcelOut `json:"parcels"`
+}
+
+// PickupQuery struct to describe the pickups of a day, in the server time zone.
+type PickupQuery struct {
+	Date   string `query:"date" validate:"required,datetime=2006-01-02"`
+	ZoneID string `query:"zoneId" validate:"omitempty,lte=50"`
+}
+
+// PickupWindowOut struct to describe the orders to pick up in the same window.
+type PickupWindowOut struct {
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	Orders         int                `json:"orders"`
+	WeightProduct  int                `json:"weightProduct"`
+	ShippingOrders []ShippingOrderOut `json:"shippingOrders"`
+}
+
+// ShippingOrderNearbyQuery struct to describe a search of orders around a point.
+type ShippingOrderNearbyQuery struct {
+	End      string   `query:"end" validate:"required,eq=origin|eq=destination"`
+	Lat      *float64 `query:"lat" validate:"required,gte=-90,lte=90"`
+	Lng      *float64 `query:"lng" validate:"required,gte=-180,lte=180"`
+	RadiusKm float64  `query:"radiusKm" validate:"required,gt=0,lte=100"`
+	Limit    int      `query:"limit" validate:"omitempty,gt=0,lte=500"`
+}
+
+// ShippingOrderBoxQuery struct to describe a search of orders inside a bounding box.
+type ShippingOrderBoxQuery struct {
+	End    string   `query:"end" validate:"required,eq=origin|eq=destination"`
+	MinLat *float64 `query:"minLat" validate:"required,gte=-90,lte=90"`
+	MinLng *float64 `query:"minLng" validate:"required,gte=-180,lte=180"`
+	MaxLat *float64 `query:"maxLat" validate:"required,gte=-90,lte=90,gtefield=MinLat"`
+	MaxLng *float64 `query:"maxLng" validate:"required,gte=-180,lte=180,gtefield=MinLng"`
+	Limit  int      `query:"limit" validate:"omitempty,gt=0,lte=500"`
+}
+
 type ShippingOrderOut struct {
-	ID          int                       `json:"id" `
-	Sender      *ShippingOrderSender      `json:"sender"`
-	Recipient   *ShippingOrderRecipient   `json:"recipient"`
-	Origin      *ShippingOrderOrigin      `json:"origin"`
-	Destination *ShippingOrderDestination `json:"destination"`
-	Package     *ShippingOrderPackage     `json:"package"`
-	OrderStatus string                    `json:"orderStatus"`
-	CreatedUser string                    `json:"created_user"`
-	CreatedAt   time.Time                 `json:"created_at"`
-	UpdatedUser string                    `json:"updated_user"`
-	UpdatedAt   time.Time                 `json:"updated_at"`
-	Status      string                    `json:"status"`
+	ID                    int                         `json:"id" `
+	Sender                *ShippingOrderSender        `json:"sender"`
+	Recipient             *ShippingOrderRecipient     `json:"recipient"`
+	Origin                *ShippingOrderOrigin        `json:"origin"`
+	Destination           *ShippingOrderDestination   `json:"destination"`
+	Package               *ShippingOrderPackage       `json:"package"`
+	Parcels               []*ShippingOrderParcelOut   `json:"parcels"`
+	Notifications         *ShippingOrderNotifications `json:"notifications"`
+	OriginZoneID          string                      `json:"originZoneId"`
+	DestinationZoneID     string                      `json:"destinationZoneId"`
+	ServiceLevel          string                      `json:"serviceLevel"`
+	Price                 float64                     `json:"price"`
+	DeclaredValue         float64                     `json:"declaredValue"`
+	Currency              string                      `json:"currency"`
+	Insured               bool                        `json:"insured"`
+	InsurancePremium      float64                     `json:"insurancePremium"`
+	CashOnDelivery        float64                     `json:"cashOnDelivery"`
+	CashCollected         *float64                    `json:"cashCollected"`
+	CashCollectedAt       *time.Time                  `json:"cashCollectedAt"`
+	DeliveryAttempts      int                         `json:"deliveryAttempts"`
+	PickupFrom            *time.Time                  `json:"pickupFrom"`
+	PickupTo              *time.Time                  `json:"pickupTo"`
+	TrackingCode          string                      `json:"trackingCode"`
+	Preferences           *DeliveryPreferencesOut     `json:"preferences"`
+	OrderStatus           string                      `json:"orderStatus"`
+	CourierID             *int                        `json:"courierId"`
+	EstimatedDeliveryFrom *time.Time                  `json:"estimatedDeliveryFrom"`
+	EstimatedDeliveryTo   *time.Time                  `json:"estimatedDeliveryTo"`
+	Application           string                      `json:"application"`
+	CreatedUser           string                      `json:"created_user"`
+	CreatedAt             time.Time                   `json:"created_at"`
+	UpdatedUser           string                      `json:"updated_user"`
+	UpdatedAt             time.Time                   `json:"updated_at"`
+	Status                string                      `json:"status"`
+}
+
+// TrackingCodeInsert struct to describe the channel a recipient asks the verification code through.
+type TrackingCodeInsert struct {
+	Channel string `json:"channel" validate:"required,eq=email|eq=sms"`
+}
+
+// TrackingCodeOut struct to describe where the verification code was sent.
+type TrackingCodeOut struct {
+	Channel   string    `json:"channel"`
+	SentTo    string    `json:"sentTo"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// TrackingVerifyInsert struct to describe the verification code typed by the recipient.
+type TrackingVerifyInsert struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// TrackingOut struct to describe the public view of a shipping_order.
+// Contacts are masked, the preferences are only shown with a verified session.
+type TrackingOut struct {
+	TrackingCode          string                  `json:"trackingCode"`
+	OrderStatus           string                  `json:"orderStatus"`
+	EstimatedDeliveryFrom *time.Time              `json:"estimatedDeliveryFrom"`
+	EstimatedDeliveryTo   *time.Time              `json:"estimatedDeliveryTo"`
+	DeliveryAttempts      int                     `json:"deliveryAttempts"`
+	RecipientEmail        string                  `json:"recipientEmail"`
+	RecipientPhone        string                  `json:"recipientPhone"`
+	PreferencesOpen       bool                    `json:"preferencesOpen"`
+	Preferences           *DeliveryPreferencesOut `json:"preferences,omitempty"`
+}
+
+// DeliveryPreferencesUpdate struct to describe the delivery preferences chosen by the recipient.
+// The whole set is replaced, a pickup point left out sends the order back to its destination.
+type DeliveryPreferencesUpdate struct {
+	DeliveryWindow *ShippingOrderWindow `json:"deliveryWindow"`
+	Instructions   string               `json:"instructions" validate:"omitempty,lte=500"`
+	PickupPoint    *PickupPointInsert   `json:"pickupPoint"`
+	Neighbour      *AuthorizedNeighbour `json:"neighbour"`
+}
+
+// PickupPointInsert struct to describe the pickup point an order is redirected to.
+type PickupPointInsert struct {
+	Name    string           `json:"name" validate:"required,lte=200"`
+	Address *address.Address `json:"address" validate:"required"`
+}
+
+// AuthorizedNeighbour struct to describe who can receive the order instead of the recipient.
+type AuthorizedNeighbour struct {
+	FullName string `json:"fullName" validate:"required,lte=200"`
+	Document string `json:"document" validate:"omitempty,lte=50"`
+	Address  string `json:"address" validate:"required,lte=200"`
+}
+
+// DeliveryPreferences struct to describe the delivery preferences stored for a shipping_order.
+type DeliveryPreferences struct {
+	ShippingOrderID    int
+	DeliveryWindowFrom *time.Time
+	DeliveryWindowTo   *time.Time
+	Instructions       string
+	Neighbour          *AuthorizedNeighbour
+	PickupPoint        *PickupPoint
+	UpdatedAt          time.Time
+}
+
+// PickupPoint struct to describe the pickup point of the preferences, located and inside a service zone.
+type PickupPoint struct {
+	Name    string
+	Address *address.Address
+	Lat     float64
+	Lng     float64
+	ZoneID  string
+}
+
+// DeliveryPreferencesOut struct to describe the delivery preferences of a shipping_order.
+type DeliveryPreferencesOut struct {
+	DeliveryWindowFrom *time.Time           `json:"deliveryWindowFrom"`
+	DeliveryWindowTo   *time.Time           `json:"deliveryWindowTo"`
+	Instructions       string               `json:"instructions"`
+	Neighbour          *AuthorizedNeighbour `json:"neighbour"`
+	PickupPoint        string               `json:"pickupPoint"`
+	UpdatedAt          time.Time            `json:"updatedAt"`
 }
 
 // Our repository will implement these methods.
 type ShippingOrderRepository interface {
 	GetShippingOrder(ctx context.Context, shippingOrderID int) (*ShippingOrderOut, error)
 	GetSenderShippingOrder(ctx context.Context, shippingOrderID int, idSender string) (*ShippingOrderOut, error)
-	CreateShippingOrder(ctx context.Context, shipping_order *ShippingOrder) (sql.Result, error)
-	UpdateShippingOrder(ctx context.Context, shippingOrderID int, shipping_order *ShippingOrder) error
+	GetIdempotentShippingOrder(ctx context.Context, application string, idempotencyKey string) (*ShippingOrderOut, error)
+	CreateShippingOrder(ctx context.Context, shipping_order *ShippingOrder, event *outbox.Event) (sql.Result, error)
+	UpdateShippingOrder(ctx context.Context, shippingOrderID int, previousOrderStatus string, shipping_order *ShippingOrder, event *outbox.Event) error
+	UpdateShippingOrdersStatus(ctx context.Context, shippingOrderIDs []int, previousOrderStatuses []string, shipping_order *ShippingOrder, events []*outbox.Event, atomic bool) (map[int]error, error)
+	UpdateShippingOrderNotifications(ctx context.Context, shippingOrderID int, shipping_order *ShippingOrder) error
+	UpdateShippingOrderCourier(ctx context.Context, shippingOrderID int, shipping_order *ShippingOrder) error
+	DispatchShippingOrderCourier(ctx context.Context, shippingOrderID int, shipping_order *ShippingOrder) error
+	UpdateShippingOrderEstimate(ctx context.Context, shippingOrderID int, shipping_order *ShippingOrder) error
+	UpdateShippingOrderParcel(ctx context.Context, shippingOrderID int, parcelID int, previousOrderStatus string, previousParcelStatus string, parcel *ShippingOrderParcel, shipping_order *ShippingOrder, event *outbox.Event) error
+	GetUndispatchedShippingOrders(ctx context.Context, application string, pickupBefore time.Time, limit int) (*[]ShippingOrderOut, error)
+	GetCourierWorkloads(ctx context.Context) (map[int]*CourierWorkload, error)
+	GetCourierShippingOrders(ctx context.Context, courierID int) (*[]ShippingOrderOut, error)
+	GetShippingOrdersNearby(ctx context.Context, end string, lat float64, lng float64, radiusKm float64, limit int) (*[]ShippingOrderOut, error)
+	GetShippingOrdersInBox(ctx context.Context, end string, minLat float64, minLng float64, maxLat float64, maxLng float64, limit int) (*[]ShippingOrderOut, error)
+	GetCourierCashOnDelivery(ctx context.Context, courierID int, from time.Time, to time.Time) (*[]CashOnDeliveryOrder, error)
+	GetProofOfDelivery(ctx context.Context, shippingOrderID int) (*ProofOfDelivery, error)
+	GetDeliveryAttempts(ctx context.Context, shippingOrderID int) (*[]DeliveryAttemptOut, error)
+	GetShippingOrdersToPickUp(ctx context.Context, from time.Time, to time.Time, zoneID string) (*[]ShippingOrderOut, error)
+	GetTrackedShippingOrder(ctx context.Context, trackingCode string) (*ShippingOrderOut, error)
+	UpdateDeliveryPreferences(ctx context.Context, preferences *DeliveryPreferences, event *outbox.Event) error
+	CountStationParcels(ctx context.Context, stationID int) (int, error)
+	GetStationParcels(ctx context.Context, stationID int) ([]*StationParcelOut, error)
 }
 
 // Our use-case or service will implement these methods.
@@ -131,4 +638,24 @@ type ShippingOrderService interface {
 	CreateShippingOrder(ctx context.Context, shippingOrderInsert *ShippingOrderInsert) (*ShippingOrderOut, error)
 	UpdateShippingOrder(ctx context.Context, shippingOrderID int, shippingOrderUpdate *ShippingOrderUpdate) (*ShippingOrderOut, error)
 	CancelShippingOrder(ctx context.Context, shippingOrderID int, shippingOrderCancel *ShippingOrderCancel) error
+	UpdateShippingOrdersStatus(ctx context.Context, shippingOrderStatusBatch *ShippingOrderStatusBatch) (*ShippingOrderStatusBatchOut, error)
+	UpdateShippingOrderNotifications(ctx context.Context, shippingOrderID int, shippingOrderNotificationsUpdate *ShippingOrderNotificationsUpdate) (*ShippingOrderOut, error)
+	AssignShippingOrderCourier(ctx context.Context, shippingOrderID int, shippingOrderCourierAssign *ShippingOrderCourierAssign) (*ShippingOrderOut, error)
+	UpdateShippingOrderParcel(ctx context.Context, shippingOrderID int, parcelID int, shippingOrderParcelUpdate *ShippingOrderParcelUpdate) (*ShippingOrderOut, error)
+	DispatchShippingOrder(ctx context.Context, shippingOrderID int, shippingOrderDispatch *ShippingOrderDispatch) (*ShippingOrderDispatchOut, error)
+	DispatchShippingOrders(ctx context.Context, shippingOrderDispatch *ShippingOrderDispatch) (*[]ShippingOrderDispatchOut, error)
+	GetShippingOrderLocation(ctx context.Context, shippingOrderID int) (*ShippingOrderLocationOut, error)
+	GetCourierRoute(ctx context.Context, courierID int, application string) (*CourierRouteOut, error)
+	GetShippingOrdersNearby(ctx context.Context, shippingOrderNearbyQuery *ShippingOrderNearbyQuery) (*[]ShippingOrderOut, error)
+	GetShippingOrdersInBox(ctx context.Context, shippingOrderBoxQuery *ShippingOrderBoxQuery) (*[]ShippingOrderOut, error)
+	GetCourierCashReport(ctx context.Context, courierID int, application string, courierCashQuery *CourierCashQuery) (*CourierCashReportOut, error)
+	GetProofOfDelivery(ctx context.Context, shippingOrderID int, application string) (*ProofOfDeliveryOut, error)
+	GetProofOfDeliveryFile(ctx context.Context, shippingOrderID int, application string, kind string) (io.ReadCloser, string, error)
+	GetDeliveryAttempts(ctx context.Context, shippingOrderID int) (*[]DeliveryAttemptOut, error)
+	GetPickups(ctx context.Context, pickupQuery *PickupQuery) (*[]PickupWindowOut, error)
+	GetTracking(ctx context.Context, trackingCode string, token string) (*TrackingOut, error)
+	SendTrackingCode(ctx context.Context, trackingCode string, trackingCodeInsert *TrackingCodeInsert) (*TrackingCodeOut, error)
+	VerifyTrackingCode(ctx context.Context, trackingCode string, trackingVerifyInsert *TrackingVerifyInsert) (*verification.Session, error)
+	UpdateDeliveryPreferences(ctx context.Context, trackingCode string, token string, deliveryPreferencesUpdate *DeliveryPreferencesUpdate) (*TrackingOut, error)
+	GetStationInventory(ctx context.Context, stationID int) (*StationInventoryOut, error)
 }
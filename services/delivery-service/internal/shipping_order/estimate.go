@@ -0,0 +1,131 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/courier"
+	"delivery-service/internal/service_level"
+	"delivery-service/internal/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Settings of the promised delivery windows.
+type estimateSettings struct {
+	speedKmh  float64
+	handling  time.Duration
+	window    time.Duration
+	retry     time.Duration
+	sizeExtra map[string]time.Duration
+}
+
+// Reads the estimate settings from the environment.
+func loadEstimateSettings() *estimateSettings {
+	speedKmh, _ := strconv.ParseFloat(os.Getenv("ETA_SPEED_KMH"), 64)
+	if speedKmh <= 0 {
+		speedKmh = 30
+	}
+
+	handlingMinutesCount, err := strconv.Atoi(os.Getenv("ETA_HANDLING_MINUTES"))
+	if err != nil || handlingMinutesCount < 0 {
+		handlingMinutesCount = 120
+	}
+
+	windowMinutesCount, _ := strconv.Atoi(os.Getenv("ETA_WINDOW_MINUTES"))
+	if windowMinutesCount <= 0 {
+		windowMinutesCount = 60
+	}
+
+	// Extra minutes per package size, as "S:0,M:30,L:60".
+	sizeExtra := make(map[string]time.Duration)
+	for _, pair := range strings.Split(os.Getenv("ETA_PACKAGE_EXTRA_MINUTES"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		minutesCount, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		sizeExtra[parts[0]] = time.Minute * time.Duration(minutesCount)
+	}
+
+	// A failed delivery is attempted again after this many hours.
+	retryHoursCount, err := strconv.Atoi(os.Getenv("DELIVERY_RETRY_HOURS"))
+	if err != nil || retryHoursCount < 0 {
+		retryHoursCount = 24
+	}
+
+	return &estimateSettings{
+		speedKmh:  speedKmh,
+		handling:  time.Minute * time.Duration(handlingMinutesCount),
+		window:    time.Minute * time.Duration(windowMinutesCount),
+		retry:     time.Hour * time.Duration(retryHoursCount),
+		sizeExtra: sizeExtra,
+	}
+}
+
+// Computes the promised delivery window of an order from 'now'.
+// While en route the trip starts at the courier position when it is known.
+func estimateDelivery(now time.Time, orderStatus string, serviceLevel *service_level.ServiceLevelOut, origin *ShippingOrderOrigin, destination *ShippingOrderDestination, packageSize string, position *courier.CourierPosition) (time.Time, time.Time) {
+	settings := loadEstimateSettings()
+
+	// The service level handles its orders faster or slower than the default.
+	if serviceLevel != nil && serviceLevel.HandlingMinutes > 0 {
+		settings.handling = time.Minute * time.Duration(serviceLevel.HandlingMinutes)
+	}
+
+	// Time left before the parcel leaves with the courier.
+	var pending time.Duration
+	switch orderStatus {
+	case "creado":
+		pending = settings.handling + settings.sizeExtra[packageSize]
+	case "recolectado", "en_estacion":
+		pending = settings.handling/2 + settings.sizeExtra[packageSize]
+	case "intento_fallido":
+		pending = settings.retry
+	}
+
+	distanceKm := orderDistanceKm(origin, destination)
+	if orderStatus == "en_ruta" && position != nil {
+		distanceKm = utils.Haversine(position.Lat, position.Lng, *destination.LatDestination, *destination.LngDestination)
+	}
+	travel := time.Duration(distanceKm / settings.speedKmh * float64(time.Hour))
+
+	estimatedFrom := now.Add(pending + travel)
+	return estimatedFrom, estimatedFrom.Add(settings.window)
+}
+
+// Recomputes the promised delivery window of an order that moved to a new status.
+func (s *shippingOrderService) refreshDeliveryEstimate(ctx context.Context, shippingOrder *ShippingOrderOut, orderStatus string) error {
+	// Delivered, returned and cancelled orders keep their last promise.
+	if orderStatus == "entregado" || orderStatus == "devolucion" || orderStatus == "devuelto" || orderStatus == "cancelado" {
+		return nil
+	}
+
+	serviceLevel, err := s.serviceLevelRepository.GetServiceLevel(ctx, shippingOrder.ServiceLevel)
+	if err != nil {
+		return utils.FailOnError(err, "service level information could not be retrieved")
+	}
+
+	var position *courier.CourierPosition
+	if orderStatus == "en_ruta" && shippingOrder.CourierID != nil {
+		position, err = s.locationRepository.GetLocation(ctx, *shippingOrder.CourierID)
+		if err != nil {
+			return utils.FailOnError(err, "location could not be retrieved")
+		}
+	}
+
+	estimatedFrom, estimatedTo := estimateDelivery(time.Now(), orderStatus, serviceLevel, shippingOrder.Origin, shippingOrder.Destination, shippingOrder.Package.PackageSize, position)
+
+	// The recipient may have chosen a later window.
+	if preferences := shippingOrder.Preferences; preferences != nil && preferences.DeliveryWindowFrom != nil && estimatedFrom.Before(*preferences.DeliveryWindowFrom) {
+		estimatedFrom, estimatedTo = *preferences.DeliveryWindowFrom, *preferences.DeliveryWindowTo
+	}
+
+	return s.shippingOrderRepository.UpdateShippingOrderEstimate(ctx, shippingOrder.ID, &ShippingOrder{
+		EstimatedDeliveryFrom: &estimatedFrom,
+		EstimatedDeliveryTo:   &estimatedTo,
+	})
+}
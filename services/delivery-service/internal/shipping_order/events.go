@@ -0,0 +1,82 @@
+package shipping_order
+
+import (
+	"delivery-service/internal/outbox"
+	"encoding/json"
+	"time"
+)
+
+// Domain events emitted along the lifecycle of a shipping_order.
+const (
+	EVENT_AGGREGATE_TYPE       = "shipping_order"
+	EVENT_ORDER_CREATED        = "OrderCreated"
+	EVENT_ORDER_STATUS_CHANGED = "OrderStatusChanged"
+	EVENT_ORDER_CANCELLED      = "OrderCancelled"
+
+	EVENT_DELIVERY_PREFERENCES_CHANGED = "DeliveryPreferencesChanged"
+)
+
+// OrderCreated struct to describe the payload of 'OrderCreated'.
+// The ID of the order travels as the aggregate ID of the event.
+type OrderCreated struct {
+	Sender           *ShippingOrderSender      `json:"sender"`
+	Recipient        *ShippingOrderRecipient   `json:"recipient"`
+	Origin           *ShippingOrderOrigin      `json:"origin"`
+	Destination      *ShippingOrderDestination `json:"destination"`
+	Package          *ShippingOrderPackage     `json:"package"`
+	Parcels          []*ShippingOrderPackage   `json:"parcels"`
+	InsurancePremium float64                   `json:"insurancePremium"`
+	CashOnDelivery   float64                   `json:"cashOnDelivery"`
+	TrackingCode     string                    `json:"trackingCode"`
+	OrderStatus      string                    `json:"orderStatus"`
+	CreatedUser      string                    `json:"created_user"`
+	CreatedAt        time.Time                 `json:"created_at"`
+}
+
+// OrderStatusChanged struct to describe the payload of 'OrderStatusChanged'.
+type OrderStatusChanged struct {
+	ShippingOrderID     int       `json:"shippingOrderId"`
+	PreviousOrderStatus string    `json:"previousOrderStatus"`
+	OrderStatus         string    `json:"orderStatus"`
+	StationID           *int      `json:"stationId,omitempty"`
+	CashCollected       *float64  `json:"cashCollected,omitempty"`
+	AttemptReason       string    `json:"attemptReason,omitempty"`
+	DeliveryAttempts    int       `json:"deliveryAttempts,omitempty"`
+	UpdatedUser         string    `json:"updated_user"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// OrderCancelled struct to describe the payload of 'OrderCancelled'.
+type OrderCancelled struct {
+	ShippingOrderID     int       `json:"shippingOrderId"`
+	PreviousOrderStatus string    `json:"previousOrderStatus"`
+	Refund              string    `json:"refund"`
+	UpdatedUser         string    `json:"updated_user"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// DeliveryPreferencesChanged struct to describe the payload of 'DeliveryPreferencesChanged'.
+// The destination is sent when the order is redirected to a pickup point.
+type DeliveryPreferencesChanged struct {
+	ShippingOrderID int                       `json:"shippingOrderId"`
+	Preferences     *DeliveryPreferencesOut   `json:"preferences"`
+	Destination     *ShippingOrderDestination `json:"destination,omitempty"`
+	UpdatedAt       time.Time                 `json:"updated_at"`
+}
+
+// Builds an outbox event for a shipping_order of the given application.
+func newShippingOrderEvent(eventType string, shippingOrderID int, application string, payload interface{}) (*outbox.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &outbox.Event{
+		AggregateType: EVENT_AGGREGATE_TYPE,
+		AggregateID:   shippingOrderID,
+		Application:   application,
+		EventType:     eventType,
+		Payload:       string(data),
+		CreatedAt:     time.Now(),
+	}, nil
+}
@@ -0,0 +1,41 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/address"
+	"delivery-service/internal/utils"
+	"fmt"
+)
+
+// Cleans the address of one end of the order and fills the side the client did not send,
+// the coordinates from the address or the address from the coordinates.
+// The coordinates come both or none, so a zero latitude or longitude is a place like any other.
+func (s *shippingOrderService) locate(ctx context.Context, end string, endAddress **address.Address, lat **float64, lng **float64) error {
+	if *endAddress == nil {
+		found, err := s.geocoder.ReverseGeocode(ctx, **lat, **lng)
+		if err != nil {
+			return utils.FailOnError(err, "the address could not be geocoded")
+		}
+		if found == nil {
+			return fmt.Errorf("no address was found for the coordinates of the %s", end)
+		}
+		*endAddress = found
+	}
+
+	if err := address.Normalize(*endAddress); err != nil {
+		return fmt.Errorf("the %s address is not valid: %v", end, err)
+	}
+
+	if *lat == nil || *lng == nil {
+		coordinates, err := s.geocoder.Geocode(ctx, *endAddress)
+		if err != nil {
+			return utils.FailOnError(err, "the address could not be geocoded")
+		}
+		if coordinates == nil {
+			return fmt.Errorf("no coordinates were found for the %s address", end)
+		}
+		*lat, *lng = &coordinates.Lat, &coordinates.Lng
+	}
+
+	return nil
+}
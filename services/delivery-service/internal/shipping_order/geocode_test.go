@@ -0,0 +1,183 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/address"
+	"delivery-service/internal/geocoding"
+	"delivery-service/internal/utils"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Places known to the gazetteer of the tests.
+const testGazetteer = `[
+	{"address": {"street": "Av. Providencia", "number": "1208", "city": "Santiago", "region": "RM", "countryCode": "cl", "postalCode": "7500000"},
+	 "lat": -33.4263, "lng": -70.6170},
+	{"address": {"street": "Calle Larga", "number": "12", "city": "Valparaiso", "countryCode": "CL", "postalCode": "2340000"},
+	 "lat": -33.0458, "lng": -71.6197}
+]`
+
+// Service that locates the ends of an order through the gazetteer geocoder.
+func newGazetteerService(t *testing.T) *shippingOrderService {
+	path := filepath.Join(t.TempDir(), "gazetteer.json")
+	if err := os.WriteFile(path, []byte(testGazetteer), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GEOCODER_GAZETTEER_FILE", path)
+	t.Setenv("GEOCODER_GAZETTEER_MAX_KM", "0.5")
+
+	geocoder, err := geocoding.NewGeocoder("file")
+	if err != nil {
+		t.Fatalf("gazetteer: %v", err)
+	}
+
+	return &shippingOrderService{geocoder: geocoder}
+}
+
+// Pointer to a coordinate of the tests.
+func coordinate(value float64) *float64 {
+	return &value
+}
+
+// Order between both places of the gazetteer, each test leaves out what it wants found.
+func newLocatedOrder() *ShippingOrderInsert {
+	return &ShippingOrderInsert{
+		Sender:    &ShippingOrderSender{IdSender: "11111111-1", FullNameSender: "Ana Perez", PhoneSender: "+56911111111"},
+		Recipient: &ShippingOrderRecipient{IdRecipient: "22222222-2", FullNameRecipient: "Luis Soto", PhoneRecipient: "+56922222222"},
+		Origin: &ShippingOrderOrigin{
+			LatOrigin: coordinate(-33.4263),
+			LngOrigin: coordinate(-70.6170),
+			AddressOrigin: &address.Address{
+				Street: "Av. Providencia", Number: "1208", City: "Santiago", Region: "RM", CountryCode: "CL", PostalCode: "7500000",
+			},
+			ReferenceOrigin: "Oficina 301",
+		},
+		Destination: &ShippingOrderDestination{
+			LatDestination: coordinate(-33.0458),
+			LngDestination: coordinate(-71.6197),
+			AddressDestination: &address.Address{
+				Street: "Calle Larga", Number: "12", City: "Valparaiso", CountryCode: "CL", PostalCode: "2340000",
+			},
+			ReferenceDestination: "Casa azul",
+		},
+	}
+}
+
+func TestLocateFindsCoordinatesOfAddress(t *testing.T) {
+	s := newGazetteerService(t)
+	origin := newLocatedOrder().Origin
+	origin.LatOrigin, origin.LngOrigin = nil, nil
+	origin.AddressOrigin.Street = "  av.  PROVIDENCIA "
+	origin.AddressOrigin.CountryCode = "cl"
+
+	if err := s.locate(context.Background(), "origin", &origin.AddressOrigin, &origin.LatOrigin, &origin.LngOrigin); err != nil {
+		t.Fatalf("locate: %v", err)
+	}
+
+	if origin.LatOrigin == nil || origin.LngOrigin == nil || *origin.LatOrigin != -33.4263 || *origin.LngOrigin != -70.6170 {
+		t.Errorf("coordinates %v,%v, expected the ones of the gazetteer", origin.LatOrigin, origin.LngOrigin)
+	}
+	if origin.AddressOrigin.Street != "av. PROVIDENCIA" || origin.AddressOrigin.CountryCode != "CL" {
+		t.Errorf("address %+v, expected it normalized", origin.AddressOrigin)
+	}
+}
+
+func TestLocateFindsAddressOfCoordinates(t *testing.T) {
+	s := newGazetteerService(t)
+	destination := newLocatedOrder().Destination
+	destination.AddressDestination = nil
+	destination.LatDestination, destination.LngDestination = coordinate(-33.0460), coordinate(-71.6195)
+
+	if err := s.locate(context.Background(), "destination", &destination.AddressDestination, &destination.LatDestination, &destination.LngDestination); err != nil {
+		t.Fatalf("locate: %v", err)
+	}
+
+	if destination.AddressDestination == nil || destination.AddressDestination.Street != "Calle Larga" || destination.AddressDestination.City != "Valparaiso" {
+		t.Fatalf("address %+v, expected the closest place of the gazetteer", destination.AddressDestination)
+	}
+	if *destination.LatDestination != -33.0460 || *destination.LngDestination != -71.6195 {
+		t.Errorf("coordinates %v,%v, expected the ones sent", *destination.LatDestination, *destination.LngDestination)
+	}
+}
+
+func TestLocateKeepsWhatWasSent(t *testing.T) {
+	s := newGazetteerService(t)
+	origin := newLocatedOrder().Origin
+	origin.AddressOrigin = &address.Address{Street: "Unknown street", City: "Santiago", CountryCode: "CL", PostalCode: "8320000"}
+	origin.LatOrigin, origin.LngOrigin = coordinate(-33.5), coordinate(-70.7)
+
+	if err := s.locate(context.Background(), "origin", &origin.AddressOrigin, &origin.LatOrigin, &origin.LngOrigin); err != nil {
+		t.Fatalf("locate: %v", err)
+	}
+
+	if origin.AddressOrigin.Street != "Unknown street" || *origin.LatOrigin != -33.5 || *origin.LngOrigin != -70.7 {
+		t.Errorf("origin %+v at %v,%v, expected it as sent", origin.AddressOrigin, *origin.LatOrigin, *origin.LngOrigin)
+	}
+}
+
+func TestCreateShippingOrderFailsWhenNothingIsFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(shippingOrderInsert *ShippingOrderInsert)
+		err    string
+	}{
+		{
+			name: "unknown origin address",
+			change: func(shippingOrderInsert *ShippingOrderInsert) {
+				shippingOrderInsert.Origin.LatOrigin, shippingOrderInsert.Origin.LngOrigin = nil, nil
+				shippingOrderInsert.Origin.AddressOrigin.Number = "9999"
+			},
+			err: "no coordinates were found for the origin address",
+		},
+		{
+			name: "destination coordinates far from every place",
+			change: func(shippingOrderInsert *ShippingOrderInsert) {
+				shippingOrderInsert.Destination.AddressDestination = nil
+				shippingOrderInsert.Destination.LatDestination, shippingOrderInsert.Destination.LngDestination = coordinate(-36.8201), coordinate(-73.0444)
+			},
+			err: "no address was found for the coordinates of the destination",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newGazetteerService(t)
+			shippingOrderInsert := newLocatedOrder()
+			test.change(shippingOrderInsert)
+
+			shippingOrder, err := s.CreateShippingOrder(context.Background(), shippingOrderInsert)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("order %+v with error %v, expected %q", shippingOrder, err, test.err)
+			}
+		})
+	}
+}
+
+func TestValidateCoordinatesComeTogether(t *testing.T) {
+	tests := []struct {
+		name  string
+		lat   *float64
+		lng   *float64
+		valid bool
+	}{
+		{name: "both coordinates", lat: coordinate(-33.4263), lng: coordinate(-70.6170), valid: true},
+		{name: "zero coordinates", lat: coordinate(0), lng: coordinate(0), valid: true},
+		{name: "no coordinates", valid: true},
+		{name: "latitude alone", lat: coordinate(-33.4263)},
+		{name: "longitude alone", lng: coordinate(0)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			origin := newLocatedOrder().Origin
+			origin.LatOrigin, origin.LngOrigin = test.lat, test.lng
+
+			err := utils.NewValidator().Struct(origin)
+			if (err == nil) != test.valid {
+				t.Errorf("validation error %v, expected valid %v", err, test.valid)
+			}
+		})
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"delivery-service/internal/middleware"
 	"delivery-service/internal/utils"
+	"delivery-service/internal/verification"
+	"errors"
 	"fmt"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"strings"
 )
 
 // Represents our handler with our use-case / service.
@@ -26,9 +30,21 @@ func NewShippingOrderHandler(shippingOrderRoute fiber.Router, us ShippingOrderSe
 
 	// Declare routing endpoints for general routes.
 	shippingOrderRoute.Post("", handler.createShippingOrder)
+	shippingOrderRoute.Post("/status/batch", handler.updateShippingOrdersStatus)
+	shippingOrderRoute.Post("/dispatch", handler.dispatchShippingOrders)
+	shippingOrderRoute.Get("/nearby", handler.getShippingOrdersNearby)
+	shippingOrderRoute.Get("/within", handler.getShippingOrdersInBox)
 	shippingOrderRoute.Get("/:shippingOrderID", handler.getShippingOrder)
 	shippingOrderRoute.Put("/:shippingOrderID", handler.updateShippingOrder)
 	shippingOrderRoute.Delete("/:shippingOrderID", handler.cancelShippingOrder)
+	shippingOrderRoute.Put("/:shippingOrderID/notifications", handler.updateShippingOrderNotifications)
+	shippingOrderRoute.Put("/:shippingOrderID/courier", handler.assignShippingOrderCourier)
+	shippingOrderRoute.Put("/:shippingOrderID/parcels/:parcelID", handler.updateShippingOrderParcel)
+	shippingOrderRoute.Post("/:shippingOrderID/dispatch", handler.dispatchShippingOrder)
+	shippingOrderRoute.Get("/:shippingOrderID/location", handler.getShippingOrderLocation)
+	shippingOrderRoute.Get("/:shippingOrderID/pod", handler.getProofOfDelivery)
+	shippingOrderRoute.Get("/:shippingOrderID/attempts", handler.getDeliveryAttempts)
+	shippingOrderRoute.Get("/:shippingOrderID/pod/:kind", handler.getProofOfDeliveryFile)
 
 	// Declare routing endpoints for specific routes.
 	shippingOrderRoute.Post("/sender", handler.createShippingOrder)
@@ -36,6 +52,60 @@ func NewShippingOrderHandler(shippingOrderRoute fiber.Router, us ShippingOrderSe
 	shippingOrderRoute.Delete("/:shippingOrderID/sender/:senderID", handler.checkIfShippingOrderExistsMiddleware, handler.cancelShippingOrder)
 }
 
+// Creates the handler of the courier views built from shippingOrders.
+// The route is expected to be already restricted with our JWT middleware.
+func NewCourierRouteHandler(courierRoute fiber.Router, us ShippingOrderService) {
+	// Create a handler based on our created service / use-case.
+	handler := &ShippingOrderHandler{
+		shippingOrderService: us,
+	}
+
+	// Declare routing endpoints for specific routes.
+	courierRoute.Get("/:courierID/route", handler.getCourierRoute)
+	courierRoute.Get("/:courierID/cash", handler.getCourierCashReport)
+}
+
+// Creates the handler of the pickups of shippingOrders, used to plan the dispatch.
+func NewPickupHandler(pickupRoute fiber.Router, us ShippingOrderService) {
+	// Create a handler based on our created service / use-case.
+	handler := &ShippingOrderHandler{
+		shippingOrderService: us,
+	}
+
+	// We will restrict this route with our JWT middleware.
+	pickupRoute.Use(middleware.JWTProtected(), middleware.ExtractTokenMetadata)
+
+	// Declare routing endpoints for general routes.
+	pickupRoute.Get("", handler.getPickups)
+}
+
+// Creates the handler of the parcels held in stations.
+// The route is expected to be already restricted with our JWT middleware.
+func NewStationInventoryHandler(stationRoute fiber.Router, us ShippingOrderService) {
+	// Create a handler based on our created service / use-case.
+	handler := &ShippingOrderHandler{
+		shippingOrderService: us,
+	}
+
+	// Declare routing endpoints for specific routes.
+	stationRoute.Get("/:stationID/inventory", handler.getStationInventory)
+}
+
+// Creates the handler of the public tracking of shippingOrders.
+// The route is not restricted, the recipient proves who they are with a code sent to their contact.
+func NewTrackingHandler(trackingRoute fiber.Router, us ShippingOrderService) {
+	// Create a handler based on our created service / use-case.
+	handler := &ShippingOrderHandler{
+		shippingOrderService: us,
+	}
+
+	// Declare routing endpoints for specific routes.
+	trackingRoute.Get("/:trackingCode", handler.getTracking)
+	trackingRoute.Post("/:trackingCode/code", handler.sendTrackingCode)
+	trackingRoute.Post("/:trackingCode/verify", middleware.VerificationLimiter(), handler.verifyTrackingCode)
+	trackingRoute.Put("/:trackingCode/preferences", handler.updateDeliveryPreferences)
+}
+
 // Gets a single shippingOrder.
 func (h *ShippingOrderHandler) getShippingOrder(c *fiber.Ctx) error {
 	// Create cancellable context.
@@ -110,6 +180,10 @@ func (h *ShippingOrderHandler) createShippingOrder(c *fiber.Ctx) error {
 		})
 	}
 
+	// The order belongs to the application of the token, saved contacts to its user.
+	shippingOrderInsert.UserID = c.Locals("userid").(int)
+	shippingOrderInsert.Application = c.Locals("application").(string)
+
 	// Create one shippingOrder.
 	shippingOrder, err := h.shippingOrderService.CreateShippingOrder(customContext, shippingOrderInsert)
 	if err != nil {
@@ -170,8 +244,32 @@ func (h *ShippingOrderHandler) updateShippingOrder(c *fiber.Ctx) error {
 		})
 	}
 
+	// Deliveries carry their proof of delivery in a multipart form.
+	if shippingOrderUpdate.OrderStatus == "entregado" {
+		if shippingOrderUpdate.ProofOfDelivery, err = parseProofOfDelivery(c); err != nil {
+			return proofOfDeliveryError(c, err)
+		}
+	}
+
+	// The user of the token must be the assigned courier for some statuses.
+	shippingOrderUpdate.UserID = c.Locals("userid").(int)
+
 	// Update one shippingOrder.
 	shippingOrder, err := h.shippingOrderService.UpdateShippingOrder(customContext, targetedShippingOrderID, shippingOrderUpdate)
+	if err != nil && errors.Is(err, ErrNotAssignedCourier) {
+		return c.Status(fiber.StatusForbidden).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusForbidden,
+		})
+	}
+	if err != nil && (errors.Is(err, ErrShippingOrderChanged) || errors.Is(err, ErrStationFull) || errors.Is(err, ErrStationUnavailable)) {
+		return c.Status(fiber.StatusConflict).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusConflict,
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
 			"status":    "fail",
@@ -189,6 +287,98 @@ func (h *ShippingOrderHandler) updateShippingOrder(c *fiber.Ctx) error {
 	})
 }
 
+// Updates the status of a single parcel of a shippingOrder.
+func (h *ShippingOrderHandler) updateShippingOrderParcel(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	shippingOrderParcelUpdate := &ShippingOrderParcelUpdate{}
+
+	// Fetch parameters.
+	targetedShippingOrderID, err := c.ParamsInt("shippingOrderID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid shippingOrder ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+	targetedParcelID, err := c.ParamsInt("parcelID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid parcel ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(shippingOrderParcelUpdate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate parcel fields.
+	if err := validate.Struct(shippingOrderParcelUpdate); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Delivering the last parcel delivers the order, it carries the proof of delivery.
+	if shippingOrderParcelUpdate.ParcelStatus == "entregado" {
+		if shippingOrderParcelUpdate.ProofOfDelivery, err = parseProofOfDelivery(c); err != nil {
+			return proofOfDeliveryError(c, err)
+		}
+	}
+
+	// The user of the token must be the assigned courier for some statuses.
+	shippingOrderParcelUpdate.UserID = c.Locals("userid").(int)
+
+	// Update one parcel.
+	shippingOrder, err := h.shippingOrderService.UpdateShippingOrderParcel(customContext, targetedShippingOrderID, targetedParcelID, shippingOrderParcelUpdate)
+	if err != nil && errors.Is(err, ErrNotAssignedCourier) {
+		return c.Status(fiber.StatusForbidden).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusForbidden,
+		})
+	}
+	if err != nil && (errors.Is(err, ErrShippingOrderChanged) || errors.Is(err, ErrStationFull) || errors.Is(err, ErrStationUnavailable)) {
+		return c.Status(fiber.StatusConflict).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusConflict,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Parcel has been updated successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      shippingOrder,
+	})
+}
+
 // Deletes a single shippingOrder.
 func (h *ShippingOrderHandler) cancelShippingOrder(c *fiber.Ctx) error {
 	// Create cancellable context.
@@ -232,6 +422,13 @@ func (h *ShippingOrderHandler) cancelShippingOrder(c *fiber.Ctx) error {
 
 	// Update one shippingOrder.
 	err = h.shippingOrderService.CancelShippingOrder(customContext, targetedShippingOrderID, shippingOrderCancel)
+	if err != nil && errors.Is(err, ErrShippingOrderChanged) {
+		return c.Status(fiber.StatusConflict).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusConflict,
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
 			"status":    "fail",
@@ -247,3 +444,1010 @@ func (h *ShippingOrderHandler) cancelShippingOrder(c *fiber.Ctx) error {
 		"http_code": fiber.StatusOK,
 	})
 }
+
+// Updates the status of several shippingOrders at once.
+func (h *ShippingOrderHandler) updateShippingOrdersStatus(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	shippingOrderStatusBatch := &ShippingOrderStatusBatch{}
+
+	// Parse request body.
+	if err := c.BodyParser(shippingOrderStatusBatch); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate batch fields.
+	if err := validate.Struct(shippingOrderStatusBatch); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// The user of the token must be the assigned courier for some statuses.
+	shippingOrderStatusBatch.UserID = c.Locals("userid").(int)
+
+	// Update the shippingOrders.
+	batchOut, err := h.shippingOrderService.UpdateShippingOrdersStatus(customContext, shippingOrderStatusBatch)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if !batchOut.Committed {
+		return c.Status(fiber.StatusConflict).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "ShippingOrders have not been updated, some orders were rejected",
+			"http_code": fiber.StatusConflict,
+			"data":      batchOut,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "ShippingOrders have been processed successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      batchOut,
+	})
+}
+
+// Updates the notification preferences of a single shippingOrder.
+func (h *ShippingOrderHandler) updateShippingOrderNotifications(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	shippingOrderNotificationsUpdate := &ShippingOrderNotificationsUpdate{}
+
+	// Fetch parameter.
+	targetedShippingOrderID, err := c.ParamsInt("shippingOrderID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid shippingOrder ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(shippingOrderNotificationsUpdate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate notification fields.
+	if err := validate.Struct(shippingOrderNotificationsUpdate); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Update one shippingOrder.
+	shippingOrder, err := h.shippingOrderService.UpdateShippingOrderNotifications(customContext, targetedShippingOrderID, shippingOrderNotificationsUpdate)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "ShippingOrder notifications have been updated successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      shippingOrder,
+	})
+}
+
+// Assigns the courier that moves a single shippingOrder.
+func (h *ShippingOrderHandler) assignShippingOrderCourier(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	shippingOrderCourierAssign := &ShippingOrderCourierAssign{}
+
+	// Fetch parameter.
+	targetedShippingOrderID, err := c.ParamsInt("shippingOrderID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid shippingOrder ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(shippingOrderCourierAssign); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate assignment fields.
+	if err := validate.Struct(shippingOrderCourierAssign); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Assign the courier of one shippingOrder.
+	shippingOrder, err := h.shippingOrderService.AssignShippingOrderCourier(customContext, targetedShippingOrderID, shippingOrderCourierAssign)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Courier has been assigned successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      shippingOrder,
+	})
+}
+
+// Ranks the couriers for a single shippingOrder, assigning the best one on request.
+func (h *ShippingOrderHandler) dispatchShippingOrder(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	shippingOrderDispatch := &ShippingOrderDispatch{}
+
+	// Fetch parameter.
+	targetedShippingOrderID, err := c.ParamsInt("shippingOrderID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid shippingOrder ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(shippingOrderDispatch); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate dispatch fields.
+	if err := validate.Struct(shippingOrderDispatch); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Dispatch one shippingOrder of the application.
+	shippingOrderDispatch.Application = c.Locals("application").(string)
+	dispatchOut, err := h.shippingOrderService.DispatchShippingOrder(customContext, targetedShippingOrderID, shippingOrderDispatch)
+	if err != nil && errors.Is(err, ErrShippingOrderChanged) {
+		return c.Status(fiber.StatusConflict).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusConflict,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "ShippingOrder has been dispatched successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      dispatchOut,
+	})
+}
+
+// Ranks the couriers for every created shippingOrder without courier, assigning the best ones on request.
+func (h *ShippingOrderHandler) dispatchShippingOrders(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	shippingOrderDispatch := &ShippingOrderDispatch{}
+
+	// Parse request body.
+	if err := c.BodyParser(shippingOrderDispatch); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate dispatch fields.
+	if err := validate.Struct(shippingOrderDispatch); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Dispatch the pending shippingOrders of the application.
+	shippingOrderDispatch.Application = c.Locals("application").(string)
+	dispatchOuts, err := h.shippingOrderService.DispatchShippingOrders(customContext, shippingOrderDispatch)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "ShippingOrders have been dispatched successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      dispatchOuts,
+	})
+}
+
+// Gets where a single shippingOrder en route is.
+func (h *ShippingOrderHandler) getShippingOrderLocation(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedShippingOrderID, err := c.ParamsInt("shippingOrderID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid shippingOrder ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the location of one shippingOrder.
+	location, err := h.shippingOrderService.GetShippingOrderLocation(customContext, targetedShippingOrderID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if location.Position == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("ShippingOrder of ID {%d} has no known location.", targetedShippingOrderID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Location obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      location,
+	})
+}
+
+// Gets the planned stops of a single courier.
+func (h *ShippingOrderHandler) getCourierRoute(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedCourierID, err := c.ParamsInt("courierID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid courier ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Plan the route of one courier.
+	route, err := h.shippingOrderService.GetCourierRoute(customContext, targetedCourierID, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Route obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      route,
+	})
+}
+
+// Gets the shippingOrders around a point.
+func (h *ShippingOrderHandler) getShippingOrdersNearby(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	shippingOrderNearbyQuery := &ShippingOrderNearbyQuery{}
+
+	// Parse query string.
+	if err := c.QueryParser(shippingOrderNearbyQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate search fields.
+	if err := validate.Struct(shippingOrderNearbyQuery); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the shippingOrders around the point.
+	shippingOrders, err := h.shippingOrderService.GetShippingOrdersNearby(customContext, shippingOrderNearbyQuery)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "ShippingOrders obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      shippingOrders,
+	})
+}
+
+// Gets the shippingOrders inside a bounding box.
+func (h *ShippingOrderHandler) getShippingOrdersInBox(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	shippingOrderBoxQuery := &ShippingOrderBoxQuery{}
+
+	// Parse query string.
+	if err := c.QueryParser(shippingOrderBoxQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate search fields.
+	if err := validate.Struct(shippingOrderBoxQuery); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the shippingOrders inside the box.
+	shippingOrders, err := h.shippingOrderService.GetShippingOrdersInBox(customContext, shippingOrderBoxQuery)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "ShippingOrders obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      shippingOrders,
+	})
+}
+
+// Gets the cash on delivery a courier collected and still has to collect.
+func (h *ShippingOrderHandler) getCourierCashReport(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	courierCashQuery := &CourierCashQuery{}
+
+	// Fetch parameter.
+	targetedCourierID, err := c.ParamsInt("courierID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid courier ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse query string.
+	if err := c.QueryParser(courierCashQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate report fields.
+	if err := validate.Struct(courierCashQuery); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Reconcile the cash of one courier.
+	report, err := h.shippingOrderService.GetCourierCashReport(customContext, targetedCourierID, c.Locals("application").(string), courierCashQuery)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Cash report obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      report,
+	})
+}
+
+// Gets the shippingOrders to pick up on a day, grouped by their pickup window.
+func (h *ShippingOrderHandler) getPickups(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	pickupQuery := &PickupQuery{}
+
+	// Parse query string.
+	if err := c.QueryParser(pickupQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate query fields.
+	if err := validate.Struct(pickupQuery); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Group the pickups of the day.
+	pickupWindows, err := h.shippingOrderService.GetPickups(customContext, pickupQuery)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Pickups obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      pickupWindows,
+	})
+}
+
+// Reads the proof of delivery of a multipart request, null when the request is not a multipart form.
+func parseProofOfDelivery(c *fiber.Ctx) (*ProofOfDeliveryInsert, error) {
+	if !strings.HasPrefix(string(c.Request().Header.ContentType()), fiber.MIMEMultipartForm) {
+		return nil, nil
+	}
+
+	// Parse the fields of the form.
+	proofOfDeliveryInsert := &ProofOfDeliveryInsert{}
+	if err := c.BodyParser(proofOfDeliveryInsert); err != nil {
+		return nil, err
+	}
+	if err := utils.NewValidator().Struct(proofOfDeliveryInsert); err != nil {
+		return nil, err
+	}
+
+	// Take the images of the form.
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	if files := form.File[POD_SIGNATURE]; len(files) > 0 {
+		proofOfDeliveryInsert.Signature = files[0]
+	}
+	if files := form.File[POD_PHOTO]; len(files) > 0 {
+		proofOfDeliveryInsert.Photo = files[0]
+	}
+
+	return proofOfDeliveryInsert, nil
+}
+
+// Responds to a proof of delivery that could not be read.
+func proofOfDeliveryError(c *fiber.Ctx, err error) error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+		"status":    "fail",
+		"message":   err.Error(),
+		"http_code": fiber.StatusBadRequest,
+	})
+}
+
+// Gets the proof of delivery of a single shippingOrder.
+func (h *ShippingOrderHandler) getProofOfDelivery(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedShippingOrderID, err := c.ParamsInt("shippingOrderID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid shippingOrder ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the proof of delivery of one shippingOrder.
+	proofOfDelivery, err := h.shippingOrderService.GetProofOfDelivery(customContext, targetedShippingOrderID, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if proofOfDelivery == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("ShippingOrder of ID {%d} has no proof of delivery.", targetedShippingOrderID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Proof of delivery obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      proofOfDelivery,
+	})
+}
+
+// Gets the signature or the photo of the proof of delivery of a single shippingOrder.
+func (h *ShippingOrderHandler) getProofOfDeliveryFile(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameters.
+	targetedShippingOrderID, err := c.ParamsInt("shippingOrderID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid shippingOrder ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+	kind := c.Params("kind")
+	if kind != POD_SIGNATURE && kind != POD_PHOTO {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify either signature or photo!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the image, the response closes it once sent.
+	file, contentType, err := h.shippingOrderService.GetProofOfDeliveryFile(customContext, targetedShippingOrderID, c.Locals("application").(string), kind)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if file == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("ShippingOrder of ID {%d} has no %s in its proof of delivery.", targetedShippingOrderID, kind),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return the image.
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Status(fiber.StatusOK).SendStream(file)
+}
+
+// Gets the failed delivery attempts of a single shippingOrder.
+func (h *ShippingOrderHandler) getDeliveryAttempts(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedShippingOrderID, err := c.ParamsInt("shippingOrderID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid shippingOrder ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the attempts of one shippingOrder.
+	deliveryAttempts, err := h.shippingOrderService.GetDeliveryAttempts(customContext, targetedShippingOrderID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Delivery attempts obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      deliveryAttempts,
+	})
+}
+
+// Gets the parcels a station holds.
+func (h *ShippingOrderHandler) getStationInventory(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedStationID, err := c.ParamsInt("stationID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid station ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the inventory of one station.
+	inventory, err := h.shippingOrderService.GetStationInventory(customContext, targetedStationID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if inventory == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("Station of ID {%d} does not exist.", targetedStationID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Station inventory obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      inventory,
+	})
+}
+
+// Header of the session verified for a tracked shippingOrder.
+const TRACKING_TOKEN_HEADER = "X-Tracking-Token"
+
+// Answers the errors of the tracking flow with their status.
+func trackingError(c *fiber.Ctx, err error) error {
+	httpCode := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrTrackingSessionRequired), errors.Is(err, ErrWrongVerificationCode):
+		httpCode = fiber.StatusUnauthorized
+	case errors.Is(err, verification.ErrCodeRecentlySent), errors.Is(err, verification.ErrTooManyAttempts):
+		httpCode = fiber.StatusTooManyRequests
+	case errors.Is(err, ErrPreferencesClosed):
+		httpCode = fiber.StatusConflict
+	}
+
+	return c.Status(httpCode).JSON(&fiber.Map{
+		"status":    "fail",
+		"message":   err.Error(),
+		"http_code": httpCode,
+	})
+}
+
+// Answers a tracking code that matches no shippingOrder.
+func trackingNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+		"status":    "fail",
+		"message":   fmt.Sprintf("shippingOrder with tracking code {%s} does not exist.", c.Params("trackingCode")),
+		"http_code": fiber.StatusNotFound,
+	})
+}
+
+// Gets the public view of a tracked shippingOrder, with its preferences when the session is verified.
+func (h *ShippingOrderHandler) getTracking(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Get one tracked shippingOrder.
+	tracking, err := h.shippingOrderService.GetTracking(customContext, c.Params("trackingCode"), c.Get(TRACKING_TOKEN_HEADER))
+	if err != nil {
+		return trackingError(c, err)
+	}
+	if tracking == nil {
+		return trackingNotFound(c)
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Tracking obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      tracking,
+	})
+}
+
+// Sends a verification code to the recipient of a tracked shippingOrder.
+func (h *ShippingOrderHandler) sendTrackingCode(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	trackingCodeInsert := &TrackingCodeInsert{}
+
+	// Parse request body.
+	if err := c.BodyParser(trackingCodeInsert); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate code fields.
+	if err := validate.Struct(trackingCodeInsert); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Send the code.
+	trackingCode, err := h.shippingOrderService.SendTrackingCode(customContext, c.Params("trackingCode"), trackingCodeInsert)
+	if err != nil {
+		return trackingError(c, err)
+	}
+	if trackingCode == nil {
+		return trackingNotFound(c)
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusAccepted).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Verification code sent successfully!",
+		"http_code": fiber.StatusAccepted,
+		"data":      trackingCode,
+	})
+}
+
+// Checks the verification code of a tracked shippingOrder and opens a session.
+func (h *ShippingOrderHandler) verifyTrackingCode(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	trackingVerifyInsert := &TrackingVerifyInsert{}
+
+	// Parse request body.
+	if err := c.BodyParser(trackingVerifyInsert); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate code fields.
+	if err := validate.Struct(trackingVerifyInsert); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Check the code.
+	session, err := h.shippingOrderService.VerifyTrackingCode(customContext, c.Params("trackingCode"), trackingVerifyInsert)
+	if err != nil {
+		return trackingError(c, err)
+	}
+	if session == nil {
+		return trackingNotFound(c)
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Verification code checked successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      session,
+	})
+}
+
+// Replaces the delivery preferences of a tracked shippingOrder.
+func (h *ShippingOrderHandler) updateDeliveryPreferences(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	deliveryPreferencesUpdate := &DeliveryPreferencesUpdate{}
+
+	// Parse request body.
+	if err := c.BodyParser(deliveryPreferencesUpdate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a ShippingOrder model.
+	validate := utils.NewValidator()
+
+	// Validate preference fields.
+	if err := validate.Struct(deliveryPreferencesUpdate); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Update the preferences.
+	tracking, err := h.shippingOrderService.UpdateDeliveryPreferences(customContext, c.Params("trackingCode"), c.Get(TRACKING_TOKEN_HEADER), deliveryPreferencesUpdate)
+	if err != nil {
+		return trackingError(c, err)
+	}
+	if tracking == nil {
+		return trackingNotFound(c)
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Delivery preferences updated successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      tracking,
+	})
+}
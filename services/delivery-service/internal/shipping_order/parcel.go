@@ -0,0 +1,311 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/utils"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Progress of each status, an order is as advanced as its least advanced parcel.
+// A parcel whose delivery failed is behind the ones still en route.
+var parcelStatusRanks = map[string]int{
+	"creado":          0,
+	"recolectado":     1,
+	"en_estacion":     2,
+	"intento_fallido": 3,
+	"en_ruta":         4,
+	"entregado":       5,
+	"devolucion":      6,
+	"devuelto":        7,
+}
+
+// Checks every package of a new order against the package size catalog.
+// Returns its parcels and the totals kept on the order, whose size is the largest one.
+func (s *shippingOrderService) buildParcels(ctx context.Context, shippingOrderInsert *ShippingOrderInsert, createdAt time.Time) ([]*ShippingOrderParcel, *ShippingOrderPackage, error) {
+	packages := shippingOrderInsert.Packages
+	if shippingOrderInsert.Package != nil {
+		if len(packages) > 0 {
+			return nil, nil, fmt.Errorf("the package can not be sent together with packages")
+		}
+		packages = []*ShippingOrderPackage{shippingOrderInsert.Package}
+	}
+
+	//valid package size
+	maxSize, _ := strconv.Atoi(os.Getenv("MAX_SIZE"))
+
+	parcels := make([]*ShippingOrderParcel, 0, len(packages))
+	summary := &ShippingOrderPackage{}
+	largestLimit := -1
+	for i, shippingOrderPackage := range packages {
+		if shippingOrderPackage.WeightProduct > maxSize {
+			return nil, nil, fmt.Errorf("package %d: orders greater than 25KG must contact the company to make a special agreement", i+1)
+		}
+
+		packageSize, err := s.packageSizeRepository.GetPackageSize(ctx, shippingOrderPackage.WeightProduct)
+		if err != nil {
+			return nil, nil, utils.FailOnError(err, "packet size information could not be retrieved")
+		}
+		if len(*packageSize) == 0 || (*packageSize)[0].Nemo != shippingOrderPackage.PackageSize {
+			return nil, nil, fmt.Errorf("package %d: the type of the package has no relation to size", i+1)
+		}
+
+		items, declaredValue, err := s.resolveItems(ctx, shippingOrderInsert.Application, shippingOrderPackage)
+		if err != nil {
+			return nil, nil, fmt.Errorf("package %d: %v", i+1, err)
+		}
+
+		// An order is paid and insured in a single currency.
+		if shippingOrderPackage.Currency != "" {
+			if summary.Currency != "" && summary.Currency != shippingOrderPackage.Currency {
+				return nil, nil, fmt.Errorf("package %d: every package of the order must use the same currency", i+1)
+			}
+			summary.Currency = shippingOrderPackage.Currency
+		}
+
+		parcels = append(parcels, &ShippingOrderParcel{
+			Sequence:        i + 1,
+			PackageSize:     shippingOrderPackage.PackageSize,
+			QuantityProduct: shippingOrderPackage.QuantityProduct,
+			WeightProduct:   shippingOrderPackage.WeightProduct,
+			DeclaredValue:   declaredValue,
+			ParcelStatus:    "creado",
+			CreatedAt:       createdAt,
+			UpdatedUser:     shippingOrderInsert.CreatedUser,
+			UpdatedAt:       createdAt,
+			Items:           items,
+		})
+
+		summary.QuantityProduct += shippingOrderPackage.QuantityProduct
+		summary.WeightProduct += shippingOrderPackage.WeightProduct
+		summary.DeclaredValue += declaredValue
+		if limit := (*packageSize)[0].Limitvalue; limit > largestLimit {
+			largestLimit = limit
+			summary.PackageSize = shippingOrderPackage.PackageSize
+		}
+	}
+
+	summary.DeclaredValue = math.Round(summary.DeclaredValue*100) / 100
+	if summary.Currency == "" {
+		summary.Currency = defaultCurrency()
+	}
+
+	return parcels, summary, nil
+}
+
+// Reads the products of a package from the catalog of the application, its quantity becomes the sum of their quantities.
+// Returns the products as they are now and the declared value of the package, typed by hand when it has no products.
+func (s *shippingOrderService) resolveItems(ctx context.Context, application string, shippingOrderPackage *ShippingOrderPackage) ([]*ShippingOrderItemOut, float64, error) {
+	items := make([]*ShippingOrderItemOut, 0, len(shippingOrderPackage.Items))
+	if len(shippingOrderPackage.Items) == 0 {
+		return items, math.Round(shippingOrderPackage.DeclaredValue*100) / 100, nil
+	}
+
+	quantityProduct := 0
+	declaredValue := 0.0
+	for _, item := range shippingOrderPackage.Items {
+		searchedProduct, err := s.productRepository.GetProduct(ctx, item.ProductID, application)
+		if err != nil {
+			return nil, 0, utils.FailOnError(err, "product information could not be retrieved")
+		}
+		if searchedProduct == nil {
+			return nil, 0, fmt.Errorf("There is no product with the ID %d", item.ProductID)
+		}
+
+		items = append(items, &ShippingOrderItemOut{
+			ProductID: searchedProduct.ID,
+			Name:      searchedProduct.Name,
+			Quantity:  item.Quantity,
+			UnitValue: searchedProduct.UnitValue,
+		})
+		quantityProduct += item.Quantity
+		declaredValue += float64(item.Quantity) * searchedProduct.UnitValue
+	}
+
+	if shippingOrderPackage.QuantityProduct != 0 && shippingOrderPackage.QuantityProduct != quantityProduct {
+		return nil, 0, fmt.Errorf("the quantity of the package does not match its items")
+	}
+	declaredValue = math.Round(declaredValue*100) / 100
+	if shippingOrderPackage.DeclaredValue != 0 && math.Abs(shippingOrderPackage.DeclaredValue-declaredValue) >= 0.005 {
+		return nil, 0, fmt.Errorf("the declared value of the package does not match its items")
+	}
+	shippingOrderPackage.QuantityProduct = quantityProduct
+	shippingOrderPackage.DeclaredValue = declaredValue
+
+	return items, declaredValue, nil
+}
+
+// Status of an order given its parcels, cancelled parcels do not hold it back.
+func deriveOrderStatus(parcels []*ShippingOrderParcelOut) string {
+	orderStatus := ""
+	for _, parcel := range parcels {
+		if parcel.ParcelStatus == "cancelado" {
+			continue
+		}
+		if orderStatus == "" || parcelStatusRanks[parcel.ParcelStatus] < parcelStatusRanks[orderStatus] {
+			orderStatus = parcel.ParcelStatus
+		}
+	}
+
+	// Every parcel was cancelled.
+	if orderStatus == "" {
+		return "cancelado"
+	}
+
+	return orderStatus
+}
+
+// Times a parcel update is made again when the order changed meanwhile.
+const PARCEL_UPDATE_ATTEMPTS = 3
+
+// Implementation of 'UpdateShippingOrderParcel'.
+// Parcels of an order may be moved at the same time, an update that raced another one is checked again against the order as it is now.
+func (s *shippingOrderService) UpdateShippingOrderParcel(ctx context.Context, shippingOrderID int, parcelID int, shippingOrderParcelUpdate *ShippingOrderParcelUpdate) (*ShippingOrderOut, error) {
+	for attempt := 1; ; attempt++ {
+		shippingOrder, err := s.updateShippingOrderParcel(ctx, shippingOrderID, parcelID, shippingOrderParcelUpdate)
+		if !errors.Is(err, ErrShippingOrderChanged) || attempt == PARCEL_UPDATE_ATTEMPTS {
+			return shippingOrder, err
+		}
+	}
+}
+
+// Updates a single parcel against the order as it was read.
+func (s *shippingOrderService) updateShippingOrderParcel(ctx context.Context, shippingOrderID int, parcelID int, shippingOrderParcelUpdate *ShippingOrderParcelUpdate) (*ShippingOrderOut, error) {
+	// Check if shippingOrder exists.
+	searchedShippingOrder, err := s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil {
+		return nil, fmt.Errorf("There is no shippingOrder with this ID")
+	}
+
+	var searchedParcel *ShippingOrderParcelOut
+	for _, parcel := range searchedShippingOrder.Parcels {
+		if parcel.ID == parcelID {
+			searchedParcel = parcel
+		}
+	}
+	if searchedParcel == nil {
+		return nil, fmt.Errorf("There is no parcel with this ID")
+	}
+
+	// Parcels follow the same rules as orders.
+	parcelStatus := shippingOrderParcelUpdate.ParcelStatus
+	if parcelStatus == "cancelado" {
+		if dispatchedOrderStatuses[searchedParcel.ParcelStatus] || searchedParcel.ParcelStatus == "cancelado" {
+			return nil, fmt.Errorf("the parcel must not have the status of en route, delivered, returned or cancelled")
+		}
+	} else if err = validateStatusTransition(searchedParcel.ParcelStatus, parcelStatus); err != nil {
+		return nil, err
+	}
+
+	if err = s.checkAssignedCourier(ctx, searchedShippingOrder, parcelStatus, shippingOrderParcelUpdate.UserID); err != nil {
+		return nil, err
+	}
+
+	// Set value for 'Modified' attribute.
+	parcel := &ShippingOrderParcel{
+		ParcelStatus: parcelStatus,
+		UpdatedUser:  shippingOrderParcelUpdate.UpdatedUser,
+		UpdatedAt:    time.Now(),
+	}
+
+	// A parcel arriving at a station must fit in it.
+	if parcelStatus == "en_estacion" {
+		searchedStation, room, err := s.stationRoom(ctx, shippingOrderParcelUpdate.StationID)
+		if err != nil {
+			return nil, err
+		}
+		if room < 1 {
+			return nil, stationFullError(searchedStation, room)
+		}
+		parcel.StationID = &searchedStation.ID
+	}
+
+	// The order moves once its last parcel behind does.
+	previousParcelStatus := searchedParcel.ParcelStatus
+	searchedParcel.ParcelStatus = parcelStatus
+	orderStatus := deriveOrderStatus(searchedShippingOrder.Parcels)
+	searchedParcel.ParcelStatus = previousParcelStatus
+	if orderStatus == searchedShippingOrder.OrderStatus {
+		err = s.shippingOrderRepository.UpdateShippingOrderParcel(ctx, shippingOrderID, parcelID, searchedShippingOrder.OrderStatus, previousParcelStatus, parcel, nil, nil)
+		if errors.Is(err, ErrShippingOrderChanged) || errors.Is(err, ErrStationFull) || errors.Is(err, ErrStationUnavailable) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, utils.FailOnError(err, "could not update record")
+		}
+		return s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+	}
+
+	shippingOrder := &ShippingOrder{
+		OrderStatus: orderStatus,
+		UpdatedUser: parcel.UpdatedUser,
+		UpdatedAt:   parcel.UpdatedAt,
+	}
+
+	// Delivering the last parcel delivers the order, its cash must be collected.
+	if err = collectCash(searchedShippingOrder, shippingOrder, shippingOrderParcelUpdate.CashCollected); err != nil {
+		return nil, err
+	}
+	if orderStatus == "entregado" {
+		shippingOrder.ProofOfDelivery, err = s.storeProofOfDelivery(ctx, shippingOrderID, shippingOrderParcelUpdate.ProofOfDelivery, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Prepare the event of the change.
+	var eventType string
+	var payload interface{}
+	if orderStatus == "cancelado" {
+		eventType = EVENT_ORDER_CANCELLED
+		payload = &OrderCancelled{
+			ShippingOrderID:     shippingOrderID,
+			PreviousOrderStatus: searchedShippingOrder.OrderStatus,
+			Refund:              "N",
+			UpdatedUser:         shippingOrder.UpdatedUser,
+			UpdatedAt:           shippingOrder.UpdatedAt,
+		}
+	} else {
+		eventType = EVENT_ORDER_STATUS_CHANGED
+		payload = &OrderStatusChanged{
+			ShippingOrderID:     shippingOrderID,
+			PreviousOrderStatus: searchedShippingOrder.OrderStatus,
+			OrderStatus:         orderStatus,
+			StationID:           parcel.StationID,
+			CashCollected:       shippingOrder.CashCollected,
+			UpdatedUser:         shippingOrder.UpdatedUser,
+			UpdatedAt:           shippingOrder.UpdatedAt,
+		}
+	}
+	event, err := newShippingOrderEvent(eventType, shippingOrderID, searchedShippingOrder.Application, payload)
+	if err != nil {
+		s.removeProofOfDelivery(ctx, shippingOrder.ProofOfDelivery)
+		return nil, utils.FailOnError(err, "the event of the record could not be prepared")
+	}
+
+	// Pass to the repository layer.
+	err = s.shippingOrderRepository.UpdateShippingOrderParcel(ctx, shippingOrderID, parcelID, searchedShippingOrder.OrderStatus, previousParcelStatus, parcel, shippingOrder, event)
+	if err != nil {
+		s.removeProofOfDelivery(ctx, shippingOrder.ProofOfDelivery)
+		if errors.Is(err, ErrShippingOrderChanged) || errors.Is(err, ErrStationFull) || errors.Is(err, ErrStationUnavailable) {
+			return nil, err
+		}
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	if err = s.refreshDeliveryEstimate(ctx, searchedShippingOrder, orderStatus); err != nil {
+		log.Printf("Oops... Delivery estimate of ShippingOrder %d could not be updated! Reason: %v", shippingOrderID, err)
+	}
+
+	s.notifyStatusChange(searchedShippingOrder, orderStatus)
+
+	return s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+}
@@ -0,0 +1,43 @@
+package shipping_order
+
+import (
+	"testing"
+)
+
+// Parcels of the tests, one for each given status.
+func parcelsInStatuses(parcelStatuses ...string) []*ShippingOrderParcelOut {
+	parcels := make([]*ShippingOrderParcelOut, 0, len(parcelStatuses))
+	for i, parcelStatus := range parcelStatuses {
+		parcels = append(parcels, &ShippingOrderParcelOut{ID: i + 1, Sequence: i + 1, ParcelStatus: parcelStatus})
+	}
+	return parcels
+}
+
+func TestDeriveOrderStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		parcelStatuses []string
+		orderStatus    string
+	}{
+		{"single parcel", []string{"en_estacion"}, "en_estacion"},
+		{"all delivered", []string{"entregado", "entregado", "entregado"}, "entregado"},
+		{"some delivered and some en route", []string{"entregado", "en_ruta", "entregado"}, "en_ruta"},
+		{"some delivered and some still to collect", []string{"entregado", "creado"}, "creado"},
+		{"failed delivery is behind en route", []string{"en_ruta", "intento_fallido"}, "intento_fallido"},
+		{"failed delivery is ahead of the station", []string{"intento_fallido", "en_estacion"}, "en_estacion"},
+		{"some delivered and some returning", []string{"devolucion", "entregado"}, "entregado"},
+		{"all returned", []string{"devuelto", "devuelto"}, "devuelto"},
+		{"all cancelled", []string{"cancelado", "cancelado"}, "cancelado"},
+		{"cancelled parcels do not hold back the rest", []string{"cancelado", "entregado"}, "entregado"},
+		{"cancelled parcels do not move the rest ahead", []string{"recolectado", "cancelado", "en_ruta"}, "recolectado"},
+		{"no parcels", nil, "cancelado"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if orderStatus := deriveOrderStatus(parcelsInStatuses(test.parcelStatuses...)); orderStatus != test.orderStatus {
+				t.Errorf("parcels %v gave %v, expected %v", test.parcelStatuses, orderStatus, test.orderStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/utils"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Currency of the orders whose packages do not state one.
+func defaultCurrency() string {
+	if currency := os.Getenv("DEFAULT_CURRENCY"); currency != "" {
+		return currency
+	}
+
+	return "USD"
+}
+
+// Premium of insuring an order, a share of its declared value with a minimum.
+func insurancePremium(declaredValue float64) float64 {
+	// Define insurance settings.
+	rate, err := strconv.ParseFloat(os.Getenv("INSURANCE_RATE"), 64)
+	if err != nil {
+		rate = 0.01
+	}
+	minPremium, _ := strconv.ParseFloat(os.Getenv("INSURANCE_MIN_PREMIUM"), 64)
+
+	premium := math.Max(declaredValue*rate, minPremium)
+
+	return math.Round(premium*100) / 100
+}
+
+// Checks the cash collected when an order with cash on delivery is delivered and keeps it on the change.
+func collectCash(searchedShippingOrder *ShippingOrderOut, shippingOrder *ShippingOrder, cashCollected *float64) error {
+	if shippingOrder.OrderStatus != "entregado" || searchedShippingOrder.CashOnDelivery == 0 {
+		return nil
+	}
+
+	if cashCollected == nil {
+		return fmt.Errorf("the courier must confirm the cash collected to deliver the order")
+	}
+	if math.Abs(*cashCollected-searchedShippingOrder.CashOnDelivery) >= 0.005 {
+		return fmt.Errorf("the cash collected does not match the cash on delivery of the order, %.2f %s", searchedShippingOrder.CashOnDelivery, searchedShippingOrder.Currency)
+	}
+
+	collected := searchedShippingOrder.CashOnDelivery
+	shippingOrder.CashCollected = &collected
+	shippingOrder.CashCollectedAt = &shippingOrder.UpdatedAt
+
+	return nil
+}
+
+// Implementation of 'GetCourierCashReport'.
+func (s *shippingOrderService) GetCourierCashReport(ctx context.Context, courierID int, application string, courierCashQuery *CourierCashQuery) (*CourierCashReportOut, error) {
+	// Check if courier exists, couriers of other applications do not exist for the caller.
+	searchedCourier, err := s.courierRepository.GetCourier(ctx, courierID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedCourier == nil || searchedCourier.Application != application {
+		return nil, fmt.Errorf("There is no courier with this ID")
+	}
+
+	// The period covers both days.
+	from, err := time.ParseInLocation("2006-01-02", courierCashQuery.From, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	to, err := time.ParseInLocation("2006-01-02", courierCashQuery.To, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("the end of the period must not be before its start")
+	}
+
+	cashOnDeliveryOrders, err := s.shippingOrderRepository.GetCourierCashOnDelivery(ctx, courierID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+
+	reportOut := &CourierCashReportOut{
+		CourierID: courierID,
+		From:      courierCashQuery.From,
+		To:        courierCashQuery.To,
+		Collected: []*CashOnDeliveryOrder{},
+		Pending:   []*CashOnDeliveryOrder{},
+		Totals:    []*CashOnDeliveryTotal{},
+	}
+
+	// Add up the cash of each currency.
+	totals := make(map[string]*CashOnDeliveryTotal)
+	for i := range *cashOnDeliveryOrders {
+		cashOnDeliveryOrder := &(*cashOnDeliveryOrders)[i]
+
+		total, ok := totals[cashOnDeliveryOrder.Currency]
+		if !ok {
+			total = &CashOnDeliveryTotal{Currency: cashOnDeliveryOrder.Currency}
+			totals[cashOnDeliveryOrder.Currency] = total
+			reportOut.Totals = append(reportOut.Totals, total)
+		}
+
+		if cashOnDeliveryOrder.CashCollected != nil {
+			reportOut.Collected = append(reportOut.Collected, cashOnDeliveryOrder)
+			total.Collected = math.Round((total.Collected+*cashOnDeliveryOrder.CashCollected)*100) / 100
+		} else {
+			reportOut.Pending = append(reportOut.Pending, cashOnDeliveryOrder)
+			total.Pending = math.Round((total.Pending+cashOnDeliveryOrder.CashOnDelivery)*100) / 100
+		}
+	}
+
+	return reportOut, nil
+}
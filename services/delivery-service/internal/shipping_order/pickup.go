@@ -0,0 +1,124 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/utils"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Checks the pickup scheduled for a new order against the calendar of the country of its origin.
+// Returns the window in the server time zone, null when the order is picked up right away.
+func (s *shippingOrderService) schedulePickup(ctx context.Context, pickup *ShippingOrderWindow, country string, now time.Time) (*time.Time, *time.Time, error) {
+	if pickup == nil {
+		return nil, nil, nil
+	}
+
+	// Define pickup settings.
+	minWindow, _ := strconv.Atoi(os.Getenv("PICKUP_MIN_WINDOW_MINUTES"))
+	if minWindow <= 0 {
+		minWindow = 60
+	}
+	maxDays, _ := strconv.Atoi(os.Getenv("PICKUP_MAX_DAYS"))
+	if maxDays <= 0 {
+		maxDays = 14
+	}
+
+	return s.scheduleWindow(ctx, "pickup", pickup, country, now, minWindow, maxDays)
+}
+
+// Checks a window of one end of an order against the calendar of its country.
+// Returns the window in the server time zone.
+func (s *shippingOrderService) scheduleWindow(ctx context.Context, end string, window *ShippingOrderWindow, country string, now time.Time, minWindow int, maxDays int) (*time.Time, *time.Time, error) {
+	// Countries without a calendar have no windows when calendars are configured.
+	operatingCalendar, err := s.calendarRepository.GetCalendar(ctx, country)
+	if err != nil {
+		return nil, nil, utils.FailOnError(err, "operating calendar information could not be retrieved")
+	}
+	if operatingCalendar == nil && s.calendarRepository.IsConfigured() {
+		return nil, nil, fmt.Errorf("%s windows are not available in %s", end, country)
+	}
+	location := time.Local
+	if operatingCalendar != nil {
+		location = operatingCalendar.Location
+	}
+
+	from, err := time.ParseInLocation("2006-01-02 15:04", window.Date+" "+window.From, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := time.ParseInLocation("2006-01-02 15:04", window.Date+" "+window.To, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !to.After(from) {
+		return nil, nil, fmt.Errorf("the end of the %s window must be after its start", end)
+	}
+	if to.Sub(from) < time.Duration(minWindow)*time.Minute {
+		return nil, nil, fmt.Errorf("the %s window must last at least %d minutes", end, minWindow)
+	}
+	if !from.After(now) {
+		return nil, nil, fmt.Errorf("the %s window must be in the future", end)
+	}
+	if from.After(now.AddDate(0, 0, maxDays)) {
+		return nil, nil, fmt.Errorf("the %s window can not be scheduled more than %d days ahead", end, maxDays)
+	}
+
+	if operatingCalendar != nil {
+		if err = operatingCalendar.CheckWindow(from, to); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	from = from.In(time.Local)
+	to = to.In(time.Local)
+
+	return &from, &to, nil
+}
+
+// End of the day of a time, in the server time zone.
+// Dispatch and routes only take the orders whose pickup is due by the end of the day.
+func endOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+}
+
+// Checks if the pickup of an order is scheduled for a later day.
+func pickupScheduledLater(shippingOrder *ShippingOrderOut, now time.Time) bool {
+	return shippingOrder.PickupFrom != nil && !shippingOrder.PickupFrom.Before(endOfDay(now))
+}
+
+// Implementation of 'GetPickups'.
+func (s *shippingOrderService) GetPickups(ctx context.Context, pickupQuery *PickupQuery) (*[]PickupWindowOut, error) {
+	day, err := time.ParseInLocation("2006-01-02", pickupQuery.Date, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	shippingOrders, err := s.shippingOrderRepository.GetShippingOrdersToPickUp(ctx, day, day.AddDate(0, 0, 1), pickupQuery.ZoneID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+
+	// Orders come sorted by window, each window gathers the orders next to each other.
+	pickupWindows := []PickupWindowOut{}
+	for _, shippingOrder := range *shippingOrders {
+		last := len(pickupWindows) - 1
+		if last < 0 || !pickupWindows[last].From.Equal(*shippingOrder.PickupFrom) || !pickupWindows[last].To.Equal(*shippingOrder.PickupTo) {
+			pickupWindows = append(pickupWindows, PickupWindowOut{
+				From:           *shippingOrder.PickupFrom,
+				To:             *shippingOrder.PickupTo,
+				ShippingOrders: []ShippingOrderOut{},
+			})
+			last++
+		}
+
+		pickupWindows[last].Orders++
+		pickupWindows[last].WeightProduct += shippingOrder.Package.WeightProduct
+		pickupWindows[last].ShippingOrders = append(pickupWindows[last].ShippingOrders, shippingOrder)
+	}
+
+	return &pickupWindows, nil
+}
@@ -0,0 +1,212 @@
+package shipping_order
+
+import (
+	"context"
+	"crypto/rand"
+	"delivery-service/internal/utils"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"time"
+)
+
+// Images a proof of delivery can carry.
+const (
+	POD_SIGNATURE = "signature"
+	POD_PHOTO     = "photo"
+)
+
+// Content types accepted for the images, with the extension of their blobs.
+var proofOfDeliveryContentTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+}
+
+// Checks the proof of delivery of an order and stores its images in the blob store.
+// If the change of the order is not stored afterwards, its images must be removed with 'removeProofOfDelivery'.
+func (s *shippingOrderService) storeProofOfDelivery(ctx context.Context, shippingOrderID int, proofOfDeliveryInsert *ProofOfDeliveryInsert, createdUser string, createdAt time.Time) (*ProofOfDelivery, error) {
+	if proofOfDeliveryInsert == nil {
+		return nil, fmt.Errorf("the proof of delivery is required to deliver the order")
+	}
+	if proofOfDeliveryInsert.Signature == nil && proofOfDeliveryInsert.Photo == nil {
+		return nil, fmt.Errorf("the proof of delivery must carry a signature or a photo")
+	}
+
+	proofOfDelivery := &ProofOfDelivery{
+		ShippingOrderID:  shippingOrderID,
+		ReceiverName:     proofOfDeliveryInsert.ReceiverName,
+		ReceiverDocument: proofOfDeliveryInsert.ReceiverDocument,
+		Lat:              *proofOfDeliveryInsert.Lat,
+		Lng:              *proofOfDeliveryInsert.Lng,
+		CreatedUser:      createdUser,
+		CreatedAt:        createdAt,
+	}
+
+	var err error
+	if proofOfDeliveryInsert.Signature != nil {
+		proofOfDelivery.SignatureKey, err = s.storeProofOfDeliveryImage(ctx, shippingOrderID, POD_SIGNATURE, proofOfDeliveryInsert.Signature, createdAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if proofOfDeliveryInsert.Photo != nil {
+		proofOfDelivery.PhotoKey, err = s.storeProofOfDeliveryImage(ctx, shippingOrderID, POD_PHOTO, proofOfDeliveryInsert.Photo, createdAt)
+		if err != nil {
+			s.removeProofOfDelivery(ctx, proofOfDelivery)
+			return nil, err
+		}
+	}
+
+	return proofOfDelivery, nil
+}
+
+// Stores one image of a proof of delivery and returns its key.
+func (s *shippingOrderService) storeProofOfDeliveryImage(ctx context.Context, shippingOrderID int, kind string, fileHeader *multipart.FileHeader, createdAt time.Time) (string, error) {
+	// Define image settings.
+	maxFileSize, err := strconv.ParseInt(os.Getenv("POD_MAX_FILE_SIZE"), 10, 64)
+	if err != nil {
+		maxFileSize = 5 << 20
+	}
+	if fileHeader.Size > maxFileSize {
+		return "", fmt.Errorf("the %s must not be larger than %d bytes", kind, maxFileSize)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", utils.FailOnError(err, "the "+kind+" could not be read")
+	}
+	defer file.Close()
+
+	// The content decides the type, not the name of the file.
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", utils.FailOnError(err, "the "+kind+" could not be read")
+	}
+	extension, ok := proofOfDeliveryContentTypes[http.DetectContentType(head[:n])]
+	if !ok {
+		return "", fmt.Errorf("the %s must be a PNG or JPEG image", kind)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return "", utils.FailOnError(err, "the "+kind+" could not be read")
+	}
+
+	// A random suffix keeps images of the same second, or of a retried request, apart.
+	suffix := make([]byte, 8)
+	if _, err = rand.Read(suffix); err != nil {
+		return "", utils.FailOnError(err, "the "+kind+" could not be named")
+	}
+	key := fmt.Sprintf("pod/%d/%s-%d-%s%s", shippingOrderID, kind, createdAt.Unix(), hex.EncodeToString(suffix), extension)
+	if err = s.blobStore.Put(ctx, key, file); err != nil {
+		return "", utils.FailOnError(err, "the "+kind+" could not be stored")
+	}
+
+	return key, nil
+}
+
+// Removes the images of a proof of delivery that was not stored with its order.
+func (s *shippingOrderService) removeProofOfDelivery(ctx context.Context, proofOfDelivery *ProofOfDelivery) {
+	if proofOfDelivery == nil {
+		return
+	}
+
+	for _, key := range []string{proofOfDelivery.SignatureKey, proofOfDelivery.PhotoKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.blobStore.Delete(ctx, key); err != nil {
+			log.Printf("Oops... Image %s of a proof of delivery could not be removed! Reason: %v", key, err)
+		}
+	}
+}
+
+// Implementation of 'GetProofOfDelivery'.
+// Returns null when the shippingOrder does not exist for the application or has no proof of delivery.
+func (s *shippingOrderService) GetProofOfDelivery(ctx context.Context, shippingOrderID int, application string) (*ProofOfDeliveryOut, error) {
+	// Check if shippingOrder exists, the receiver data is only shown to its application.
+	searchedShippingOrder, err := s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil || searchedShippingOrder.Application != application {
+		return nil, nil
+	}
+
+	proofOfDelivery, err := s.shippingOrderRepository.GetProofOfDelivery(ctx, shippingOrderID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if proofOfDelivery == nil {
+		return nil, nil
+	}
+
+	// How far from the destination the order was handed over.
+	distanceKm := utils.Haversine(proofOfDelivery.Lat, proofOfDelivery.Lng, *searchedShippingOrder.Destination.LatDestination, *searchedShippingOrder.Destination.LngDestination)
+
+	proofOfDeliveryOut := &ProofOfDeliveryOut{
+		ShippingOrderID:  shippingOrderID,
+		ReceiverName:     proofOfDelivery.ReceiverName,
+		ReceiverDocument: proofOfDelivery.ReceiverDocument,
+		Lat:              proofOfDelivery.Lat,
+		Lng:              proofOfDelivery.Lng,
+		DistanceKm:       math.Round(distanceKm*1000) / 1000,
+		CreatedUser:      proofOfDelivery.CreatedUser,
+		CreatedAt:        proofOfDelivery.CreatedAt,
+	}
+	if proofOfDelivery.SignatureKey != "" {
+		proofOfDeliveryOut.SignatureURL = fmt.Sprintf("/api/v1/order/%d/pod/%s", shippingOrderID, POD_SIGNATURE)
+	}
+	if proofOfDelivery.PhotoKey != "" {
+		proofOfDeliveryOut.PhotoURL = fmt.Sprintf("/api/v1/order/%d/pod/%s", shippingOrderID, POD_PHOTO)
+	}
+
+	return proofOfDeliveryOut, nil
+}
+
+// Implementation of 'GetProofOfDeliveryFile'.
+// Returns the image with its content type, the caller must close it.
+// Returns null when the shippingOrder does not exist for the application, has no proof of delivery or the proof has no such image.
+func (s *shippingOrderService) GetProofOfDeliveryFile(ctx context.Context, shippingOrderID int, application string, kind string) (io.ReadCloser, string, error) {
+	// Check if shippingOrder exists, its images are only shown to its application.
+	searchedShippingOrder, err := s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+	if err != nil {
+		return nil, "", utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil || searchedShippingOrder.Application != application {
+		return nil, "", nil
+	}
+
+	proofOfDelivery, err := s.shippingOrderRepository.GetProofOfDelivery(ctx, shippingOrderID)
+	if err != nil {
+		return nil, "", utils.FailOnError(err, "information could not be retrieved")
+	}
+	if proofOfDelivery == nil {
+		return nil, "", nil
+	}
+
+	key := proofOfDelivery.SignatureKey
+	if kind == POD_PHOTO {
+		key = proofOfDelivery.PhotoKey
+	}
+	if key == "" {
+		return nil, "", nil
+	}
+
+	file, err := s.blobStore.Get(ctx, key)
+	if err != nil {
+		return nil, "", utils.FailOnError(err, "the "+kind+" could not be retrieved")
+	}
+	if file == nil {
+		return nil, "", fmt.Errorf("the %s of the proof of delivery is missing", kind)
+	}
+
+	return file, mime.TypeByExtension(path.Ext(key)), nil
+}
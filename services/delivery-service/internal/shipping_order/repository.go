@@ -3,45 +3,224 @@ package shipping_order
 import (
 	"context"
 	"database/sql"
+	"delivery-service/internal/address"
+	"delivery-service/internal/outbox"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 )
 
+// Columns read for every shippingOrder, in the order expected by 'scanShippingOrder'.
+// Sender and recipient come from 'parties', origin and destination from 'addresses', the preferences of the recipient from 'shipping_order_preferences'.
+const QUERY_SHIPPINGORDER_COLUMNS = "so.id,sp.document_id,sp.full_name,sp.phone,sp.email,rp.document_id,rp.full_name,rp.phone,rp.email," +
+	"oa.lat,oa.lng,oa.street,oa.number,oa.district,oa.city,oa.region,oa.country_code,oa.postal_code,oa.reference," +
+	"da.lat,da.lng,da.street,da.number,da.district,da.city,da.region,da.country_code,da.postal_code,da.reference," +
+	"so.packageSize,so.quantityProduct,so.weightProduct,so.originZoneId,so.destinationZoneId,so.serviceLevel,so.price,so.declaredValue,so.currency,so.insured,so.insurancePremium,so.cashOnDelivery,so.cashCollected,so.cashCollectedAt,so.deliveryAttempts,so.pickupFrom,so.pickupTo,so.trackingCode," +
+	"pf.deliveryWindowFrom,pf.deliveryWindowTo,pf.instructions,pf.neighbourName,pf.neighbourDocument,pf.neighbourAddress,pf.pickupPointName,pf.updated_at," +
+	"so.orderStatus,so.courierId,so.estimatedDeliveryFrom,so.estimatedDeliveryTo," +
+	"so.optOutSender,so.optOutRecipient,so.language,so.application,so.created_user,so.created_at,so.updated_user,so.updated_at,so.status"
+
+// Tables read for every shippingOrder.
+const QUERY_SHIPPINGORDER_FROM = " FROM shipping_order so " +
+	"JOIN parties sp ON sp.id = so.senderPartyId JOIN parties rp ON rp.id = so.recipientPartyId " +
+	"JOIN addresses oa ON oa.id = so.originAddressId JOIN addresses da ON da.id = so.destinationAddressId " +
+	"LEFT JOIN shipping_order_preferences pf ON pf.shippingOrderId = so.id "
+
 // Queries that we will use.
 const (
-	QUERY_GET_SHIPPINGORDER = "SELECT id,idSender,fullNameSender,phoneSender,emailSender,idRecipient,fullNameRecipient,phoneRecipient,emailRecipient,latOrigin,lngOrigin,addressOrigin, " +
-		"countryOrigin,zipcodeOrigin,referenceOrigin,latDestination,lngDestination,addressDestination,countryDestination,zipcodeDestination,referenceDestination," +
-		"packageSize,quantityProduct,weightProduct,orderStatus,created_user,created_at,updated_user,updated_at,status FROM shipping_order WHERE  id = ? and status = ?"
-	QUERY_GET_SHIPPINGORDER_SENDER = "SELECT id,idSender,fullNameSender,phoneSender,emailSender,idRecipient,fullNameRecipient,phoneRecipient,emailRecipient,latOrigin,lngOrigin,addressOrigin, " +
-		"countryOrigin,zipcodeOrigin,referenceOrigin,latDestination,lngDestination,addressDestination,countryDestination,zipcodeDestination,referenceDestination," +
-		"packageSize,quantityProduct,weightProduct,orderStatus,created_user,created_at,updated_user,updated_at,status FROM shipping_order " +
-		"WHERE  id = ? and idSender = ? and status = ?"
-	QUERY_CREATE_SHIPPINGORDER = "INSERT INTO shipping_order (idSender,fullNameSender,phoneSender,emailSender,idRecipient,fullNameRecipient,phoneRecipient,emailRecipient,latOrigin,lngOrigin,addressOrigin," +
-		"countryOrigin,zipcodeOrigin,referenceOrigin,latDestination,lngDestination,addressDestination,countryDestination,zipcodeDestination,referenceDestination," +
-		"packageSize,quantityProduct,weightProduct,orderStatus,created_user,created_at,updated_user,updated_at,status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
-	QUERY_UPDATE_SHIPPINGORDER = "UPDATE shipping_order SET orderStatus = ? , updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_GET_SHIPPINGORDER        = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM + "WHERE so.id = ? and so.status = ?"
+	QUERY_GET_SHIPPINGORDER_SENDER = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM +
+		"WHERE so.id = ? and sp.document_id = ? and so.status = ?"
+	QUERY_GET_SHIPPINGORDER_TRACKING    = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM + "WHERE so.trackingCode = ? and so.status = ?"
+	QUERY_GET_SHIPPINGORDER_IDEMPOTENCY = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM + "WHERE so.application = ? and so.idempotencyKey = ? and so.status = ?"
+	// Equal parties and addresses are stored once, the fingerprint finds the existing row.
+	QUERY_SAVE_PARTY = "INSERT INTO parties (fingerprint,document_id,full_name,phone,email,created_at) " +
+		"VALUES (SHA2(CONCAT_WS('|', ?, ?, ?, ?), 256), ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)"
+	QUERY_SAVE_ADDRESS = "INSERT INTO addresses (fingerprint,lat,lng,street,number,district,city,region,country_code,postal_code,reference,created_at) " +
+		"VALUES (SHA2(CONCAT_WS('|', CAST(? AS DECIMAL(9,6)), CAST(? AS DECIMAL(9,6)), ?, ?, ?, ?, ?, ?, ?, ?), 256), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)"
+	QUERY_CREATE_SHIPPINGORDER = "INSERT INTO shipping_order (senderPartyId,recipientPartyId,originAddressId,destinationAddressId," +
+		"packageSize,quantityProduct,weightProduct,originZoneId,destinationZoneId,serviceLevel,price,declaredValue,currency,insured,insurancePremium,cashOnDelivery,pickupFrom,pickupTo,trackingCode,idempotencyKey,orderStatus,estimatedDeliveryFrom,estimatedDeliveryTo,optOutSender,optOutRecipient,language,application,created_user,created_at,updated_user,updated_at,status,originPoint,destinationPoint) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, POINT(?, ?), POINT(?, ?))"
+	QUERY_CREATE_SHIPPINGORDER_PARCEL = "INSERT INTO shipping_order_parcels (shippingOrderId,sequence,packageSize,quantityProduct,weightProduct,declaredValue,parcelStatus,created_at,updated_user,updated_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_CREATE_SHIPPINGORDER_ITEM = "INSERT INTO shipping_order_items (parcelId,productId,name,quantity,unitValue,created_at) VALUES (?, ?, ?, ?, ?, ?)"
+	QUERY_GET_SHIPPINGORDER_PARCELS = "SELECT id,shippingOrderId,sequence,packageSize,quantityProduct,weightProduct,declaredValue,parcelStatus,stationId,updated_user,updated_at FROM shipping_order_parcels " +
+		"WHERE shippingOrderId IN (%s) order by shippingOrderId asc, sequence asc"
+	QUERY_GET_SHIPPINGORDER_ITEMS = "SELECT soi.parcelId,soi.productId,soi.name,soi.quantity,soi.unitValue FROM shipping_order_items soi " +
+		"JOIN shipping_order_parcels sop ON sop.id = soi.parcelId WHERE sop.shippingOrderId IN (%s) order by soi.id asc"
+	QUERY_UPDATE_SHIPPINGORDER_PARCEL   = "UPDATE shipping_order_parcels SET parcelStatus = ?, stationId = ?, updated_user = ?, updated_at = ? WHERE id = ? and shippingOrderId = ? and parcelStatus = ?"
+	QUERY_GET_PARCEL_STATUSES           = "SELECT parcelStatus FROM shipping_order_parcels WHERE shippingOrderId = ?"
+	QUERY_ADVANCE_SHIPPINGORDER_PARCELS = "UPDATE shipping_order_parcels SET parcelStatus = ?, stationId = ?, updated_user = ?, updated_at = ? WHERE shippingOrderId = ? and parcelStatus = ?"
+	QUERY_CANCEL_SHIPPINGORDER_PARCELS  = "UPDATE shipping_order_parcels SET parcelStatus = ?, updated_user = ?, updated_at = ? " +
+		"WHERE shippingOrderId = ? and parcelStatus not in ('entregado', 'cancelado')"
+	QUERY_LOCK_SHIPPINGORDER_STATUS   = "SELECT orderStatus FROM shipping_order WHERE id = ? FOR UPDATE"
+	QUERY_UPDATE_SHIPPINGORDER        = "UPDATE shipping_order SET orderStatus = ? , cashCollected = COALESCE(?, cashCollected), cashCollectedAt = COALESCE(?, cashCollectedAt), updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_UPDATE_SHIPPINGORDER_STATUS = "UPDATE shipping_order SET orderStatus = ? , updated_user = ?, updated_at = ? " +
+		"WHERE id = ? and orderStatus = ? and status = ?"
+	QUERY_UPDATE_SHIPPINGORDER_NOTIFICATIONS = "UPDATE shipping_order SET optOutSender = ?, optOutRecipient = ?, language = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_UPDATE_SHIPPINGORDER_COURIER       = "UPDATE shipping_order SET courierId = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_DISPATCH_SHIPPINGORDER_COURIER     = "UPDATE shipping_order SET courierId = ?, updated_user = ?, updated_at = ? WHERE id = ? and courierId IS NULL and orderStatus = ?"
+	QUERY_UPDATE_SHIPPINGORDER_ESTIMATE      = "UPDATE shipping_order SET estimatedDeliveryFrom = ?, estimatedDeliveryTo = ? WHERE id = ?"
+	QUERY_GET_UNDISPATCHED_SHIPPINGORDERS    = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM +
+		"WHERE so.application = ? and so.orderStatus = ? and so.courierId IS NULL and so.status = ? and (so.pickupFrom IS NULL or so.pickupFrom < ?) " +
+		"order by (SELECT COALESCE(MAX(sl.priority), 0) FROM service_level sl WHERE sl.nemo = so.serviceLevel and sl.status = 'A') desc, so.created_at asc limit ?"
+	QUERY_GET_COURIER_SHIPPINGORDERS = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM +
+		"WHERE so.courierId = ? and so.orderStatus in ('creado', 'recolectado', 'en_estacion', 'en_ruta', 'intento_fallido', 'devolucion') and so.status = ? order by so.created_at asc"
+	QUERY_GET_SHIPPINGORDERS_NEAR_ORIGIN = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM +
+		"WHERE MBRContains(ST_GeomFromText(?), so.originPoint) and ST_Distance_Sphere(so.originPoint, POINT(?, ?)) <= ? and so.status = ? " +
+		"order by ST_Distance_Sphere(so.originPoint, POINT(?, ?)) asc limit ?"
+	QUERY_GET_SHIPPINGORDERS_NEAR_DESTINATION = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM +
+		"WHERE MBRContains(ST_GeomFromText(?), so.destinationPoint) and ST_Distance_Sphere(so.destinationPoint, POINT(?, ?)) <= ? and so.status = ? " +
+		"order by ST_Distance_Sphere(so.destinationPoint, POINT(?, ?)) asc limit ?"
+	QUERY_GET_SHIPPINGORDERS_IN_BOX_ORIGIN = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM +
+		"WHERE MBRContains(ST_GeomFromText(?), so.originPoint) and so.status = ? order by so.created_at desc limit ?"
+	QUERY_GET_SHIPPINGORDERS_IN_BOX_DESTINATION = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM +
+		"WHERE MBRContains(ST_GeomFromText(?), so.destinationPoint) and so.status = ? order by so.created_at desc limit ?"
+	QUERY_GET_COURIER_WORKLOADS = "SELECT so.courierId, COUNT(DISTINCT so.id), COALESCE(SUM(ps.limitvalue), 0) FROM shipping_order so " +
+		"JOIN shipping_order_parcels sop ON sop.shippingOrderId = so.id and sop.parcelStatus <> 'cancelado' " +
+		"LEFT JOIN package_size ps ON ps.nemo = sop.packageSize and ps.status = 'A' " +
+		"WHERE so.courierId IS NOT NULL and so.orderStatus in ('creado', 'recolectado', 'en_estacion', 'en_ruta', 'intento_fallido', 'devolucion') and so.status = ? " +
+		"GROUP BY so.courierId"
+	QUERY_CREATE_DELIVERY_ATTEMPT  = "INSERT INTO shipping_order_attempts (shippingOrderId,attempt,reason,notes,courierId,created_user,created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	QUERY_UPDATE_DELIVERY_ATTEMPTS = "UPDATE shipping_order SET deliveryAttempts = ? WHERE id = ?"
+	QUERY_GET_DELIVERY_ATTEMPTS    = "SELECT attempt,reason,notes,courierId,created_user,created_at FROM shipping_order_attempts WHERE shippingOrderId = ? order by attempt asc"
+	QUERY_CREATE_PROOF_OF_DELIVERY = "INSERT INTO shipping_order_pod (shippingOrderId,receiverName,receiverDocument,lat,lng,signatureKey,photoKey,created_user,created_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_GET_PROOF_OF_DELIVERY         = "SELECT shippingOrderId,receiverName,receiverDocument,lat,lng,signatureKey,photoKey,created_user,created_at FROM shipping_order_pod WHERE shippingOrderId = ?"
+	QUERY_GET_SHIPPINGORDERS_TO_PICK_UP = "SELECT " + QUERY_SHIPPINGORDER_COLUMNS + QUERY_SHIPPINGORDER_FROM +
+		"WHERE so.pickupFrom >= ? and so.pickupFrom < ? and (? = '' or so.originZoneId = ?) and so.orderStatus = ? and so.status = ? " +
+		"order by so.pickupFrom asc, so.pickupTo asc, so.created_at asc"
+	// The destination is locked with the preferences, the address it had before a redirection is kept to restore it.
+	QUERY_LOCK_SHIPPINGORDER_DESTINATION = "SELECT so.orderStatus,so.destinationAddressId,so.destinationZoneId,pf.redirectedFromAddressId,pf.redirectedFromZoneId " +
+		"FROM shipping_order so LEFT JOIN shipping_order_preferences pf ON pf.shippingOrderId = so.id WHERE so.id = ? FOR UPDATE"
+	QUERY_REDIRECT_SHIPPINGORDER = "UPDATE shipping_order SET destinationAddressId = ?, destinationZoneId = ?, destinationPoint = POINT(?, ?), updated_at = ? WHERE id = ?"
+	QUERY_RESTORE_SHIPPINGORDER  = "UPDATE shipping_order so JOIN addresses a ON a.id = ? " +
+		"SET so.destinationAddressId = a.id, so.destinationZoneId = ?, so.destinationPoint = POINT(a.lng, a.lat), so.updated_at = ? WHERE so.id = ?"
+	QUERY_SAVE_DELIVERY_PREFERENCES = "INSERT INTO shipping_order_preferences (shippingOrderId,deliveryWindowFrom,deliveryWindowTo,instructions,neighbourName,neighbourDocument,neighbourAddress," +
+		"pickupPointName,redirectedFromAddressId,redirectedFromZoneId,updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE deliveryWindowFrom = VALUES(deliveryWindowFrom), deliveryWindowTo = VALUES(deliveryWindowTo), instructions = VALUES(instructions), " +
+		"neighbourName = VALUES(neighbourName), neighbourDocument = VALUES(neighbourDocument), neighbourAddress = VALUES(neighbourAddress), pickupPointName = VALUES(pickupPointName), " +
+		"redirectedFromAddressId = VALUES(redirectedFromAddressId), redirectedFromZoneId = VALUES(redirectedFromZoneId), updated_at = VALUES(updated_at)"
+	// Cash collected in the period and cash still to be collected, whatever its date.
+	QUERY_GET_COURIER_CASH_ON_DELIVERY = "SELECT id,orderStatus,currency,cashOnDelivery,cashCollected,cashCollectedAt FROM shipping_order " +
+		"WHERE courierId = ? and cashOnDelivery > 0 and status = ? " +
+		"and ((cashCollectedAt >= ? and cashCollectedAt < ?) or (cashCollectedAt IS NULL and orderStatus not in ('entregado', 'cancelado', 'devolucion', 'devuelto'))) " +
+		"order by created_at asc"
+	QUERY_COUNT_STATION_PARCELS = "SELECT COUNT(*) FROM shipping_order_parcels WHERE stationId = ? and parcelStatus = 'en_estacion'"
+	QUERY_LOCK_STATION          = "SELECT capacity FROM station WHERE id = ? and status = ? FOR UPDATE"
+	QUERY_COUNT_MOVING_PARCELS  = "SELECT COUNT(*) FROM shipping_order_parcels WHERE shippingOrderId = ? and parcelStatus = ?"
+	QUERY_GET_STATION_PARCELS   = "SELECT so.id,so.trackingCode,sop.id,sop.sequence,sop.packageSize,sop.weightProduct,so.destinationZoneId,so.serviceLevel,sop.updated_at " +
+		"FROM shipping_order_parcels sop JOIN shipping_order so ON so.id = sop.shippingOrderId " +
+		"WHERE sop.stationId = ? and sop.parcelStatus = 'en_estacion' and so.status = ? order by sop.updated_at asc, sop.id asc"
 )
 
 // Represents that we will use MariaDB in order to implement the methods.
 type mariaDBRepository struct {
-	mariadb *sql.DB
+	mariadb          *sql.DB
+	outboxRepository outbox.OutboxRepository
 }
 
 // Create a new repository with MariaDB as the driver.
-func NewShippingOrderRepository(mariaDBConnection *sql.DB) ShippingOrderRepository {
+// Events are written to the outbox in the same transaction as the shippingOrder.
+func NewShippingOrderRepository(mariaDBConnection *sql.DB, o outbox.OutboxRepository) ShippingOrderRepository {
 	return &mariaDBRepository{
-		mariadb: mariaDBConnection,
+		mariadb:          mariaDBConnection,
+		outboxRepository: o,
 	}
 }
 
-// Gets a single shippingOrder in the database.
-func (r *mariaDBRepository) GetShippingOrder(ctx context.Context, shippingOrderID int) (*ShippingOrderOut, error) {
+// Scanner of a single row, satisfied by both '*sql.Row' and '*sql.Rows'.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans the columns of 'QUERY_SHIPPINGORDER_COLUMNS' into a new shippingOrder.
+func scanShippingOrder(row rowScanner) (*ShippingOrderOut, error) {
 	// Initialize variable.
 	shippingOrder := &ShippingOrderOut{}
 	shippingOrderSender := &ShippingOrderSender{}
 	shippingOrderRecipient := &ShippingOrderRecipient{}
 	shippingOrderOrigin := &ShippingOrderOrigin{}
 	shippingOrderDestination := &ShippingOrderDestination{}
+	originAddress := &address.Address{}
+	destinationAddress := &address.Address{}
 	shippingOrderPackage := &ShippingOrderPackage{}
+	shippingOrderNotifications := &ShippingOrderNotifications{}
+	var courierID sql.NullInt64
+	var cashCollected sql.NullFloat64
+	var estimatedDeliveryFrom, estimatedDeliveryTo, cashCollectedAt, pickupFrom, pickupTo sql.NullTime
+	var deliveryWindowFrom, deliveryWindowTo, preferencesUpdatedAt sql.NullTime
+	var instructions, neighbourName, neighbourDocument, neighbourAddress, pickupPointName sql.NullString
 
+	err := row.Scan(&shippingOrder.ID,
+		&shippingOrderSender.IdSender, &shippingOrderSender.FullNameSender, &shippingOrderSender.PhoneSender, &shippingOrderSender.EmailSender,
+		&shippingOrderRecipient.IdRecipient, &shippingOrderRecipient.FullNameRecipient, &shippingOrderRecipient.PhoneRecipient, &shippingOrderRecipient.EmailRecipient,
+		&shippingOrderOrigin.LatOrigin, &shippingOrderOrigin.LngOrigin,
+		&originAddress.Street, &originAddress.Number, &originAddress.District, &originAddress.City, &originAddress.Region, &originAddress.CountryCode, &originAddress.PostalCode, &shippingOrderOrigin.ReferenceOrigin,
+		&shippingOrderDestination.LatDestination, &shippingOrderDestination.LngDestination,
+		&destinationAddress.Street, &destinationAddress.Number, &destinationAddress.District, &destinationAddress.City, &destinationAddress.Region, &destinationAddress.CountryCode, &destinationAddress.PostalCode, &shippingOrderDestination.ReferenceDestination,
+		&shippingOrderPackage.PackageSize, &shippingOrderPackage.QuantityProduct, &shippingOrderPackage.WeightProduct,
+		&shippingOrder.OriginZoneID, &shippingOrder.DestinationZoneID, &shippingOrder.ServiceLevel, &shippingOrder.Price, &shippingOrder.DeclaredValue,
+		&shippingOrder.Currency, &shippingOrder.Insured, &shippingOrder.InsurancePremium, &shippingOrder.CashOnDelivery, &cashCollected, &cashCollectedAt, &shippingOrder.DeliveryAttempts, &pickupFrom, &pickupTo, &shippingOrder.TrackingCode,
+		&deliveryWindowFrom, &deliveryWindowTo, &instructions, &neighbourName, &neighbourDocument, &neighbourAddress, &pickupPointName, &preferencesUpdatedAt,
+		&shippingOrder.OrderStatus, &courierID, &estimatedDeliveryFrom, &estimatedDeliveryTo, &shippingOrderNotifications.OptOutSender, &shippingOrderNotifications.OptOutRecipient, &shippingOrderNotifications.Language, &shippingOrder.Application, &shippingOrder.CreatedUser, &shippingOrder.CreatedAt, &shippingOrder.UpdatedUser, &shippingOrder.UpdatedAt, &shippingOrder.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	shippingOrder.Sender = shippingOrderSender
+	shippingOrder.Recipient = shippingOrderRecipient
+	shippingOrderOrigin.AddressOrigin = originAddress
+	shippingOrderDestination.AddressDestination = destinationAddress
+	shippingOrder.Origin = shippingOrderOrigin
+	shippingOrder.Destination = shippingOrderDestination
+	shippingOrder.Package = shippingOrderPackage
+	shippingOrder.Notifications = shippingOrderNotifications
+	if courierID.Valid {
+		assignedCourierID := int(courierID.Int64)
+		shippingOrder.CourierID = &assignedCourierID
+	}
+	if estimatedDeliveryFrom.Valid && estimatedDeliveryTo.Valid {
+		shippingOrder.EstimatedDeliveryFrom = &estimatedDeliveryFrom.Time
+		shippingOrder.EstimatedDeliveryTo = &estimatedDeliveryTo.Time
+	}
+	if cashCollected.Valid && cashCollectedAt.Valid {
+		shippingOrder.CashCollected = &cashCollected.Float64
+		shippingOrder.CashCollectedAt = &cashCollectedAt.Time
+	}
+	if pickupFrom.Valid && pickupTo.Valid {
+		shippingOrder.PickupFrom = &pickupFrom.Time
+		shippingOrder.PickupTo = &pickupTo.Time
+	}
+	if preferencesUpdatedAt.Valid {
+		preferences := &DeliveryPreferencesOut{
+			Instructions: instructions.String,
+			PickupPoint:  pickupPointName.String,
+			UpdatedAt:    preferencesUpdatedAt.Time,
+		}
+		if deliveryWindowFrom.Valid && deliveryWindowTo.Valid {
+			preferences.DeliveryWindowFrom = &deliveryWindowFrom.Time
+			preferences.DeliveryWindowTo = &deliveryWindowTo.Time
+		}
+		if neighbourName.Valid {
+			preferences.Neighbour = &AuthorizedNeighbour{
+				FullName: neighbourName.String,
+				Document: neighbourDocument.String,
+				Address:  neighbourAddress.String,
+			}
+		}
+		shippingOrder.Preferences = preferences
+	}
+	shippingOrderPackage.DeclaredValue = shippingOrder.DeclaredValue
+	shippingOrderPackage.Currency = shippingOrder.Currency
+	return shippingOrder, nil
+}
+
+// Gets a single shippingOrder in the database.
+func (r *mariaDBRepository) GetShippingOrder(ctx context.Context, shippingOrderID int) (*ShippingOrderOut, error) {
 	// Prepare SQL to get one shippingOrder.
 	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_GET_SHIPPINGORDER)
 	if err != nil {
@@ -51,13 +230,7 @@ func (r *mariaDBRepository) GetShippingOrder(ctx context.Context, shippingOrderI
 
 	// Get one shippingOrder and insert it to the 'shippingOrder' struct.
 	// If it's empty, return null.
-	err = stmt.QueryRowContext(ctx, shippingOrderID, "A").Scan(&shippingOrder.ID,
-		&shippingOrderSender.IdSender, &shippingOrderSender.FullNameSender, &shippingOrderSender.PhoneSender, &shippingOrderSender.EmailSender,
-		&shippingOrderRecipient.IdRecipient, &shippingOrderRecipient.FullNameRecipient, &shippingOrderRecipient.PhoneRecipient, &shippingOrderRecipient.EmailRecipient,
-		&shippingOrderOrigin.LatOrigin, &shippingOrderOrigin.LngOrigin, &shippingOrderOrigin.AddressOrigin, &shippingOrderOrigin.CountryOrigin, &shippingOrderOrigin.ZipcodeOrigin, &shippingOrderOrigin.ReferenceOrigin,
-		&shippingOrderDestination.LatDestination, &shippingOrderDestination.LngDestination, &shippingOrderDestination.AddressDestination, &shippingOrderDestination.CountryDestination, &shippingOrderDestination.ZipcodeDestination, &shippingOrderDestination.ReferenceDestination,
-		&shippingOrderPackage.PackageSize, &shippingOrderPackage.QuantityProduct, &shippingOrderPackage.WeightProduct,
-		&shippingOrder.OrderStatus, &shippingOrder.CreatedUser, &shippingOrder.CreatedAt, &shippingOrder.UpdatedUser, &shippingOrder.UpdatedAt, &shippingOrder.Status)
+	shippingOrder, err := scanShippingOrder(stmt.QueryRowContext(ctx, shippingOrderID, "A"))
 	if err != nil && err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -66,24 +239,11 @@ func (r *mariaDBRepository) GetShippingOrder(ctx context.Context, shippingOrderI
 	}
 
 	// Return result.
-	shippingOrder.Sender = shippingOrderSender
-	shippingOrder.Recipient = shippingOrderRecipient
-	shippingOrder.Origin = shippingOrderOrigin
-	shippingOrder.Destination = shippingOrderDestination
-	shippingOrder.Package = shippingOrderPackage
-	return shippingOrder, nil
+	return shippingOrder, r.attachParcels(ctx, []*ShippingOrderOut{shippingOrder})
 }
 
 // Gets a single shippingOrder in the database.
 func (r *mariaDBRepository) GetSenderShippingOrder(ctx context.Context, shippingOrderID int, idSender string) (*ShippingOrderOut, error) {
-	// Initialize variable.
-	shippingOrder := &ShippingOrderOut{}
-	shippingOrderSender := &ShippingOrderSender{}
-	shippingOrderRecipient := &ShippingOrderRecipient{}
-	shippingOrderOrigin := &ShippingOrderOrigin{}
-	shippingOrderDestination := &ShippingOrderDestination{}
-	shippingOrderPackage := &ShippingOrderPackage{}
-
 	// Prepare SQL to get one shippingOrder.
 	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_GET_SHIPPINGORDER_SENDER)
 	if err != nil {
@@ -93,13 +253,7 @@ func (r *mariaDBRepository) GetSenderShippingOrder(ctx context.Context, shipping
 
 	// Get one shippingOrder and insert it to the 'shippingOrder' struct.
 	// If it's empty, return null.
-	err = stmt.QueryRowContext(ctx, shippingOrderID, idSender, "A").Scan(&shippingOrder.ID,
-		&shippingOrderSender.IdSender, &shippingOrderSender.FullNameSender, &shippingOrderSender.PhoneSender, &shippingOrderSender.EmailSender,
-		&shippingOrderRecipient.IdRecipient, &shippingOrderRecipient.FullNameRecipient, &shippingOrderRecipient.PhoneRecipient, &shippingOrderRecipient.EmailRecipient,
-		&shippingOrderOrigin.LatOrigin, &shippingOrderOrigin.LngOrigin, &shippingOrderOrigin.AddressOrigin, &shippingOrderOrigin.CountryOrigin, &shippingOrderOrigin.ZipcodeOrigin, &shippingOrderOrigin.ReferenceOrigin,
-		&shippingOrderDestination.LatDestination, &shippingOrderDestination.LngDestination, &shippingOrderDestination.AddressDestination, &shippingOrderDestination.CountryDestination, &shippingOrderDestination.ZipcodeDestination, &shippingOrderDestination.ReferenceDestination,
-		&shippingOrderPackage.PackageSize, &shippingOrderPackage.QuantityProduct, &shippingOrderPackage.WeightProduct,
-		&shippingOrder.OrderStatus, &shippingOrder.CreatedUser, &shippingOrder.CreatedAt, &shippingOrder.UpdatedUser, &shippingOrder.UpdatedAt, &shippingOrder.Status)
+	shippingOrder, err := scanShippingOrder(stmt.QueryRowContext(ctx, shippingOrderID, idSender, "A"))
 	if err != nil && err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -108,49 +262,686 @@ func (r *mariaDBRepository) GetSenderShippingOrder(ctx context.Context, shipping
 	}
 
 	// Return result.
-	shippingOrder.Sender = shippingOrderSender
-	shippingOrder.Recipient = shippingOrderRecipient
-	shippingOrder.Origin = shippingOrderOrigin
-	shippingOrder.Destination = shippingOrderDestination
-	shippingOrder.Package = shippingOrderPackage
-	return shippingOrder, nil
+	return shippingOrder, r.attachParcels(ctx, []*ShippingOrderOut{shippingOrder})
 }
 
-// Creates a single shippingOrder in the database.
-func (r *mariaDBRepository) CreateShippingOrder(ctx context.Context, shippingOrder *ShippingOrder) (sql.Result, error) {
+// Creates a single shippingOrder in the database together with its event.
+func (r *mariaDBRepository) CreateShippingOrder(ctx context.Context, shippingOrder *ShippingOrder, event *outbox.Event) (sql.Result, error) {
+	// Begin transaction.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Store the parties and addresses the shippingOrder refers to.
+	senderPartyID, err := saveParty(ctx, tx, shippingOrder.IdSender, shippingOrder.FullNameSender, shippingOrder.PhoneSender, shippingOrder.EmailSender)
+	if err != nil {
+		return nil, err
+	}
+	recipientPartyID, err := saveParty(ctx, tx, shippingOrder.IdRecipient, shippingOrder.FullNameRecipient, shippingOrder.PhoneRecipient, shippingOrder.EmailRecipient)
+	if err != nil {
+		return nil, err
+	}
+	originAddressID, err := saveAddress(ctx, tx, shippingOrder.LatOrigin, shippingOrder.LngOrigin, &address.Address{
+		Street:      shippingOrder.AddressOrigin,
+		Number:      shippingOrder.NumberOrigin,
+		District:    shippingOrder.DistrictOrigin,
+		City:        shippingOrder.CityOrigin,
+		Region:      shippingOrder.RegionOrigin,
+		CountryCode: shippingOrder.CountryOrigin,
+		PostalCode:  shippingOrder.ZipcodeOrigin,
+	}, shippingOrder.ReferenceOrigin)
+	if err != nil {
+		return nil, err
+	}
+	destinationAddressID, err := saveAddress(ctx, tx, shippingOrder.LatDestination, shippingOrder.LngDestination, &address.Address{
+		Street:      shippingOrder.AddressDestination,
+		Number:      shippingOrder.NumberDestination,
+		District:    shippingOrder.DistrictDestination,
+		City:        shippingOrder.CityDestination,
+		Region:      shippingOrder.RegionDestination,
+		CountryCode: shippingOrder.CountryDestination,
+		PostalCode:  shippingOrder.ZipcodeDestination,
+	}, shippingOrder.ReferenceDestination)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare context to be used.
-	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_CREATE_SHIPPINGORDER)
+	stmt, err := tx.PrepareContext(ctx, QUERY_CREATE_SHIPPINGORDER)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
 	// Insert one shippingOrder.
-	result, err := stmt.ExecContext(ctx, shippingOrder.IdSender, shippingOrder.FullNameSender, shippingOrder.PhoneSender, shippingOrder.EmailSender,
-		shippingOrder.IdRecipient, shippingOrder.FullNameRecipient, shippingOrder.PhoneRecipient, shippingOrder.EmailRecipient,
-		shippingOrder.LatOrigin, shippingOrder.LngOrigin, shippingOrder.AddressOrigin, shippingOrder.CountryOrigin, shippingOrder.ZipcodeOrigin, shippingOrder.ReferenceOrigin,
-		shippingOrder.LatDestination, shippingOrder.LngDestination, shippingOrder.AddressDestination, shippingOrder.CountryDestination, shippingOrder.ZipcodeDestination, shippingOrder.ReferenceDestination,
+	result, err := stmt.ExecContext(ctx, senderPartyID, recipientPartyID, originAddressID, destinationAddressID,
 		shippingOrder.PackageSize, shippingOrder.QuantityProduct, shippingOrder.WeightProduct,
-		shippingOrder.OrderStatus, shippingOrder.CreatedUser, shippingOrder.CreatedAt, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrder.Status)
+		shippingOrder.OriginZoneID, shippingOrder.DestinationZoneID, shippingOrder.ServiceLevel, shippingOrder.Price, shippingOrder.DeclaredValue,
+		shippingOrder.Currency, shippingOrder.Insured, shippingOrder.InsurancePremium, shippingOrder.CashOnDelivery, shippingOrder.PickupFrom, shippingOrder.PickupTo, shippingOrder.TrackingCode, shippingOrder.IdempotencyKey, shippingOrder.OrderStatus, shippingOrder.EstimatedDeliveryFrom, shippingOrder.EstimatedDeliveryTo, shippingOrder.OptOutSender, shippingOrder.OptOutRecipient, shippingOrder.Language, shippingOrder.Application, shippingOrder.CreatedUser, shippingOrder.CreatedAt, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrder.Status,
+		shippingOrder.LngOrigin, shippingOrder.LatOrigin, shippingOrder.LngDestination, shippingOrder.LatDestination)
 	if err != nil {
 		return nil, err
 	}
 
-	// Return empty.
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	// Store every parcel of the shippingOrder.
+	for _, parcel := range shippingOrder.Parcels {
+		parcel.ShippingOrderID = int(insertedID)
+		parcelResult, err := tx.ExecContext(ctx, QUERY_CREATE_SHIPPINGORDER_PARCEL, parcel.ShippingOrderID, parcel.Sequence, parcel.PackageSize, parcel.QuantityProduct, parcel.WeightProduct,
+			parcel.DeclaredValue, parcel.ParcelStatus, parcel.CreatedAt, parcel.UpdatedUser, parcel.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		parcelID, err := parcelResult.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		parcel.ID = int(parcelID)
+
+		// Store the products of the parcel.
+		for _, item := range parcel.Items {
+			_, err = tx.ExecContext(ctx, QUERY_CREATE_SHIPPINGORDER_ITEM, parcel.ID, item.ProductID, item.Name, item.Quantity, item.UnitValue, parcel.CreatedAt)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Store the event of the new shippingOrder.
+	event.AggregateID = int(insertedID)
+	if err = r.outboxRepository.SaveEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Return result.
 	return result, nil
 }
 
-// Updates a single shippingOrder in the database.
-func (r *mariaDBRepository) UpdateShippingOrder(ctx context.Context, shippingOrderID int, shippingOrder *ShippingOrder) error {
+// Stores a party in the transaction, or finds the equal one, and returns its ID.
+func saveParty(ctx context.Context, tx *sql.Tx, documentID string, fullName string, phone string, email string) (int64, error) {
+	result, err := tx.ExecContext(ctx, QUERY_SAVE_PARTY, documentID, fullName, phone, email, documentID, fullName, phone, email, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// Stores an address in the transaction, or finds the equal one, and returns its ID.
+func saveAddress(ctx context.Context, tx *sql.Tx, lat float64, lng float64, a *address.Address, reference string) (int64, error) {
+	result, err := tx.ExecContext(ctx, QUERY_SAVE_ADDRESS,
+		lat, lng, a.Street, a.Number, a.District, a.City, a.Region, a.CountryCode, a.PostalCode, reference,
+		lat, lng, a.Street, a.Number, a.District, a.City, a.Region, a.CountryCode, a.PostalCode, reference, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// Updates a single shippingOrder in the database together with its event.
+// The shippingOrder must still be in 'previousOrderStatus', the status its change was checked against.
+func (r *mariaDBRepository) UpdateShippingOrder(ctx context.Context, shippingOrderID int, previousOrderStatus string, shippingOrder *ShippingOrder, event *outbox.Event) error {
+	// Begin transaction.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the shippingOrder, it must not have moved since it was checked.
+	if err = lockOrderStatus(ctx, tx, shippingOrderID, previousOrderStatus); err != nil {
+		return err
+	}
+
+	// Its parcels arriving at a station must fit in it.
+	if shippingOrder.StationID != nil {
+		room, err := lockStationRoom(ctx, tx, *shippingOrder.StationID)
+		if err != nil {
+			return err
+		}
+		incoming, err := countMovingParcels(ctx, tx, shippingOrderID, previousOrderStatus)
+		if err != nil {
+			return err
+		}
+		if incoming > room {
+			return ErrStationFull
+		}
+	}
+
+	// Update one shippingOrder.
+	_, err = tx.ExecContext(ctx, QUERY_UPDATE_SHIPPINGORDER, shippingOrder.OrderStatus, shippingOrder.CashCollected, shippingOrder.CashCollectedAt, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrderID)
+	if err != nil {
+		return err
+	}
+
+	if err = moveParcels(ctx, tx, shippingOrderID, previousOrderStatus, shippingOrder); err != nil {
+		return err
+	}
+
+	if err = saveProofOfDelivery(ctx, tx, shippingOrder.ProofOfDelivery); err != nil {
+		return err
+	}
+
+	if err = saveDeliveryAttempt(ctx, tx, shippingOrder.DeliveryAttempt); err != nil {
+		return err
+	}
+
+	// Store the event of the change.
+	if err = r.outboxRepository.SaveEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	// Return empty.
+	return tx.Commit()
+}
+
+// Updates the status of several shippingOrders in a single transaction.
+// Orders that are no longer in their previous status, or whose parcels do not fit in the station they arrive at,
+// are not updated and are returned with the reason.
+// When 'atomic' is set, any of those rejections rolls back the whole transaction.
+// 'previousOrderStatuses' and 'events' hold the previous status and the event of each order, in the same position as its ID.
+func (r *mariaDBRepository) UpdateShippingOrdersStatus(ctx context.Context, shippingOrderIDs []int, previousOrderStatuses []string, shippingOrder *ShippingOrder, events []*outbox.Event, atomic bool) (map[int]error, error) {
+	// Initialize variables.
+	rejected := make(map[int]error)
+
+	// Begin transaction.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	// Prepare context to be used.
-	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_SHIPPINGORDER)
+	stmt, err := tx.PrepareContext(ctx, QUERY_UPDATE_SHIPPINGORDER_STATUS)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Orders arriving at a station take its room in turn.
+	room := 0
+	if shippingOrder.StationID != nil {
+		if room, err = lockStationRoom(ctx, tx, *shippingOrder.StationID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update every shippingOrder.
+	for i, shippingOrderID := range shippingOrderIDs {
+		if shippingOrder.StationID != nil {
+			err = lockOrderStatus(ctx, tx, shippingOrderID, previousOrderStatuses[i])
+			if err == ErrShippingOrderChanged || err == sql.ErrNoRows {
+				rejected[shippingOrderID] = ErrShippingOrderChanged
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			incoming, err := countMovingParcels(ctx, tx, shippingOrderID, previousOrderStatuses[i])
+			if err != nil {
+				return nil, err
+			}
+			if incoming > room {
+				rejected[shippingOrderID] = ErrStationFull
+				continue
+			}
+			room -= incoming
+		}
+
+		result, err := stmt.ExecContext(ctx, shippingOrder.OrderStatus, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrderID, previousOrderStatuses[i], "A")
+		if err != nil {
+			return nil, err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			rejected[shippingOrderID] = ErrShippingOrderChanged
+			continue
+		}
+
+		if err = moveParcels(ctx, tx, shippingOrderID, previousOrderStatuses[i], shippingOrder); err != nil {
+			return nil, err
+		}
+
+		// Store the event of the change.
+		if err = r.outboxRepository.SaveEvent(ctx, tx, events[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if atomic && len(rejected) > 0 {
+		return rejected, nil
+	}
+
+	// Return rejections.
+	return rejected, tx.Commit()
+}
+
+// Moves the parcels that were in the previous status of the shippingOrder to its new status, and to its station if any.
+// Parcels already ahead keep their status, a cancelled shippingOrder cancels every parcel not delivered.
+func moveParcels(ctx context.Context, tx *sql.Tx, shippingOrderID int, previousOrderStatus string, shippingOrder *ShippingOrder) error {
+	if shippingOrder.OrderStatus == "cancelado" {
+		_, err := tx.ExecContext(ctx, QUERY_CANCEL_SHIPPINGORDER_PARCELS, shippingOrder.OrderStatus, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrderID)
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, QUERY_ADVANCE_SHIPPINGORDER_PARCELS, shippingOrder.OrderStatus, shippingOrder.StationID, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrderID, previousOrderStatus)
+	return err
+}
+
+// Stores the proof of delivery of a shippingOrder in the transaction, if any.
+func saveProofOfDelivery(ctx context.Context, tx *sql.Tx, proofOfDelivery *ProofOfDelivery) error {
+	if proofOfDelivery == nil {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, QUERY_CREATE_PROOF_OF_DELIVERY, proofOfDelivery.ShippingOrderID, proofOfDelivery.ReceiverName, proofOfDelivery.ReceiverDocument,
+		proofOfDelivery.Lat, proofOfDelivery.Lng, proofOfDelivery.SignatureKey, proofOfDelivery.PhotoKey, proofOfDelivery.CreatedUser, proofOfDelivery.CreatedAt)
+	return err
+}
+
+// Stores a failed delivery attempt of a shippingOrder in the transaction, if any, and counts it on the shippingOrder.
+func saveDeliveryAttempt(ctx context.Context, tx *sql.Tx, deliveryAttempt *DeliveryAttempt) error {
+	if deliveryAttempt == nil {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, QUERY_CREATE_DELIVERY_ATTEMPT, deliveryAttempt.ShippingOrderID, deliveryAttempt.Attempt, deliveryAttempt.Reason, deliveryAttempt.Notes,
+		deliveryAttempt.CourierID, deliveryAttempt.CreatedUser, deliveryAttempt.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, QUERY_UPDATE_DELIVERY_ATTEMPTS, deliveryAttempt.Attempt, deliveryAttempt.ShippingOrderID)
+	return err
+}
+
+// Updates the status of a single parcel in the database.
+// When 'shippingOrder' is set, the status of the whole shippingOrder changes with it in the same transaction.
+// The shippingOrder and the parcel must still be in the statuses the change was checked against,
+// and the parcels, as they are once it is locked, must lead to the same status of the shippingOrder.
+func (r *mariaDBRepository) UpdateShippingOrderParcel(ctx context.Context, shippingOrderID int, parcelID int, previousOrderStatus string, previousParcelStatus string, parcel *ShippingOrderParcel, shippingOrder *ShippingOrder, event *outbox.Event) error {
+	// Begin transaction.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the shippingOrder, its parcels only change while it is locked.
+	if err = lockOrderStatus(ctx, tx, shippingOrderID, previousOrderStatus); err != nil {
+		return err
+	}
+
+	// A parcel arriving at a station must fit in it.
+	if parcel.StationID != nil {
+		room, err := lockStationRoom(ctx, tx, *parcel.StationID)
+		if err != nil {
+			return err
+		}
+		if room < 1 {
+			return ErrStationFull
+		}
+	}
+
+	// Update one parcel.
+	result, err := tx.ExecContext(ctx, QUERY_UPDATE_SHIPPINGORDER_PARCEL, parcel.ParcelStatus, parcel.StationID, parcel.UpdatedUser, parcel.UpdatedAt, parcelID, shippingOrderID, previousParcelStatus)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrShippingOrderChanged
+	}
+
+	// Derive the status of the shippingOrder from its parcels as they are now.
+	orderStatus, err := deriveLockedOrderStatus(ctx, tx, shippingOrderID)
+	if err != nil {
+		return err
+	}
+	expectedOrderStatus := previousOrderStatus
+	if shippingOrder != nil {
+		expectedOrderStatus = shippingOrder.OrderStatus
+	}
+	if orderStatus != expectedOrderStatus {
+		return ErrShippingOrderChanged
+	}
+
+	if shippingOrder == nil {
+		return tx.Commit()
+	}
+
+	// Update the shippingOrder, its parcels are already where they belong.
+	_, err = tx.ExecContext(ctx, QUERY_UPDATE_SHIPPINGORDER, shippingOrder.OrderStatus, shippingOrder.CashCollected, shippingOrder.CashCollectedAt, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrderID)
+	if err != nil {
+		return err
+	}
+
+	if err = saveProofOfDelivery(ctx, tx, shippingOrder.ProofOfDelivery); err != nil {
+		return err
+	}
+
+	// Store the event of the change.
+	if err = r.outboxRepository.SaveEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	// Return empty.
+	return tx.Commit()
+}
+
+// Locks a station in the transaction and counts the parcels it still has room for.
+// Arrivals to the station wait for each other, the count is taken once the station is locked.
+func lockStationRoom(ctx context.Context, tx *sql.Tx, stationID int) (int, error) {
+	var capacity int
+	err := tx.QueryRowContext(ctx, QUERY_LOCK_STATION, stationID, "A").Scan(&capacity)
+	if err == sql.ErrNoRows {
+		return 0, ErrStationUnavailable
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var occupancy int
+	if err = tx.QueryRowContext(ctx, QUERY_COUNT_STATION_PARCELS, stationID).Scan(&occupancy); err != nil {
+		return 0, err
+	}
+
+	return capacity - occupancy, nil
+}
+
+// Counts the parcels that move with a locked shippingOrder, the ones still in its status.
+func countMovingParcels(ctx context.Context, tx *sql.Tx, shippingOrderID int, previousOrderStatus string) (int, error) {
+	var count int
+	err := tx.QueryRowContext(ctx, QUERY_COUNT_MOVING_PARCELS, shippingOrderID, previousOrderStatus).Scan(&count)
+
+	return count, err
+}
+
+// Locks a shippingOrder in the transaction and checks it is still in the status it was read with.
+func lockOrderStatus(ctx context.Context, tx *sql.Tx, shippingOrderID int, previousOrderStatus string) error {
+	var orderStatus string
+	if err := tx.QueryRowContext(ctx, QUERY_LOCK_SHIPPINGORDER_STATUS, shippingOrderID).Scan(&orderStatus); err != nil {
+		return err
+	}
+	if orderStatus != previousOrderStatus {
+		return ErrShippingOrderChanged
+	}
+
+	return nil
+}
+
+// Status of a locked shippingOrder given its parcels as they are in the transaction.
+func deriveLockedOrderStatus(ctx context.Context, tx *sql.Tx, shippingOrderID int) (string, error) {
+	res, err := tx.QueryContext(ctx, QUERY_GET_PARCEL_STATUSES, shippingOrderID)
+	if err != nil {
+		return "", err
+	}
+	defer res.Close()
+
+	var parcels []*ShippingOrderParcelOut
+	for res.Next() {
+		parcel := &ShippingOrderParcelOut{}
+		if err = res.Scan(&parcel.ParcelStatus); err != nil {
+			return "", err
+		}
+		parcels = append(parcels, parcel)
+	}
+	if err = res.Err(); err != nil {
+		return "", err
+	}
+
+	return deriveOrderStatus(parcels), nil
+}
+
+// Loads the parcels of several shippingOrders with a single query.
+func (r *mariaDBRepository) attachParcels(ctx context.Context, shippingOrders []*ShippingOrderOut) error {
+	if len(shippingOrders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*ShippingOrderOut, len(shippingOrders))
+	args := make([]interface{}, 0, len(shippingOrders))
+	for _, shippingOrder := range shippingOrders {
+		shippingOrder.Parcels = []*ShippingOrderParcelOut{}
+		byID[shippingOrder.ID] = shippingOrder
+		args = append(args, shippingOrder.ID)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(args)), ",")
+	res, err := r.mariadb.QueryContext(ctx, fmt.Sprintf(QUERY_GET_SHIPPINGORDER_PARCELS, placeholders), args...)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the parcels of their shippingOrder.
+	parcels := make(map[int]*ShippingOrderParcelOut)
+	for res.Next() {
+		parcel := &ShippingOrderParcelOut{Items: []*ShippingOrderItemOut{}}
+		var stationID sql.NullInt64
+		var shippingOrderID int
+		err = res.Scan(&parcel.ID, &shippingOrderID, &parcel.Sequence, &parcel.PackageSize, &parcel.QuantityProduct, &parcel.WeightProduct,
+			&parcel.DeclaredValue, &parcel.ParcelStatus, &stationID, &parcel.UpdatedUser, &parcel.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		if stationID.Valid {
+			parcelStationID := int(stationID.Int64)
+			parcel.StationID = &parcelStationID
+		}
+		byID[shippingOrderID].Parcels = append(byID[shippingOrderID].Parcels, parcel)
+		parcels[parcel.ID] = parcel
+	}
+	if err = res.Err(); err != nil {
+		return err
+	}
+
+	// Get the products of those parcels.
+	itemRes, err := r.mariadb.QueryContext(ctx, fmt.Sprintf(QUERY_GET_SHIPPINGORDER_ITEMS, placeholders), args...)
+	if err != nil {
+		return err
+	}
+	defer itemRes.Close()
+
+	for itemRes.Next() {
+		item := &ShippingOrderItemOut{}
+		var parcelID int
+		if err = itemRes.Scan(&parcelID, &item.ProductID, &item.Name, &item.Quantity, &item.UnitValue); err != nil {
+			return err
+		}
+		if parcel, ok := parcels[parcelID]; ok {
+			parcel.Items = append(parcel.Items, item)
+		}
+	}
+
+	return itemRes.Err()
+}
+
+// Loads the parcels of every shippingOrder of a list.
+func (r *mariaDBRepository) attachParcelsToList(ctx context.Context, shippingOrders []ShippingOrderOut) error {
+	pointers := make([]*ShippingOrderOut, 0, len(shippingOrders))
+	for i := range shippingOrders {
+		pointers = append(pointers, &shippingOrders[i])
+	}
+
+	return r.attachParcels(ctx, pointers)
+}
+
+// Updates the notification preferences of a single shippingOrder in the database.
+func (r *mariaDBRepository) UpdateShippingOrderNotifications(ctx context.Context, shippingOrderID int, shippingOrder *ShippingOrder) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_SHIPPINGORDER_NOTIFICATIONS)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one shippingOrder.
+	_, err = stmt.ExecContext(ctx, shippingOrder.OptOutSender, shippingOrder.OptOutRecipient, shippingOrder.Language, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrderID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Assigns the courier of a single shippingOrder in the database.
+func (r *mariaDBRepository) UpdateShippingOrderCourier(ctx context.Context, shippingOrderID int, shippingOrder *ShippingOrder) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_SHIPPINGORDER_COURIER)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one shippingOrder.
+	_, err = stmt.ExecContext(ctx, shippingOrder.CourierID, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrderID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Assigns the courier of a created shippingOrder that still has no courier in the database.
+func (r *mariaDBRepository) DispatchShippingOrderCourier(ctx context.Context, shippingOrderID int, shippingOrder *ShippingOrder) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_DISPATCH_SHIPPINGORDER_COURIER)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one shippingOrder, unless it was assigned or changed meanwhile.
+	res, err := stmt.ExecContext(ctx, shippingOrder.CourierID, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrderID, "creado")
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrShippingOrderChanged
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Gets the oldest created shippingOrders that have no courier yet in the database.
+func (r *mariaDBRepository) GetUndispatchedShippingOrders(ctx context.Context, application string, pickupBefore time.Time, limit int) (*[]ShippingOrderOut, error) {
+	// Initialize variables.
+	var shippingOrders []ShippingOrderOut
+
+	// Get the shippingOrders waiting for a courier.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_UNDISPATCHED_SHIPPINGORDERS, application, "creado", "A", pickupBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'shippingOrders' array.
+	for res.Next() {
+		shippingOrder, err := scanShippingOrder(res)
+		if err != nil {
+			return nil, err
+		}
+		shippingOrders = append(shippingOrders, *shippingOrder)
+	}
+	if err = res.Err(); err != nil {
+		return nil, err
+	}
+
+	// Return all of our shippingOrders.
+	return &shippingOrders, r.attachParcelsToList(ctx, shippingOrders)
+}
+
+// Gets the orders and load in kilograms each courier is carrying in the database.
+// The load of an order is the limit of its package size.
+func (r *mariaDBRepository) GetCourierWorkloads(ctx context.Context) (map[int]*CourierWorkload, error) {
+	// Initialize variables.
+	workloads := make(map[int]*CourierWorkload)
+
+	// Get the workload of every courier with active orders.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_COURIER_WORKLOADS, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'workloads' map.
+	for res.Next() {
+		workload := &CourierWorkload{}
+		if err = res.Scan(&workload.CourierID, &workload.ActiveOrders, &workload.LoadKg); err != nil {
+			return nil, err
+		}
+		workloads[workload.CourierID] = workload
+	}
+
+	// Return all of our workloads.
+	return workloads, res.Err()
+}
+
+// Gets the shippingOrders a courier still has to pick up or drop in the database.
+func (r *mariaDBRepository) GetCourierShippingOrders(ctx context.Context, courierID int) (*[]ShippingOrderOut, error) {
+	// Initialize variables.
+	var shippingOrders []ShippingOrderOut
+
+	// Get the shippingOrders of the courier.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_COURIER_SHIPPINGORDERS, courierID, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'shippingOrders' array.
+	for res.Next() {
+		shippingOrder, err := scanShippingOrder(res)
+		if err != nil {
+			return nil, err
+		}
+		shippingOrders = append(shippingOrders, *shippingOrder)
+	}
+	if err = res.Err(); err != nil {
+		return nil, err
+	}
+
+	// Return all of our shippingOrders.
+	return &shippingOrders, r.attachParcelsToList(ctx, shippingOrders)
+}
+
+// Updates the promised delivery window of a single shippingOrder in the database.
+func (r *mariaDBRepository) UpdateShippingOrderEstimate(ctx context.Context, shippingOrderID int, shippingOrder *ShippingOrder) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_SHIPPINGORDER_ESTIMATE)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
 	// Update one shippingOrder.
-	_, err = stmt.ExecContext(ctx, shippingOrder.OrderStatus, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt, shippingOrderID)
+	_, err = stmt.ExecContext(ctx, shippingOrder.EstimatedDeliveryFrom, shippingOrder.EstimatedDeliveryTo, shippingOrderID)
 	if err != nil {
 		return err
 	}
@@ -158,3 +949,311 @@ func (r *mariaDBRepository) UpdateShippingOrder(ctx context.Context, shippingOrd
 	// Return empty.
 	return nil
 }
+
+// Gets the shippingOrders whose origin or destination is within a radius of a point, closest first.
+// The bounding box of the circle lets the spatial index discard the far away orders.
+func (r *mariaDBRepository) GetShippingOrdersNearby(ctx context.Context, end string, lat float64, lng float64, radiusKm float64, limit int) (*[]ShippingOrderOut, error) {
+	query := QUERY_GET_SHIPPINGORDERS_NEAR_ORIGIN
+	if end == "destination" {
+		query = QUERY_GET_SHIPPINGORDERS_NEAR_DESTINATION
+	}
+
+	// One degree of latitude is about 111.32 km, degrees of longitude shrink towards the poles.
+	deltaLat := radiusKm / 111.32
+	deltaLng := radiusKm / (111.32 * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+	box := boundingBoxWKT(lat-deltaLat, lng-deltaLng, lat+deltaLat, lng+deltaLng)
+
+	return r.getShippingOrders(ctx, query, box, lng, lat, radiusKm*1000, "A", lng, lat, limit)
+}
+
+// Gets the shippingOrders whose origin or destination is inside a bounding box, newest first.
+func (r *mariaDBRepository) GetShippingOrdersInBox(ctx context.Context, end string, minLat float64, minLng float64, maxLat float64, maxLng float64, limit int) (*[]ShippingOrderOut, error) {
+	query := QUERY_GET_SHIPPINGORDERS_IN_BOX_ORIGIN
+	if end == "destination" {
+		query = QUERY_GET_SHIPPINGORDERS_IN_BOX_DESTINATION
+	}
+
+	return r.getShippingOrders(ctx, query, boundingBoxWKT(minLat, minLng, maxLat, maxLng), "A", limit)
+}
+
+// Runs a query that returns the columns of 'QUERY_SHIPPINGORDER_COLUMNS' for several shippingOrders.
+func (r *mariaDBRepository) getShippingOrders(ctx context.Context, query string, args ...interface{}) (*[]ShippingOrderOut, error) {
+	// Initialize variables.
+	shippingOrders := []ShippingOrderOut{}
+
+	res, err := r.mariadb.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'shippingOrders' array.
+	for res.Next() {
+		shippingOrder, err := scanShippingOrder(res)
+		if err != nil {
+			return nil, err
+		}
+		shippingOrders = append(shippingOrders, *shippingOrder)
+	}
+	if err = res.Err(); err != nil {
+		return nil, err
+	}
+
+	// Return all of our shippingOrders.
+	return &shippingOrders, r.attachParcelsToList(ctx, shippingOrders)
+}
+
+// Polygon in WKT of a bounding box, points are written as 'lng lat' like the stored POINTs.
+func boundingBoxWKT(minLat float64, minLng float64, maxLat float64, maxLng float64) string {
+	return fmt.Sprintf("POLYGON((%f %f, %f %f, %f %f, %f %f, %f %f))",
+		minLng, minLat, maxLng, minLat, maxLng, maxLat, minLng, maxLat, minLng, minLat)
+}
+
+// Gets the orders with cash on delivery of a courier, collected between 'from' and 'to' or still to be collected.
+func (r *mariaDBRepository) GetCourierCashOnDelivery(ctx context.Context, courierID int, from time.Time, to time.Time) (*[]CashOnDeliveryOrder, error) {
+	// Initialize variables.
+	cashOnDeliveryOrders := []CashOnDeliveryOrder{}
+
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_COURIER_CASH_ON_DELIVERY, courierID, "A", from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'cashOnDeliveryOrders' array.
+	for res.Next() {
+		cashOnDeliveryOrder := CashOnDeliveryOrder{}
+		var cashCollected sql.NullFloat64
+		var cashCollectedAt sql.NullTime
+		err = res.Scan(&cashOnDeliveryOrder.ShippingOrderID, &cashOnDeliveryOrder.OrderStatus, &cashOnDeliveryOrder.Currency, &cashOnDeliveryOrder.CashOnDelivery, &cashCollected, &cashCollectedAt)
+		if err != nil {
+			return nil, err
+		}
+		if cashCollected.Valid && cashCollectedAt.Valid {
+			cashOnDeliveryOrder.CashCollected = &cashCollected.Float64
+			cashOnDeliveryOrder.CashCollectedAt = &cashCollectedAt.Time
+		}
+		cashOnDeliveryOrders = append(cashOnDeliveryOrders, cashOnDeliveryOrder)
+	}
+
+	// Return all of our orders.
+	return &cashOnDeliveryOrders, res.Err()
+}
+
+// Gets the proof of delivery of a shippingOrder in the database.
+func (r *mariaDBRepository) GetProofOfDelivery(ctx context.Context, shippingOrderID int) (*ProofOfDelivery, error) {
+	// Initialize variable.
+	proofOfDelivery := &ProofOfDelivery{}
+
+	// Get the proof of delivery.
+	// If it's empty, return null.
+	err := r.mariadb.QueryRowContext(ctx, QUERY_GET_PROOF_OF_DELIVERY, shippingOrderID).Scan(&proofOfDelivery.ShippingOrderID, &proofOfDelivery.ReceiverName, &proofOfDelivery.ReceiverDocument,
+		&proofOfDelivery.Lat, &proofOfDelivery.Lng, &proofOfDelivery.SignatureKey, &proofOfDelivery.PhotoKey, &proofOfDelivery.CreatedUser, &proofOfDelivery.CreatedAt)
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return proofOfDelivery, nil
+}
+
+// Gets the failed delivery attempts of a shippingOrder in the database.
+func (r *mariaDBRepository) GetDeliveryAttempts(ctx context.Context, shippingOrderID int) (*[]DeliveryAttemptOut, error) {
+	// Initialize variables.
+	deliveryAttempts := []DeliveryAttemptOut{}
+
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_DELIVERY_ATTEMPTS, shippingOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'deliveryAttempts' array.
+	for res.Next() {
+		deliveryAttempt := DeliveryAttemptOut{}
+		var courierID sql.NullInt64
+		err = res.Scan(&deliveryAttempt.Attempt, &deliveryAttempt.Reason, &deliveryAttempt.Notes, &courierID, &deliveryAttempt.CreatedUser, &deliveryAttempt.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if courierID.Valid {
+			attemptCourierID := int(courierID.Int64)
+			deliveryAttempt.CourierID = &attemptCourierID
+		}
+		deliveryAttempts = append(deliveryAttempts, deliveryAttempt)
+	}
+
+	// Return all of our attempts.
+	return &deliveryAttempts, res.Err()
+}
+
+// Gets the shippingOrders still to pick up whose window starts between 'from' and 'to', optionally in one zone.
+func (r *mariaDBRepository) GetShippingOrdersToPickUp(ctx context.Context, from time.Time, to time.Time, zoneID string) (*[]ShippingOrderOut, error) {
+	return r.getShippingOrders(ctx, QUERY_GET_SHIPPINGORDERS_TO_PICK_UP, from, to, zoneID, zoneID, "creado", "A")
+}
+
+// Gets the shippingOrder an application created with an idempotency key.
+func (r *mariaDBRepository) GetIdempotentShippingOrder(ctx context.Context, application string, idempotencyKey string) (*ShippingOrderOut, error) {
+	// Prepare SQL to get one shippingOrder.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_GET_SHIPPINGORDER_IDEMPOTENCY)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Get one shippingOrder and insert it to the 'shippingOrder' struct.
+	// If it's empty, return null.
+	shippingOrder, err := scanShippingOrder(stmt.QueryRowContext(ctx, application, idempotencyKey, "A"))
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return shippingOrder, r.attachParcels(ctx, []*ShippingOrderOut{shippingOrder})
+}
+
+// Gets a single shippingOrder by its tracking code.
+func (r *mariaDBRepository) GetTrackedShippingOrder(ctx context.Context, trackingCode string) (*ShippingOrderOut, error) {
+	// Prepare SQL to get one shippingOrder.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_GET_SHIPPINGORDER_TRACKING)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Get one shippingOrder and insert it to the 'shippingOrder' struct.
+	// If it's empty, return null.
+	shippingOrder, err := scanShippingOrder(stmt.QueryRowContext(ctx, trackingCode, "A"))
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return shippingOrder, r.attachParcels(ctx, []*ShippingOrderOut{shippingOrder})
+}
+
+// Replaces the delivery preferences of a single shippingOrder in the database together with its event.
+// A pickup point redirects the order, and leaving it out restores the destination the order had before.
+func (r *mariaDBRepository) UpdateDeliveryPreferences(ctx context.Context, preferences *DeliveryPreferences, event *outbox.Event) error {
+	// Begin transaction.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the shippingOrder, it may have left for delivery since it was read.
+	var orderStatus, destinationZoneID string
+	var destinationAddressID int64
+	var redirectedFromAddressID sql.NullInt64
+	var redirectedFromZoneID sql.NullString
+	err = tx.QueryRowContext(ctx, QUERY_LOCK_SHIPPINGORDER_DESTINATION, preferences.ShippingOrderID).
+		Scan(&orderStatus, &destinationAddressID, &destinationZoneID, &redirectedFromAddressID, &redirectedFromZoneID)
+	if err != nil {
+		return err
+	}
+	if !preferenceOrderStatuses[orderStatus] {
+		return ErrPreferencesClosed
+	}
+
+	if pickupPoint := preferences.PickupPoint; pickupPoint != nil {
+		// The first redirection keeps the destination to go back to.
+		if !redirectedFromAddressID.Valid {
+			redirectedFromAddressID = sql.NullInt64{Int64: destinationAddressID, Valid: true}
+			redirectedFromZoneID = sql.NullString{String: destinationZoneID, Valid: true}
+		}
+
+		pickupPointAddressID, err := saveAddress(ctx, tx, pickupPoint.Lat, pickupPoint.Lng, pickupPoint.Address, pickupPoint.Name)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, QUERY_REDIRECT_SHIPPINGORDER, pickupPointAddressID, pickupPoint.ZoneID, pickupPoint.Lng, pickupPoint.Lat, preferences.UpdatedAt, preferences.ShippingOrderID)
+		if err != nil {
+			return err
+		}
+	} else if redirectedFromAddressID.Valid {
+		_, err = tx.ExecContext(ctx, QUERY_RESTORE_SHIPPINGORDER, redirectedFromAddressID.Int64, redirectedFromZoneID.String, preferences.UpdatedAt, preferences.ShippingOrderID)
+		if err != nil {
+			return err
+		}
+		redirectedFromAddressID = sql.NullInt64{}
+		redirectedFromZoneID = sql.NullString{}
+	}
+
+	// The delivery window chosen by the recipient becomes the promise of the order.
+	if preferences.DeliveryWindowFrom != nil {
+		_, err = tx.ExecContext(ctx, QUERY_UPDATE_SHIPPINGORDER_ESTIMATE, preferences.DeliveryWindowFrom, preferences.DeliveryWindowTo, preferences.ShippingOrderID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Store the preferences.
+	var neighbourName, neighbourDocument, neighbourAddress, pickupPointName sql.NullString
+	if preferences.Neighbour != nil {
+		neighbourName = sql.NullString{String: preferences.Neighbour.FullName, Valid: true}
+		neighbourDocument = sql.NullString{String: preferences.Neighbour.Document, Valid: true}
+		neighbourAddress = sql.NullString{String: preferences.Neighbour.Address, Valid: true}
+	}
+	if preferences.PickupPoint != nil {
+		pickupPointName = sql.NullString{String: preferences.PickupPoint.Name, Valid: true}
+	}
+	_, err = tx.ExecContext(ctx, QUERY_SAVE_DELIVERY_PREFERENCES, preferences.ShippingOrderID, preferences.DeliveryWindowFrom, preferences.DeliveryWindowTo,
+		preferences.Instructions, neighbourName, neighbourDocument, neighbourAddress, pickupPointName, redirectedFromAddressID, redirectedFromZoneID, preferences.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// Store the event of the change.
+	if err = r.outboxRepository.SaveEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	// Return empty.
+	return tx.Commit()
+}
+
+// Counts the parcels a station holds in the database.
+func (r *mariaDBRepository) CountStationParcels(ctx context.Context, stationID int) (int, error) {
+	var count int
+	if err := r.mariadb.QueryRowContext(ctx, QUERY_COUNT_STATION_PARCELS, stationID).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Gets the parcels a station holds in the database, the oldest arrivals first.
+func (r *mariaDBRepository) GetStationParcels(ctx context.Context, stationID int) ([]*StationParcelOut, error) {
+	// Initialize variables.
+	stationParcels := []*StationParcelOut{}
+
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_STATION_PARCELS, stationID, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'stationParcels' array.
+	for res.Next() {
+		stationParcel := &StationParcelOut{}
+		err = res.Scan(&stationParcel.ShippingOrderID, &stationParcel.TrackingCode, &stationParcel.ParcelID, &stationParcel.Sequence, &stationParcel.PackageSize,
+			&stationParcel.WeightProduct, &stationParcel.DestinationZoneID, &stationParcel.ServiceLevel, &stationParcel.ArrivedAt)
+		if err != nil {
+			return nil, err
+		}
+		stationParcels = append(stationParcels, stationParcel)
+	}
+
+	// Return all of our parcels.
+	return stationParcels, res.Err()
+}
@@ -0,0 +1,234 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/courier"
+	"delivery-service/internal/utils"
+	"fmt"
+	"time"
+)
+
+// Kinds of stop of a route.
+const (
+	ROUTE_STOP_PICKUP = "pickup"
+	ROUTE_STOP_DROP   = "drop"
+	ROUTE_STOP_RETURN = "return"
+)
+
+// Implementation of 'GetCourierRoute'.
+func (s *shippingOrderService) GetCourierRoute(ctx context.Context, courierID int, application string) (*CourierRouteOut, error) {
+	// Check if courier exists, couriers of other applications do not exist for the caller.
+	searchedCourier, err := s.courierRepository.GetCourier(ctx, courierID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedCourier == nil || searchedCourier.Application != application {
+		return nil, fmt.Errorf("There is no courier with this ID")
+	}
+
+	shippingOrders, err := s.shippingOrderRepository.GetCourierShippingOrders(ctx, courierID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+
+	routeOut := &CourierRouteOut{
+		CourierID: courierID,
+		Stops:     []*RouteStop{},
+	}
+
+	// The route starts where the courier is, if known.
+	position, err := s.locationRepository.GetLocation(ctx, courierID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "location could not be retrieved")
+	}
+	if position == nil && searchedCourier.LastLat != nil && searchedCourier.LastLng != nil {
+		position = &courier.CourierPosition{
+			CourierID: courierID,
+			Lat:       *searchedCourier.LastLat,
+			Lng:       *searchedCourier.LastLng,
+		}
+	}
+	routeOut.Start = position
+
+	// Orders not collected yet need a pickup before their drop.
+	// Orders picked up on a later day are left out of the route, with their drop.
+	var stops []*RouteStop
+	now := time.Now()
+	for _, shippingOrder := range *shippingOrders {
+		if shippingOrder.OrderStatus == "creado" {
+			if pickupScheduledLater(&shippingOrder, now) {
+				continue
+			}
+			stops = append(stops, &RouteStop{
+				ShippingOrderID: shippingOrder.ID,
+				StopType:        ROUTE_STOP_PICKUP,
+				Lat:             *shippingOrder.Origin.LatOrigin,
+				Lng:             *shippingOrder.Origin.LngOrigin,
+				Address:         shippingOrder.Origin.AddressOrigin.Format(),
+				WindowFrom:      shippingOrder.PickupFrom,
+				WindowTo:        shippingOrder.PickupTo,
+			})
+		}
+		// Returned orders are dropped back at their origin.
+		if shippingOrder.OrderStatus == "devolucion" {
+			stops = append(stops, &RouteStop{
+				ShippingOrderID: shippingOrder.ID,
+				StopType:        ROUTE_STOP_RETURN,
+				Lat:             *shippingOrder.Origin.LatOrigin,
+				Lng:             *shippingOrder.Origin.LngOrigin,
+				Address:         shippingOrder.Origin.AddressOrigin.Format(),
+			})
+			continue
+		}
+		stops = append(stops, &RouteStop{
+			ShippingOrderID: shippingOrder.ID,
+			StopType:        ROUTE_STOP_DROP,
+			Lat:             *shippingOrder.Destination.LatDestination,
+			Lng:             *shippingOrder.Destination.LngDestination,
+			Address:         shippingOrder.Destination.AddressDestination.Format(),
+			Preferences:     shippingOrder.Preferences,
+		})
+	}
+
+	if len(stops) == 0 {
+		return routeOut, nil
+	}
+
+	// Build a first route and improve it.
+	route := nearestNeighbourRoute(position, stops)
+	route = twoOptRoute(position, route)
+
+	// Number the stops and add up the distances.
+	var previousLat, previousLng float64
+	hasPrevious := position != nil
+	if hasPrevious {
+		previousLat, previousLng = position.Lat, position.Lng
+	}
+	for i, stop := range route {
+		stop.Sequence = i + 1
+		if hasPrevious {
+			stop.DistanceKm = utils.Haversine(previousLat, previousLng, stop.Lat, stop.Lng)
+		}
+		routeOut.TotalDistanceKm += stop.DistanceKm
+		stop.CumulativeKm = routeOut.TotalDistanceKm
+
+		previousLat, previousLng, hasPrevious = stop.Lat, stop.Lng, true
+	}
+	routeOut.Stops = route
+
+	return routeOut, nil
+}
+
+// Visits the closest stop allowed each time, a drop is allowed once its pickup is visited.
+func nearestNeighbourRoute(start *courier.CourierPosition, stops []*RouteStop) []*RouteStop {
+	pendingPickups := make(map[int]bool)
+	for _, stop := range stops {
+		if stop.StopType == ROUTE_STOP_PICKUP {
+			pendingPickups[stop.ShippingOrderID] = true
+		}
+	}
+
+	visited := make([]bool, len(stops))
+	route := make([]*RouteStop, 0, len(stops))
+
+	var currentLat, currentLng float64
+	hasCurrent := start != nil
+	if hasCurrent {
+		currentLat, currentLng = start.Lat, start.Lng
+	}
+
+	for len(route) < len(stops) {
+		next := -1
+		nextDistance := 0.0
+		for i, stop := range stops {
+			if visited[i] || (stop.StopType == ROUTE_STOP_DROP && pendingPickups[stop.ShippingOrderID]) {
+				continue
+			}
+
+			// Without a start, the route begins at the first allowed stop.
+			distance := 0.0
+			if hasCurrent {
+				distance = utils.Haversine(currentLat, currentLng, stop.Lat, stop.Lng)
+			}
+			if next == -1 || distance < nextDistance {
+				next, nextDistance = i, distance
+			}
+		}
+
+		visited[next] = true
+		route = append(route, stops[next])
+		if stops[next].StopType == ROUTE_STOP_PICKUP {
+			pendingPickups[stops[next].ShippingOrderID] = false
+		}
+		currentLat, currentLng, hasCurrent = stops[next].Lat, stops[next].Lng, true
+	}
+
+	return route
+}
+
+// Reverses segments of the route while it gets shorter and keeps every pickup before its drop.
+func twoOptRoute(start *courier.CourierPosition, route []*RouteStop) []*RouteStop {
+	bestDistance := routeDistance(start, route)
+
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(route)-1; i++ {
+			for j := i + 1; j < len(route); j++ {
+				candidate := make([]*RouteStop, len(route))
+				copy(candidate, route)
+				for left, right := i, j; left < right; left, right = left+1, right-1 {
+					candidate[left], candidate[right] = candidate[right], candidate[left]
+				}
+
+				if !isRouteFeasible(candidate) {
+					continue
+				}
+
+				// Ignore floating point noise to always finish.
+				candidateDistance := routeDistance(start, candidate)
+				if candidateDistance < bestDistance-1e-9 {
+					route, bestDistance, improved = candidate, candidateDistance, true
+				}
+			}
+		}
+	}
+
+	return route
+}
+
+// Checks that no drop comes before the pickup of its order.
+func isRouteFeasible(route []*RouteStop) bool {
+	pickedUp := make(map[int]bool)
+	hasPickup := make(map[int]bool)
+	for _, stop := range route {
+		if stop.StopType == ROUTE_STOP_PICKUP {
+			hasPickup[stop.ShippingOrderID] = true
+		}
+	}
+
+	for _, stop := range route {
+		switch stop.StopType {
+		case ROUTE_STOP_PICKUP:
+			pickedUp[stop.ShippingOrderID] = true
+		case ROUTE_STOP_DROP:
+			if hasPickup[stop.ShippingOrderID] && !pickedUp[stop.ShippingOrderID] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Length in kilometers of the route, from the start if known.
+func routeDistance(start *courier.CourierPosition, route []*RouteStop) float64 {
+	distance := 0.0
+	if start != nil && len(route) > 0 {
+		distance += utils.Haversine(start.Lat, start.Lng, route[0].Lat, route[0].Lng)
+	}
+	for i := 1; i < len(route); i++ {
+		distance += utils.Haversine(route[i-1].Lat, route[i-1].Lng, route[i].Lat, route[i].Lng)
+	}
+
+	return distance
+}
@@ -0,0 +1,66 @@
+package shipping_order
+
+import (
+	"delivery-service/internal/courier"
+	"testing"
+)
+
+// Stop of the tests on the equator, a degree of longitude is about 111 km.
+func stopAt(shippingOrderID int, stopType string, lng float64) *RouteStop {
+	return &RouteStop{ShippingOrderID: shippingOrderID, StopType: stopType, Lng: lng}
+}
+
+// Position in the route of every stop of the given type, by order.
+func stopPositions(route []*RouteStop, stopType string) map[int]int {
+	positions := make(map[int]int)
+	for i, stop := range route {
+		if stop.StopType == stopType {
+			positions[stop.ShippingOrderID] = i
+		}
+	}
+	return positions
+}
+
+func TestTwoOptRouteUntanglesCrossedRoute(t *testing.T) {
+	start := &courier.CourierPosition{}
+	pickup1, drop1 := stopAt(1, ROUTE_STOP_PICKUP, 1), stopAt(1, ROUTE_STOP_DROP, 3)
+	pickup2, drop2 := stopAt(2, ROUTE_STOP_PICKUP, 2), stopAt(2, ROUTE_STOP_DROP, 4)
+
+	route := twoOptRoute(start, []*RouteStop{pickup1, drop1, pickup2, drop2})
+
+	expected := []*RouteStop{pickup1, pickup2, drop1, drop2}
+	for i := range expected {
+		if route[i] != expected[i] {
+			t.Fatalf("stop %d is %v of order %d, expected %v of order %d", i+1, route[i].StopType, route[i].ShippingOrderID, expected[i].StopType, expected[i].ShippingOrderID)
+		}
+	}
+}
+
+func TestTwoOptRouteKeepsPickupBeforeDrop(t *testing.T) {
+	start := &courier.CourierPosition{}
+	pickup1, drop1 := stopAt(1, ROUTE_STOP_PICKUP, 3), stopAt(1, ROUTE_STOP_DROP, 1)
+	pickup2, drop2 := stopAt(2, ROUTE_STOP_PICKUP, 2), stopAt(2, ROUTE_STOP_DROP, 4)
+	crossed := []*RouteStop{pickup1, drop1, pickup2, drop2}
+
+	// Dropping the first order before picking it up would be shorter, the route must not take it.
+	reversed := []*RouteStop{drop1, pickup1, pickup2, drop2}
+	if routeDistance(start, reversed) >= routeDistance(start, crossed) {
+		t.Fatal("the reversed route should be shorter for this test to mean anything")
+	}
+
+	route := twoOptRoute(start, crossed)
+
+	if len(route) != len(crossed) {
+		t.Fatalf("%d stops, expected %d", len(route), len(crossed))
+	}
+	if routeDistance(start, route) > routeDistance(start, crossed) {
+		t.Errorf("route of %.1f km is longer than the one given of %.1f km", routeDistance(start, route), routeDistance(start, crossed))
+	}
+
+	pickups, drops := stopPositions(route, ROUTE_STOP_PICKUP), stopPositions(route, ROUTE_STOP_DROP)
+	for shippingOrderID, pickup := range pickups {
+		if drop, ok := drops[shippingOrderID]; !ok || drop < pickup {
+			t.Errorf("order %d is dropped at stop %d before its pickup at stop %d", shippingOrderID, drop+1, pickup+1)
+		}
+	}
+}
@@ -2,25 +2,126 @@ package shipping_order
 
 import (
 	"context"
+	"delivery-service/internal/blob"
+	"delivery-service/internal/calendar"
+	"delivery-service/internal/contact"
+	"delivery-service/internal/courier"
+	"delivery-service/internal/geocoding"
+	"delivery-service/internal/notification"
+	"delivery-service/internal/outbox"
 	"delivery-service/internal/package_size"
+	"delivery-service/internal/product"
+	"delivery-service/internal/service_level"
+	"delivery-service/internal/station"
 	"delivery-service/internal/utils"
+	"delivery-service/internal/verification"
+	"delivery-service/internal/zone"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"os"
 	"strconv"
 	"time"
 )
 
+// Transition rules, the statuses an order must have before moving to each status.
+// Orders reach 'devolucion' on their own, after their last failed delivery attempt.
+var orderStatusTransitions = map[string]struct {
+	from    []string
+	message string
+}{
+	"recolectado":     {[]string{"creado"}, "to make this change the order status must be created"},
+	"en_estacion":     {[]string{"recolectado"}, "to make this change the status of the order must be collected"},
+	"en_ruta":         {[]string{"en_estacion", "intento_fallido"}, "to make this change the status of the order must be in station or after a failed delivery attempt"},
+	"entregado":       {[]string{"en_ruta"}, "to make this change the status of the order must be en route"},
+	"intento_fallido": {[]string{"en_ruta"}, "to make this change the status of the order must be en route"},
+	"devuelto":        {[]string{"devolucion"}, "to make this change the status of the order must be returning to the sender"},
+}
+
+// Statuses that only the courier assigned to the order can set.
+var courierOrderStatuses = map[string]bool{
+	"recolectado":     true,
+	"en_ruta":         true,
+	"entregado":       true,
+	"intento_fallido": true,
+	"devuelto":        true,
+}
+
+// Statuses of the orders that already left for delivery, they can no longer be cancelled.
+var dispatchedOrderStatuses = map[string]bool{
+	"en_ruta":         true,
+	"entregado":       true,
+	"intento_fallido": true,
+	"devolucion":      true,
+	"devuelto":        true,
+}
+
+// Statuses of the orders whose recipient can still change the delivery preferences.
+var preferenceOrderStatuses = map[string]bool{
+	"creado":      true,
+	"recolectado": true,
+	"en_estacion": true,
+}
+
+// Returned when the order is moved by someone other than its assigned courier.
+var ErrNotAssignedCourier = errors.New("only the courier assigned to the order can make this change")
+
+// Returned when the order or its parcel changed after it was checked, the change must be made again.
+var ErrShippingOrderChanged = errors.New("the order changed while processing the request, please try again")
+
 // Implementation of the repository in this service.
 type shippingOrderService struct {
 	shippingOrderRepository ShippingOrderRepository
 	packageSizeRepository   package_size.PackageSizeRepository
+	productRepository       product.ProductRepository
+	serviceLevelRepository  service_level.ServiceLevelRepository
+	zoneRepository          zone.ZoneRepository
+	geocoder                geocoding.Geocoder
+	contactRepository       contact.ContactRepository
+	courierRepository       courier.CourierRepository
+	locationRepository      courier.LocationRepository
+	notificationService     notification.NotificationService
+	blobStore               blob.Store
+	calendarRepository      calendar.CalendarRepository
+	verificationRepository  verification.VerificationRepository
+	stationRepository       station.StationRepository
+}
+
+// Repositories and services used by the 'ShippingOrder' service besides its own repository.
+type ShippingOrderDependencies struct {
+	PackageSizeRepository  package_size.PackageSizeRepository
+	ProductRepository      product.ProductRepository
+	ServiceLevelRepository service_level.ServiceLevelRepository
+	ZoneRepository         zone.ZoneRepository
+	Geocoder               geocoding.Geocoder
+	ContactRepository      contact.ContactRepository
+	CourierRepository      courier.CourierRepository
+	LocationRepository     courier.LocationRepository
+	NotificationService    notification.NotificationService
+	BlobStore              blob.Store
+	CalendarRepository     calendar.CalendarRepository
+	VerificationRepository verification.VerificationRepository
+	StationRepository      station.StationRepository
 }
 
 // Create a new 'service' or 'use-case' for 'ShippingOrder' entity.
-func NewShippingOrderService(r ShippingOrderRepository, p package_size.PackageSizeRepository) ShippingOrderService {
+func NewShippingOrderService(r ShippingOrderRepository, d ShippingOrderDependencies) ShippingOrderService {
 	return &shippingOrderService{
 		shippingOrderRepository: r,
-		packageSizeRepository:   p,
+		packageSizeRepository:   d.PackageSizeRepository,
+		productRepository:       d.ProductRepository,
+		serviceLevelRepository:  d.ServiceLevelRepository,
+		zoneRepository:          d.ZoneRepository,
+		geocoder:                d.Geocoder,
+		contactRepository:       d.ContactRepository,
+		courierRepository:       d.CourierRepository,
+		locationRepository:      d.LocationRepository,
+		notificationService:     d.NotificationService,
+		blobStore:               d.BlobStore,
+		calendarRepository:      d.CalendarRepository,
+		verificationRepository:  d.VerificationRepository,
+		stationRepository:       d.StationRepository,
 	}
 }
 
@@ -36,6 +137,32 @@ func (s *shippingOrderService) GetSenderShippingOrder(ctx context.Context, shipp
 
 // Implementation of 'CreateShippingOrder'.
 func (s *shippingOrderService) CreateShippingOrder(ctx context.Context, shippingOrderInsert *ShippingOrderInsert) (*ShippingOrderOut, error) {
+	// A request repeated with the same idempotency key gets the order it created the first time.
+	if shippingOrderInsert.IdempotencyKey != "" {
+		createdShippingOrder, err := s.shippingOrderRepository.GetIdempotentShippingOrder(ctx, shippingOrderInsert.Application, shippingOrderInsert.IdempotencyKey)
+		if err != nil {
+			return nil, utils.FailOnError(err, "information could not be retrieved")
+		}
+		if createdShippingOrder != nil {
+			return createdShippingOrder, nil
+		}
+	}
+
+	// Copy the saved contacts and addresses the order refers to.
+	if err := s.snapshotContacts(ctx, shippingOrderInsert); err != nil {
+		return nil, err
+	}
+
+	// Clean both addresses and fill what the client left out.
+	origin := shippingOrderInsert.Origin
+	if err := s.locate(ctx, "origin", &origin.AddressOrigin, &origin.LatOrigin, &origin.LngOrigin); err != nil {
+		return nil, err
+	}
+	destination := shippingOrderInsert.Destination
+	if err := s.locate(ctx, "destination", &destination.AddressDestination, &destination.LatDestination, &destination.LngDestination); err != nil {
+		return nil, err
+	}
+
 	// Create a new shippingOrder struct.
 	shippingOrder := &ShippingOrder{}
 
@@ -50,63 +177,124 @@ func (s *shippingOrderService) CreateShippingOrder(ctx context.Context, shipping
 	shippingOrder.PhoneRecipient = shippingOrderInsert.Recipient.PhoneRecipient
 	shippingOrder.EmailRecipient = shippingOrderInsert.Recipient.EmailRecipient
 
-	shippingOrder.LatOrigin = shippingOrderInsert.Origin.LatOrigin
-	shippingOrder.LngOrigin = shippingOrderInsert.Origin.LngOrigin
-	shippingOrder.AddressOrigin = shippingOrderInsert.Origin.AddressOrigin
-	shippingOrder.CountryOrigin = shippingOrderInsert.Origin.CountryOrigin
-	shippingOrder.ZipcodeOrigin = shippingOrderInsert.Origin.ZipcodeOrigin
+	shippingOrder.LatOrigin = *shippingOrderInsert.Origin.LatOrigin
+	shippingOrder.LngOrigin = *shippingOrderInsert.Origin.LngOrigin
+	shippingOrder.AddressOrigin = shippingOrderInsert.Origin.AddressOrigin.Street
+	shippingOrder.NumberOrigin = shippingOrderInsert.Origin.AddressOrigin.Number
+	shippingOrder.DistrictOrigin = shippingOrderInsert.Origin.AddressOrigin.District
+	shippingOrder.CityOrigin = shippingOrderInsert.Origin.AddressOrigin.City
+	shippingOrder.RegionOrigin = shippingOrderInsert.Origin.AddressOrigin.Region
+	shippingOrder.CountryOrigin = shippingOrderInsert.Origin.AddressOrigin.CountryCode
+	shippingOrder.ZipcodeOrigin = shippingOrderInsert.Origin.AddressOrigin.PostalCode
 	shippingOrder.ReferenceOrigin = shippingOrderInsert.Origin.ReferenceOrigin
 
-	shippingOrder.LatDestination = shippingOrderInsert.Destination.LatDestination
-	shippingOrder.LngDestination = shippingOrderInsert.Destination.LngDestination
-	shippingOrder.AddressDestination = shippingOrderInsert.Destination.AddressDestination
-	shippingOrder.CountryDestination = shippingOrderInsert.Destination.CountryDestination
-	shippingOrder.ZipcodeDestination = shippingOrderInsert.Destination.ZipcodeDestination
+	shippingOrder.LatDestination = *shippingOrderInsert.Destination.LatDestination
+	shippingOrder.LngDestination = *shippingOrderInsert.Destination.LngDestination
+	shippingOrder.AddressDestination = shippingOrderInsert.Destination.AddressDestination.Street
+	shippingOrder.NumberDestination = shippingOrderInsert.Destination.AddressDestination.Number
+	shippingOrder.DistrictDestination = shippingOrderInsert.Destination.AddressDestination.District
+	shippingOrder.CityDestination = shippingOrderInsert.Destination.AddressDestination.City
+	shippingOrder.RegionDestination = shippingOrderInsert.Destination.AddressDestination.Region
+	shippingOrder.CountryDestination = shippingOrderInsert.Destination.AddressDestination.CountryCode
+	shippingOrder.ZipcodeDestination = shippingOrderInsert.Destination.AddressDestination.PostalCode
 	shippingOrder.ReferenceDestination = shippingOrderInsert.Destination.ReferenceDestination
 
-	shippingOrder.PackageSize = shippingOrderInsert.Package.PackageSize
-	shippingOrder.QuantityProduct = shippingOrderInsert.Package.QuantityProduct
-	shippingOrder.WeightProduct = shippingOrderInsert.Package.WeightProduct
-
 	shippingOrder.OrderStatus = "creado"
+	shippingOrder.Language = os.Getenv("NOTIFICATION_DEFAULT_LANGUAGE")
+	if shippingOrderInsert.Notifications != nil {
+		shippingOrder.OptOutSender = shippingOrderInsert.Notifications.OptOutSender
+		shippingOrder.OptOutRecipient = shippingOrderInsert.Notifications.OptOutRecipient
+		if shippingOrderInsert.Notifications.Language != "" {
+			shippingOrder.Language = shippingOrderInsert.Notifications.Language
+		}
+	}
+	if shippingOrder.Language == "" {
+		shippingOrder.Language = "es"
+	}
+	shippingOrder.Application = shippingOrderInsert.Application
+	if shippingOrderInsert.IdempotencyKey != "" {
+		shippingOrder.IdempotencyKey = &shippingOrderInsert.IdempotencyKey
+	}
 	shippingOrder.CreatedUser = shippingOrderInsert.CreatedUser
 	shippingOrder.CreatedAt = time.Now()
 	shippingOrder.Status = "A"
 
-	//valid package size
-	maxSize, _ := strconv.Atoi(os.Getenv("MAX_SIZE"))
-
-	if shippingOrder.WeightProduct > maxSize {
-		return nil, fmt.Errorf("orders greater than 25KG must contact the company to make a special agreement")
+	// The recipient follows the order with its tracking code.
+	trackingCode, err := newTrackingCode()
+	if err != nil {
+		return nil, utils.FailOnError(err, "the tracking code could not be generated")
 	}
+	shippingOrder.TrackingCode = trackingCode
 
-	packageSize, err := s.packageSizeRepository.GetPackageSize(ctx, shippingOrder.WeightProduct)
+	// Every parcel is checked on its own, the order keeps their totals.
+	parcels, packageSummary, err := s.buildParcels(ctx, shippingOrderInsert, shippingOrder.CreatedAt)
 	if err != nil {
-		return nil, utils.FailOnError(err, "packet size information could not be retrieved")
+		return nil, err
 	}
-
-	sizeNemo := ""
-	for _, s := range *packageSize {
-
-		sizeNemo = s.Nemo
-		break
+	shippingOrder.Parcels = parcels
+	shippingOrder.PackageSize = packageSummary.PackageSize
+	shippingOrder.QuantityProduct = packageSummary.QuantityProduct
+	shippingOrder.WeightProduct = packageSummary.WeightProduct
+	shippingOrder.DeclaredValue = packageSummary.DeclaredValue
+	shippingOrder.Currency = packageSummary.Currency
+
+	// Only declared goods can be insured.
+	if shippingOrderInsert.Insurance {
+		if shippingOrder.DeclaredValue == 0 {
+			return nil, fmt.Errorf("the order must declare the value of its packages to be insured")
+		}
+		shippingOrder.Insured = true
+		shippingOrder.InsurancePremium = insurancePremium(shippingOrder.DeclaredValue)
 	}
+	shippingOrder.CashOnDelivery = math.Round(shippingOrderInsert.CashOnDelivery*100) / 100
 
-	if shippingOrder.PackageSize != sizeNemo {
-		return nil, fmt.Errorf("the type of the package has no relation to size")
+	// Both ends of the order must be inside a service zone of their country.
+	shippingOrder.OriginZoneID, err = s.resolveZone(ctx, "origin", shippingOrder.CountryOrigin, shippingOrder.LatOrigin, shippingOrder.LngOrigin)
+	if err != nil {
+		return nil, err
+	}
+	shippingOrder.DestinationZoneID, err = s.resolveZone(ctx, "destination", shippingOrder.CountryDestination, shippingOrder.LatDestination, shippingOrder.LngDestination)
+	if err != nil {
+		return nil, err
 	}
 
-	// Pass to the repository layer.
-	result, err := s.shippingOrderRepository.CreateShippingOrder(ctx, shippingOrder)
-
+	// Orders are picked up right away unless the sender scheduled a window, which starts their delivery.
+	shippingOrder.PickupFrom, shippingOrder.PickupTo, err = s.schedulePickup(ctx, shippingOrderInsert.Pickup, shippingOrder.CountryOrigin, shippingOrder.CreatedAt)
 	if err != nil {
-		return nil, utils.FailOnError(err, "problems creating the record")
+		return nil, err
+	}
+	handoverAt := shippingOrder.CreatedAt
+	if shippingOrder.PickupFrom != nil {
+		handoverAt = *shippingOrder.PickupFrom
 	}
 
-	insertedID, err := result.LastInsertId()
+	// Orders without a service level are standard.
+	shippingOrder.ServiceLevel = shippingOrderInsert.ServiceLevel
+	if shippingOrder.ServiceLevel == "" {
+		shippingOrder.ServiceLevel = service_level.SERVICE_LEVEL_STANDARD
+	}
+	serviceLevel, err := s.serviceLevelRepository.GetServiceLevel(ctx, shippingOrder.ServiceLevel)
 	if err != nil {
-		return nil, utils.FailOnError(err, "it is not possible to retrieve the id from the record")
+		return nil, utils.FailOnError(err, "service level information could not be retrieved")
+	}
+	if serviceLevel == nil {
+		return nil, fmt.Errorf("the service level is not available")
+	}
+	originLocation, err := s.countryLocation(ctx, shippingOrder.CountryOrigin)
+	if err != nil {
+		return nil, err
 	}
+	for _, parcel := range parcels {
+		if err = validateServiceLevel(handoverAt, originLocation, serviceLevel, shippingOrderInsert.Origin, shippingOrderInsert.Destination, parcel.PackageSize); err != nil {
+			return nil, err
+		}
+	}
+	shippingOrder.Price = quoteShippingOrder(serviceLevel, shippingOrderInsert.Origin, shippingOrderInsert.Destination, shippingOrder.WeightProduct)
+
+	// Promise a delivery window.
+	estimatedFrom, estimatedTo := estimateDelivery(handoverAt, shippingOrder.OrderStatus, serviceLevel, shippingOrderInsert.Origin, shippingOrderInsert.Destination, shippingOrder.PackageSize, nil)
+	shippingOrder.EstimatedDeliveryFrom = &estimatedFrom
+	shippingOrder.EstimatedDeliveryTo = &estimatedTo
 
 	shippingOrderSenderOut := &ShippingOrderSender{
 		IdSender:       shippingOrder.IdSender,
@@ -123,20 +311,16 @@ func (s *shippingOrderService) CreateShippingOrder(ctx context.Context, shipping
 	}
 
 	shippingOrderOriginOut := &ShippingOrderOrigin{
-		LatOrigin:       shippingOrder.LatOrigin,
-		LngOrigin:       shippingOrder.LngOrigin,
-		AddressOrigin:   shippingOrder.AddressOrigin,
-		CountryOrigin:   shippingOrder.CountryOrigin,
-		ZipcodeOrigin:   shippingOrder.ZipcodeOrigin,
+		LatOrigin:       &shippingOrder.LatOrigin,
+		LngOrigin:       &shippingOrder.LngOrigin,
+		AddressOrigin:   shippingOrderInsert.Origin.AddressOrigin,
 		ReferenceOrigin: shippingOrder.ReferenceOrigin,
 	}
 
 	shippingOrderDestinationOut := &ShippingOrderDestination{
-		LatDestination:       shippingOrder.LatDestination,
-		LngDestination:       shippingOrder.LngDestination,
-		AddressDestination:   shippingOrder.AddressDestination,
-		CountryDestination:   shippingOrder.CountryDestination,
-		ZipcodeDestination:   shippingOrder.ZipcodeDestination,
+		LatDestination:       &shippingOrder.LatDestination,
+		LngDestination:       &shippingOrder.LngDestination,
+		AddressDestination:   shippingOrderInsert.Destination.AddressDestination,
 		ReferenceDestination: shippingOrder.ReferenceDestination,
 	}
 
@@ -144,21 +328,105 @@ func (s *shippingOrderService) CreateShippingOrder(ctx context.Context, shipping
 		PackageSize:     shippingOrder.PackageSize,
 		QuantityProduct: shippingOrder.QuantityProduct,
 		WeightProduct:   shippingOrder.WeightProduct,
+		DeclaredValue:   shippingOrder.DeclaredValue,
+		Currency:        shippingOrder.Currency,
+	}
+
+	shippingOrderParcelsOut := make([]*ShippingOrderPackage, 0, len(parcels))
+	for _, parcel := range parcels {
+		shippingOrderParcelsOut = append(shippingOrderParcelsOut, &ShippingOrderPackage{
+			PackageSize:     parcel.PackageSize,
+			QuantityProduct: parcel.QuantityProduct,
+			WeightProduct:   parcel.WeightProduct,
+			DeclaredValue:   parcel.DeclaredValue,
+			Currency:        shippingOrder.Currency,
+		})
+	}
+
+	shippingOrderNotificationsOut := &ShippingOrderNotifications{
+		OptOutSender:    shippingOrder.OptOutSender,
+		OptOutRecipient: shippingOrder.OptOutRecipient,
+		Language:        shippingOrder.Language,
+	}
+
+	// Prepare the event of the new order.
+	event, err := newShippingOrderEvent(EVENT_ORDER_CREATED, 0, shippingOrder.Application, &OrderCreated{
+		Sender:           shippingOrderSenderOut,
+		Recipient:        shippingOrderRecipientOut,
+		Origin:           shippingOrderOriginOut,
+		Destination:      shippingOrderDestinationOut,
+		Package:          shippingOrderPackageOut,
+		Parcels:          shippingOrderParcelsOut,
+		InsurancePremium: shippingOrder.InsurancePremium,
+		CashOnDelivery:   shippingOrder.CashOnDelivery,
+		TrackingCode:     shippingOrder.TrackingCode,
+		OrderStatus:      shippingOrder.OrderStatus,
+		CreatedUser:      shippingOrder.CreatedUser,
+		CreatedAt:        shippingOrder.CreatedAt,
+	})
+	if err != nil {
+		return nil, utils.FailOnError(err, "the event of the record could not be prepared")
+	}
+
+	// Pass to the repository layer.
+	result, err := s.shippingOrderRepository.CreateShippingOrder(ctx, shippingOrder, event)
+
+	if err != nil {
+		return nil, utils.FailOnError(err, "problems creating the record")
+	}
+
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, utils.FailOnError(err, "it is not possible to retrieve the id from the record")
+	}
+
+	// The repository numbered the parcels.
+	parcelsOut := make([]*ShippingOrderParcelOut, 0, len(parcels))
+	for _, parcel := range parcels {
+		parcelsOut = append(parcelsOut, &ShippingOrderParcelOut{
+			ID:              parcel.ID,
+			Sequence:        parcel.Sequence,
+			PackageSize:     parcel.PackageSize,
+			QuantityProduct: parcel.QuantityProduct,
+			WeightProduct:   parcel.WeightProduct,
+			DeclaredValue:   parcel.DeclaredValue,
+			Items:           parcel.Items,
+			ParcelStatus:    parcel.ParcelStatus,
+			UpdatedUser:     parcel.UpdatedUser,
+			UpdatedAt:       parcel.UpdatedAt,
+		})
 	}
 
 	ShippingOrderOut := &ShippingOrderOut{
-		ID:          int(insertedID),
-		Sender:      shippingOrderSenderOut,
-		Recipient:   shippingOrderRecipientOut,
-		Origin:      shippingOrderOriginOut,
-		Destination: shippingOrderDestinationOut,
-		Package:     shippingOrderPackageOut,
-		OrderStatus: shippingOrder.OrderStatus,
-		CreatedUser: shippingOrder.CreatedUser,
-		CreatedAt:   shippingOrder.CreatedAt,
-		UpdatedUser: shippingOrder.UpdatedUser,
-		UpdatedAt:   shippingOrder.UpdatedAt,
-		Status:      shippingOrder.Status,
+		ID:                    int(insertedID),
+		Sender:                shippingOrderSenderOut,
+		Recipient:             shippingOrderRecipientOut,
+		Origin:                shippingOrderOriginOut,
+		Destination:           shippingOrderDestinationOut,
+		Package:               shippingOrderPackageOut,
+		Parcels:               parcelsOut,
+		Notifications:         shippingOrderNotificationsOut,
+		OriginZoneID:          shippingOrder.OriginZoneID,
+		DestinationZoneID:     shippingOrder.DestinationZoneID,
+		ServiceLevel:          shippingOrder.ServiceLevel,
+		Price:                 shippingOrder.Price,
+		DeclaredValue:         shippingOrder.DeclaredValue,
+		Currency:              shippingOrder.Currency,
+		Insured:               shippingOrder.Insured,
+		InsurancePremium:      shippingOrder.InsurancePremium,
+		CashOnDelivery:        shippingOrder.CashOnDelivery,
+		PickupFrom:            shippingOrder.PickupFrom,
+		PickupTo:              shippingOrder.PickupTo,
+		TrackingCode:          shippingOrder.TrackingCode,
+		OrderStatus:           shippingOrder.OrderStatus,
+		EstimatedDeliveryFrom: shippingOrder.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   shippingOrder.EstimatedDeliveryTo,
+		Application:           shippingOrder.Application,
+		CreatedUser:           shippingOrder.CreatedUser,
+		CreatedAt:             shippingOrder.CreatedAt,
+		UpdatedUser:           shippingOrder.UpdatedUser,
+		UpdatedAt:             shippingOrder.UpdatedAt,
+		Status:                shippingOrder.Status,
 	}
 	return ShippingOrderOut, err
 }
@@ -178,20 +446,12 @@ func (s *shippingOrderService) UpdateShippingOrder(ctx context.Context, shipping
 		return nil, fmt.Errorf("There is no shippingOrder with this ID")
 	}
 
-	if shippingOrderUpdate.OrderStatus == "recolectado" && searchedShippingOrder.OrderStatus != "creado" {
-		return nil, fmt.Errorf("to make this change the order status must be created")
-	}
-
-	if shippingOrderUpdate.OrderStatus == "en_estacion" && searchedShippingOrder.OrderStatus != "recolectado" {
-		return nil, fmt.Errorf("to make this change the status of the order must be collected")
-	}
-
-	if shippingOrderUpdate.OrderStatus == "en_ruta" && searchedShippingOrder.OrderStatus != "en_estacion" {
-		return nil, fmt.Errorf("to make this change the status of the order must be in station")
+	if err = validateStatusTransition(searchedShippingOrder.OrderStatus, shippingOrderUpdate.OrderStatus); err != nil {
+		return nil, err
 	}
 
-	if shippingOrderUpdate.OrderStatus == "entregado" && searchedShippingOrder.OrderStatus != "en_ruta" {
-		return nil, fmt.Errorf("to make this change the status of the order must be en route")
+	if err = s.checkAssignedCourier(ctx, searchedShippingOrder, shippingOrderUpdate.OrderStatus, shippingOrderUpdate.UserID); err != nil {
+		return nil, err
 	}
 
 	// Set value for 'Modified' attribute.
@@ -199,13 +459,71 @@ func (s *shippingOrderService) UpdateShippingOrder(ctx context.Context, shipping
 	shippingOrder.UpdatedUser = shippingOrderUpdate.UpdatedUser
 	shippingOrder.UpdatedAt = time.Now()
 
+	// Parcels arriving at a station must fit in it.
+	if shippingOrder.OrderStatus == "en_estacion" {
+		searchedStation, room, err := s.stationRoom(ctx, shippingOrderUpdate.StationID)
+		if err != nil {
+			return nil, err
+		}
+		if parcelsInStatus(searchedShippingOrder) > room {
+			return nil, stationFullError(searchedStation, room)
+		}
+		shippingOrder.StationID = &searchedStation.ID
+	}
+
+	if err = collectCash(searchedShippingOrder, shippingOrder, shippingOrderUpdate.CashCollected); err != nil {
+		return nil, err
+	}
+
+	// A failed attempt is recorded, the last one allowed returns the order to its sender.
+	var attemptReason string
+	if shippingOrder.OrderStatus == "intento_fallido" {
+		attemptReason = shippingOrderUpdate.AttemptReason
+		failDeliveryAttempt(searchedShippingOrder, shippingOrder, attemptReason, shippingOrderUpdate.AttemptNotes)
+	}
+
+	// A delivered order keeps who received it and where.
+	if shippingOrder.OrderStatus == "entregado" {
+		shippingOrder.ProofOfDelivery, err = s.storeProofOfDelivery(ctx, shippingOrderID, shippingOrderUpdate.ProofOfDelivery, shippingOrder.UpdatedUser, shippingOrder.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Prepare the event of the change.
+	event, err := newShippingOrderEvent(EVENT_ORDER_STATUS_CHANGED, shippingOrderID, searchedShippingOrder.Application, &OrderStatusChanged{
+		ShippingOrderID:     shippingOrderID,
+		PreviousOrderStatus: searchedShippingOrder.OrderStatus,
+		OrderStatus:         shippingOrder.OrderStatus,
+		StationID:           shippingOrder.StationID,
+		CashCollected:       shippingOrder.CashCollected,
+		AttemptReason:       attemptReason,
+		DeliveryAttempts:    shippingOrder.DeliveryAttempts,
+		UpdatedUser:         shippingOrder.UpdatedUser,
+		UpdatedAt:           shippingOrder.UpdatedAt,
+	})
+	if err != nil {
+		s.removeProofOfDelivery(ctx, shippingOrder.ProofOfDelivery)
+		return nil, utils.FailOnError(err, "the event of the record could not be prepared")
+	}
+
 	// Pass to the repository layer.
-	err = s.shippingOrderRepository.UpdateShippingOrder(ctx, shippingOrderID, shippingOrder)
+	err = s.shippingOrderRepository.UpdateShippingOrder(ctx, shippingOrderID, searchedShippingOrder.OrderStatus, shippingOrder, event)
 
 	if err != nil {
+		s.removeProofOfDelivery(ctx, shippingOrder.ProofOfDelivery)
+		if errors.Is(err, ErrShippingOrderChanged) || errors.Is(err, ErrStationFull) || errors.Is(err, ErrStationUnavailable) {
+			return nil, err
+		}
 		return nil, utils.FailOnError(err, "could not update record")
 	}
 
+	if err = s.refreshDeliveryEstimate(ctx, searchedShippingOrder, shippingOrder.OrderStatus); err != nil {
+		log.Printf("Oops... Delivery estimate of ShippingOrder %d could not be updated! Reason: %v", shippingOrderID, err)
+	}
+
+	s.notifyStatusChange(searchedShippingOrder, shippingOrder.OrderStatus)
+
 	return s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
 }
 
@@ -224,8 +542,8 @@ func (s *shippingOrderService) CancelShippingOrder(ctx context.Context, shipping
 		return fmt.Errorf("There is no shippingOrder with this ID")
 	}
 
-	if searchedShippingOrder.OrderStatus == "en_ruta" || searchedShippingOrder.OrderStatus == "entregado" {
-		return fmt.Errorf("the order must not have the status of en route or delivered")
+	if dispatchedOrderStatuses[searchedShippingOrder.OrderStatus] {
+		return fmt.Errorf("the order must not have the status of en route, delivered or returned")
 	}
 
 	//Refund
@@ -246,12 +564,412 @@ func (s *shippingOrderService) CancelShippingOrder(ctx context.Context, shipping
 	shippingOrder.UpdatedUser = shippingOrderCancel.UpdatedUser
 	shippingOrder.UpdatedAt = time.Now()
 
+	// Prepare the event of the cancellation.
+	event, err := newShippingOrderEvent(EVENT_ORDER_CANCELLED, shippingOrderID, searchedShippingOrder.Application, &OrderCancelled{
+		ShippingOrderID:     shippingOrderID,
+		PreviousOrderStatus: searchedShippingOrder.OrderStatus,
+		Refund:              shippingOrderCancel.Refund,
+		UpdatedUser:         shippingOrder.UpdatedUser,
+		UpdatedAt:           shippingOrder.UpdatedAt,
+	})
+	if err != nil {
+		return utils.FailOnError(err, "the event of the record could not be prepared")
+	}
+
 	// Pass to the repository layer.
-	err = s.shippingOrderRepository.UpdateShippingOrder(ctx, shippingOrderID, shippingOrder)
+	err = s.shippingOrderRepository.UpdateShippingOrder(ctx, shippingOrderID, searchedShippingOrder.OrderStatus, shippingOrder, event)
 
 	if err != nil {
+		if errors.Is(err, ErrShippingOrderChanged) {
+			return err
+		}
 		return utils.FailOnError(err, "could not update record")
 	}
 
+	s.notifyStatusChange(searchedShippingOrder, shippingOrder.OrderStatus)
+
 	return nil
 }
+
+// Implementation of 'UpdateShippingOrdersStatus'.
+func (s *shippingOrderService) UpdateShippingOrdersStatus(ctx context.Context, shippingOrderStatusBatch *ShippingOrderStatusBatch) (*ShippingOrderStatusBatchOut, error) {
+	// Initialize variables.
+	batchOut := &ShippingOrderStatusBatchOut{}
+	results := make(map[int]*ShippingOrderStatusResult)
+	searchedShippingOrders := make(map[int]*ShippingOrderOut)
+	var validIDs []int
+
+	// Orders arriving at a station take its room in turn.
+	var searchedStation *station.StationOut
+	var room int
+	if shippingOrderStatusBatch.OrderStatus == "en_estacion" {
+		var err error
+		if searchedStation, room, err = s.stationRoom(ctx, shippingOrderStatusBatch.StationID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check every order against the transition rules.
+	for _, shippingOrderID := range shippingOrderStatusBatch.ShippingOrderIDs {
+		if _, ok := results[shippingOrderID]; ok {
+			continue
+		}
+
+		result := &ShippingOrderStatusResult{ShippingOrderID: shippingOrderID}
+		results[shippingOrderID] = result
+		batchOut.Results = append(batchOut.Results, result)
+
+		searchedShippingOrder, err := s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+		if err != nil {
+			return nil, utils.FailOnError(err, "information could not be retrieved")
+		}
+		if searchedShippingOrder == nil {
+			result.Message = "There is no shippingOrder with this ID"
+			continue
+		}
+
+		if err = validateStatusTransition(searchedShippingOrder.OrderStatus, shippingOrderStatusBatch.OrderStatus); err != nil {
+			result.Message = err.Error()
+			continue
+		}
+
+		err = s.checkAssignedCourier(ctx, searchedShippingOrder, shippingOrderStatusBatch.OrderStatus, shippingOrderStatusBatch.UserID)
+		if err != nil && !errors.Is(err, ErrNotAssignedCourier) {
+			return nil, err
+		}
+		if err != nil {
+			result.Message = err.Error()
+			continue
+		}
+
+		// Each order is delivered on its own, with its proof of delivery and its cash.
+		if shippingOrderStatusBatch.OrderStatus == "entregado" {
+			result.Message = "orders must be delivered one at a time with their proof of delivery"
+			continue
+		}
+
+		if searchedStation != nil {
+			incoming := parcelsInStatus(searchedShippingOrder)
+			if incoming > room {
+				result.Message = stationFullError(searchedStation, room).Error()
+				continue
+			}
+			room -= incoming
+		}
+
+		validIDs = append(validIDs, shippingOrderID)
+		searchedShippingOrders[shippingOrderID] = searchedShippingOrder
+	}
+
+	// In atomic mode a single rejected order stops the whole batch.
+	atomic := shippingOrderStatusBatch.Mode == "atomic"
+	if atomic && len(validIDs) != len(batchOut.Results) {
+		return closeStatusBatch(batchOut, false), nil
+	}
+
+	if len(validIDs) == 0 {
+		return closeStatusBatch(batchOut, true), nil
+	}
+
+	// Set value for 'Modified' attribute.
+	shippingOrder := &ShippingOrder{
+		OrderStatus: shippingOrderStatusBatch.OrderStatus,
+		UpdatedUser: shippingOrderStatusBatch.UpdatedUser,
+		UpdatedAt:   time.Now(),
+	}
+	if searchedStation != nil {
+		shippingOrder.StationID = &searchedStation.ID
+	}
+
+	// Prepare the event of each change, orders may come from different statuses.
+	previousOrderStatuses := make([]string, 0, len(validIDs))
+	events := make([]*outbox.Event, 0, len(validIDs))
+	for _, shippingOrderID := range validIDs {
+		previousOrderStatus := searchedShippingOrders[shippingOrderID].OrderStatus
+		previousOrderStatuses = append(previousOrderStatuses, previousOrderStatus)
+
+		event, err := newShippingOrderEvent(EVENT_ORDER_STATUS_CHANGED, shippingOrderID, searchedShippingOrders[shippingOrderID].Application, &OrderStatusChanged{
+			ShippingOrderID:     shippingOrderID,
+			PreviousOrderStatus: previousOrderStatus,
+			OrderStatus:         shippingOrder.OrderStatus,
+			StationID:           shippingOrder.StationID,
+			UpdatedUser:         shippingOrder.UpdatedUser,
+			UpdatedAt:           shippingOrder.UpdatedAt,
+		})
+		if err != nil {
+			return nil, utils.FailOnError(err, "the event of the record could not be prepared")
+		}
+		events = append(events, event)
+	}
+
+	// Pass to the repository layer, orders changed meanwhile or without room in the station are reported back.
+	rejected, err := s.shippingOrderRepository.UpdateShippingOrdersStatus(ctx, validIDs, previousOrderStatuses, shippingOrder, events, atomic)
+	if err != nil && errors.Is(err, ErrStationUnavailable) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not update records")
+	}
+
+	for shippingOrderID, reason := range rejected {
+		results[shippingOrderID].Message = reason.Error()
+		if errors.Is(reason, ErrShippingOrderChanged) {
+			results[shippingOrderID].Message = "the status of the order changed while processing the batch"
+		}
+	}
+
+	if atomic && len(rejected) > 0 {
+		return closeStatusBatch(batchOut, false), nil
+	}
+
+	for _, shippingOrderID := range validIDs {
+		if rejected[shippingOrderID] == nil {
+			results[shippingOrderID].Success = true
+			if err = s.refreshDeliveryEstimate(ctx, searchedShippingOrders[shippingOrderID], shippingOrder.OrderStatus); err != nil {
+				log.Printf("Oops... Delivery estimate of ShippingOrder %d could not be updated! Reason: %v", shippingOrderID, err)
+			}
+			s.notifyStatusChange(searchedShippingOrders[shippingOrderID], shippingOrder.OrderStatus)
+		}
+	}
+
+	return closeStatusBatch(batchOut, true), nil
+}
+
+// Implementation of 'UpdateShippingOrderNotifications'.
+func (s *shippingOrderService) UpdateShippingOrderNotifications(ctx context.Context, shippingOrderID int, shippingOrderNotificationsUpdate *ShippingOrderNotificationsUpdate) (*ShippingOrderOut, error) {
+	// Check if shippingOrder exists.
+	searchedShippingOrder, err := s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil {
+		return nil, fmt.Errorf("There is no shippingOrder with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	shippingOrder := &ShippingOrder{
+		OptOutSender:    shippingOrderNotificationsUpdate.OptOutSender,
+		OptOutRecipient: shippingOrderNotificationsUpdate.OptOutRecipient,
+		Language:        shippingOrderNotificationsUpdate.Language,
+		UpdatedUser:     shippingOrderNotificationsUpdate.UpdatedUser,
+		UpdatedAt:       time.Now(),
+	}
+	if shippingOrder.Language == "" {
+		shippingOrder.Language = searchedShippingOrder.Notifications.Language
+	}
+
+	// Pass to the repository layer.
+	if err = s.shippingOrderRepository.UpdateShippingOrderNotifications(ctx, shippingOrderID, shippingOrder); err != nil {
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	return s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+}
+
+// Implementation of 'AssignShippingOrderCourier'.
+func (s *shippingOrderService) AssignShippingOrderCourier(ctx context.Context, shippingOrderID int, shippingOrderCourierAssign *ShippingOrderCourierAssign) (*ShippingOrderOut, error) {
+	// Check if shippingOrder exists.
+	searchedShippingOrder, err := s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil {
+		return nil, fmt.Errorf("There is no shippingOrder with this ID")
+	}
+
+	if searchedShippingOrder.OrderStatus == "entregado" || searchedShippingOrder.OrderStatus == "devuelto" || searchedShippingOrder.OrderStatus == "cancelado" {
+		return nil, fmt.Errorf("the courier of a delivered, returned or cancelled order can not be changed")
+	}
+
+	// Check if courier exists and is working.
+	assignedCourier, err := s.courierRepository.GetCourier(ctx, shippingOrderCourierAssign.CourierID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if assignedCourier == nil || assignedCourier.Application != searchedShippingOrder.Application {
+		return nil, fmt.Errorf("There is no courier with this ID")
+	}
+	if assignedCourier.CourierStatus == courier.COURIER_STATUS_OFF_DUTY {
+		return nil, fmt.Errorf("the courier is off duty")
+	}
+
+	// Set value for 'Modified' attribute.
+	shippingOrder := &ShippingOrder{
+		CourierID:   &assignedCourier.ID,
+		UpdatedUser: shippingOrderCourierAssign.UpdatedUser,
+		UpdatedAt:   time.Now(),
+	}
+
+	// Pass to the repository layer.
+	if err = s.shippingOrderRepository.UpdateShippingOrderCourier(ctx, shippingOrderID, shippingOrder); err != nil {
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	return s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+}
+
+// Implementation of 'GetShippingOrderLocation'.
+func (s *shippingOrderService) GetShippingOrderLocation(ctx context.Context, shippingOrderID int) (*ShippingOrderLocationOut, error) {
+	// Check if shippingOrder exists.
+	searchedShippingOrder, err := s.shippingOrderRepository.GetShippingOrder(ctx, shippingOrderID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil {
+		return nil, fmt.Errorf("There is no shippingOrder with this ID")
+	}
+
+	// The parcel travels with the courier only while en route.
+	if searchedShippingOrder.OrderStatus != "en_ruta" || searchedShippingOrder.CourierID == nil {
+		return nil, fmt.Errorf("the location is only available for orders en route")
+	}
+
+	position, err := s.locationRepository.GetLocation(ctx, *searchedShippingOrder.CourierID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "location could not be retrieved")
+	}
+
+	// Fall back to the last persisted position.
+	if position == nil {
+		assignedCourier, err := s.courierRepository.GetCourier(ctx, *searchedShippingOrder.CourierID)
+		if err != nil {
+			return nil, utils.FailOnError(err, "information could not be retrieved")
+		}
+		if assignedCourier != nil && assignedCourier.LastLat != nil && assignedCourier.LastLng != nil {
+			position = &courier.CourierPosition{
+				CourierID: assignedCourier.ID,
+				Lat:       *assignedCourier.LastLat,
+				Lng:       *assignedCourier.LastLng,
+			}
+			if assignedCourier.LastLocationAt != nil {
+				position.RecordedAt = *assignedCourier.LastLocationAt
+			}
+		}
+	}
+
+	return &ShippingOrderLocationOut{
+		ShippingOrderID:       shippingOrderID,
+		OrderStatus:           searchedShippingOrder.OrderStatus,
+		Position:              position,
+		EstimatedDeliveryFrom: searchedShippingOrder.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   searchedShippingOrder.EstimatedDeliveryTo,
+	}, nil
+}
+
+// Checks that the statuses reserved to couriers are set by the courier assigned to the order.
+func (s *shippingOrderService) checkAssignedCourier(ctx context.Context, shippingOrder *ShippingOrderOut, orderStatus string, userID int) error {
+	if !courierOrderStatuses[orderStatus] {
+		return nil
+	}
+	if shippingOrder.CourierID == nil {
+		return fmt.Errorf("the order has no courier assigned, %w", ErrNotAssignedCourier)
+	}
+
+	assignedCourier, err := s.courierRepository.GetCourier(ctx, *shippingOrder.CourierID)
+	if err != nil {
+		return utils.FailOnError(err, "information could not be retrieved")
+	}
+	if assignedCourier == nil || assignedCourier.UserID != userID {
+		return ErrNotAssignedCourier
+	}
+
+	return nil
+}
+
+// Notifies sender and recipient of a new status in background, the change is already stored.
+func (s *shippingOrderService) notifyStatusChange(shippingOrder *ShippingOrderOut, orderStatus string) {
+	statusChange := &notification.StatusChange{
+		ShippingOrderID: shippingOrder.ID,
+		OrderStatus:     orderStatus,
+		Language:        shippingOrder.Notifications.Language,
+		Sender: &notification.Contact{
+			FullName: shippingOrder.Sender.FullNameSender,
+			Email:    shippingOrder.Sender.EmailSender,
+			Phone:    shippingOrder.Sender.PhoneSender,
+			OptOut:   shippingOrder.Notifications.OptOutSender,
+		},
+		Recipient: &notification.Contact{
+			FullName: shippingOrder.Recipient.FullNameRecipient,
+			Email:    shippingOrder.Recipient.EmailRecipient,
+			Phone:    shippingOrder.Recipient.PhoneRecipient,
+			OptOut:   shippingOrder.Notifications.OptOutRecipient,
+		},
+	}
+
+	go func() {
+		if err := s.notificationService.NotifyStatusChange(context.Background(), statusChange); err != nil {
+			log.Printf("Oops... ShippingOrder %d could not be notified! Reason: %v", statusChange.ShippingOrderID, err)
+		}
+	}()
+}
+
+// Checks that an order can move from its current status to the requested one.
+func validateStatusTransition(currentOrderStatus string, orderStatus string) error {
+	transition, ok := orderStatusTransitions[orderStatus]
+	if !ok {
+		return nil
+	}
+
+	for _, from := range transition.from {
+		if currentOrderStatus == from {
+			return nil
+		}
+	}
+
+	return errors.New(transition.message)
+}
+
+// Sets the totals of a batch once every order has an outcome.
+func closeStatusBatch(batchOut *ShippingOrderStatusBatchOut, committed bool) *ShippingOrderStatusBatchOut {
+	batchOut.Committed = committed
+	for _, result := range batchOut.Results {
+		if result.Success {
+			batchOut.Updated++
+			continue
+		}
+		if result.Message == "" {
+			result.Message = "not applied, the batch was rolled back"
+		}
+		batchOut.Failed++
+	}
+
+	return batchOut
+}
+
+// Gets the ID of the service zone that contains one end of the order.
+// Without configured zones every point is served and has no zone.
+func (s *shippingOrderService) resolveZone(ctx context.Context, end string, country string, lat float64, lng float64) (string, error) {
+	if !s.zoneRepository.IsConfigured() {
+		return "", nil
+	}
+
+	searchedZone, err := s.zoneRepository.FindZone(ctx, country, lat, lng)
+	if err != nil {
+		return "", utils.FailOnError(err, "zone information could not be retrieved")
+	}
+	if searchedZone == nil {
+		return "", fmt.Errorf("the %s is outside of the service zones", end)
+	}
+
+	return searchedZone.ID, nil
+}
+
+// Implementation of 'GetShippingOrdersNearby'.
+func (s *shippingOrderService) GetShippingOrdersNearby(ctx context.Context, shippingOrderNearbyQuery *ShippingOrderNearbyQuery) (*[]ShippingOrderOut, error) {
+	limit := shippingOrderNearbyQuery.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	return s.shippingOrderRepository.GetShippingOrdersNearby(ctx, shippingOrderNearbyQuery.End, *shippingOrderNearbyQuery.Lat, *shippingOrderNearbyQuery.Lng, shippingOrderNearbyQuery.RadiusKm, limit)
+}
+
+// Implementation of 'GetShippingOrdersInBox'.
+func (s *shippingOrderService) GetShippingOrdersInBox(ctx context.Context, shippingOrderBoxQuery *ShippingOrderBoxQuery) (*[]ShippingOrderOut, error) {
+	limit := shippingOrderBoxQuery.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	return s.shippingOrderRepository.GetShippingOrdersInBox(ctx, shippingOrderBoxQuery.End, *shippingOrderBoxQuery.MinLat, *shippingOrderBoxQuery.MinLng, *shippingOrderBoxQuery.MaxLat, *shippingOrderBoxQuery.MaxLng, limit)
+}
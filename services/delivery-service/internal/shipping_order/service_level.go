@@ -0,0 +1,77 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/service_level"
+	"delivery-service/internal/utils"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time zone of a country, the one of its operating calendar or the server one when it has none.
+func (s *shippingOrderService) countryLocation(ctx context.Context, country string) (*time.Location, error) {
+	operatingCalendar, err := s.calendarRepository.GetCalendar(ctx, country)
+	if err != nil {
+		return nil, utils.FailOnError(err, "operating calendar information could not be retrieved")
+	}
+	if operatingCalendar == nil {
+		return time.Local, nil
+	}
+
+	return operatingCalendar.Location, nil
+}
+
+// Checks the order against the rules of its service level.
+func validateServiceLevel(now time.Time, location *time.Location, serviceLevel *service_level.ServiceLevelOut, origin *ShippingOrderOrigin, destination *ShippingOrderDestination, packageSize string) error {
+	// Cut-off times are "HH:MM" in the time zone of the origin.
+	if serviceLevel.CutoffTime != "" && now.In(location).Format("15:04") > serviceLevel.CutoffTime {
+		return fmt.Errorf("orders of the %s service level must be created before %s", serviceLevel.Nemo, serviceLevel.CutoffTime)
+	}
+
+	allowed := false
+	for _, allowedSize := range strings.Split(serviceLevel.AllowedSizes, ",") {
+		if strings.TrimSpace(allowedSize) == packageSize {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("the %s service level does not accept packages of size %s", serviceLevel.Nemo, packageSize)
+	}
+
+	if serviceLevel.MaxDistanceKm > 0 && orderDistanceKm(origin, destination) > serviceLevel.MaxDistanceKm {
+		return fmt.Errorf("the %s service level does not deliver further than %.0f km", serviceLevel.Nemo, serviceLevel.MaxDistanceKm)
+	}
+
+	return nil
+}
+
+// Prices an order as a base fare plus distance and weight, scaled by the service level.
+func quoteShippingOrder(serviceLevel *service_level.ServiceLevelOut, origin *ShippingOrderOrigin, destination *ShippingOrderDestination, weightProduct int) float64 {
+	// Define pricing settings.
+	base, err := strconv.ParseFloat(os.Getenv("PRICING_BASE"), 64)
+	if err != nil {
+		base = 5
+	}
+	perKm, err := strconv.ParseFloat(os.Getenv("PRICING_PER_KM"), 64)
+	if err != nil {
+		perKm = 0.5
+	}
+	perKg, err := strconv.ParseFloat(os.Getenv("PRICING_PER_KG"), 64)
+	if err != nil {
+		perKg = 0.2
+	}
+
+	price := (base + perKm*orderDistanceKm(origin, destination) + perKg*float64(weightProduct)) * serviceLevel.PriceMultiplier
+
+	return math.Round(price*100) / 100
+}
+
+// Distance in kilometers between the origin and the destination.
+func orderDistanceKm(origin *ShippingOrderOrigin, destination *ShippingOrderDestination) float64 {
+	return utils.Haversine(*origin.LatOrigin, *origin.LngOrigin, *destination.LatDestination, *destination.LngDestination)
+}
@@ -0,0 +1,82 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/station"
+	"delivery-service/internal/utils"
+	"errors"
+	"fmt"
+)
+
+// Returned when the parcels arriving at a station no longer fit in it.
+var ErrStationFull = errors.New("the station has no room left for the parcels arriving at it")
+
+// Returned when the station parcels arrive at was deleted meanwhile.
+var ErrStationUnavailable = errors.New("the station does not exist or was deleted")
+
+// Checks the station parcels are arriving at.
+// Returns the station and the parcels it still has room for, the room is checked again when they arrive.
+func (s *shippingOrderService) stationRoom(ctx context.Context, stationID int) (*station.StationOut, int, error) {
+	searchedStation, err := s.stationRepository.GetStation(ctx, stationID)
+	if err != nil {
+		return nil, 0, utils.FailOnError(err, "station information could not be retrieved")
+	}
+	if searchedStation == nil {
+		return nil, 0, fmt.Errorf("There is no station with this ID")
+	}
+
+	occupancy, err := s.shippingOrderRepository.CountStationParcels(ctx, stationID)
+	if err != nil {
+		return nil, 0, utils.FailOnError(err, "station occupancy could not be retrieved")
+	}
+
+	room := searchedStation.Capacity - occupancy
+	if room < 0 {
+		room = 0
+	}
+
+	return searchedStation, room, nil
+}
+
+// Parcels that move with an order, the ones still in its status.
+func parcelsInStatus(shippingOrder *ShippingOrderOut) int {
+	count := 0
+	for _, parcel := range shippingOrder.Parcels {
+		if parcel.ParcelStatus == shippingOrder.OrderStatus {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Error of a station without room for the parcels arriving at it.
+func stationFullError(searchedStation *station.StationOut, room int) error {
+	return fmt.Errorf("%w, %d more parcels fit in %v", ErrStationFull, room, searchedStation.Code)
+}
+
+// Implementation of 'GetStationInventory'.
+func (s *shippingOrderService) GetStationInventory(ctx context.Context, stationID int) (*StationInventoryOut, error) {
+	// Check if station exists.
+	searchedStation, err := s.stationRepository.GetStation(ctx, stationID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedStation == nil {
+		return nil, nil
+	}
+
+	stationParcels, err := s.shippingOrderRepository.GetStationParcels(ctx, stationID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+
+	return &StationInventoryOut{
+		StationID: searchedStation.ID,
+		Code:      searchedStation.Code,
+		Name:      searchedStation.Name,
+		Capacity:  searchedStation.Capacity,
+		Occupancy: len(stationParcels),
+		Parcels:   stationParcels,
+	}, nil
+}
@@ -0,0 +1,273 @@
+package shipping_order
+
+import (
+	"context"
+	"delivery-service/internal/courier"
+	"delivery-service/internal/notification"
+	"delivery-service/internal/outbox"
+	"delivery-service/internal/service_level"
+	"sync"
+	"testing"
+)
+
+// In-memory repository, enough to drive the status batch without a database.
+type memoryRepository struct {
+	ShippingOrderRepository
+	mu      sync.Mutex
+	orders  map[int]*ShippingOrderOut
+	changed map[int]bool
+	events  int
+}
+
+func newMemoryRepository(orders ...*ShippingOrderOut) *memoryRepository {
+	r := &memoryRepository{orders: make(map[int]*ShippingOrderOut), changed: make(map[int]bool)}
+	for _, order := range orders {
+		r.orders[order.ID] = order
+	}
+	return r
+}
+
+func (r *memoryRepository) GetShippingOrder(ctx context.Context, shippingOrderID int) (*ShippingOrderOut, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[shippingOrderID]
+	if !ok {
+		return nil, nil
+	}
+	searched := *order
+	return &searched, nil
+}
+
+// Orders marked as changed were moved by someone else after the service checked them.
+func (r *memoryRepository) UpdateShippingOrdersStatus(ctx context.Context, shippingOrderIDs []int, previousOrderStatuses []string, shippingOrder *ShippingOrder, events []*outbox.Event, atomic bool) (map[int]error, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rejected := make(map[int]error)
+	var updated []int
+	for i, shippingOrderID := range shippingOrderIDs {
+		if r.changed[shippingOrderID] || r.orders[shippingOrderID].OrderStatus != previousOrderStatuses[i] {
+			rejected[shippingOrderID] = ErrShippingOrderChanged
+			continue
+		}
+		updated = append(updated, shippingOrderID)
+	}
+
+	// Nothing is stored when the transaction is rolled back.
+	if atomic && len(rejected) > 0 {
+		return rejected, nil
+	}
+
+	for _, shippingOrderID := range updated {
+		r.orders[shippingOrderID].OrderStatus = shippingOrder.OrderStatus
+	}
+	r.events += len(updated)
+	return rejected, nil
+}
+
+func (r *memoryRepository) UpdateShippingOrderEstimate(ctx context.Context, shippingOrderID int, shippingOrder *ShippingOrder) error {
+	return nil
+}
+
+type memoryCourierRepository struct {
+	courier.CourierRepository
+	couriers map[int]*courier.CourierOut
+}
+
+func (r *memoryCourierRepository) GetCourier(ctx context.Context, courierID int) (*courier.CourierOut, error) {
+	return r.couriers[courierID], nil
+}
+
+type memoryServiceLevelRepository struct {
+	service_level.ServiceLevelRepository
+}
+
+func (r *memoryServiceLevelRepository) GetServiceLevel(ctx context.Context, serviceLevelNemo string) (*service_level.ServiceLevelOut, error) {
+	return &service_level.ServiceLevelOut{Nemo: serviceLevelNemo}, nil
+}
+
+type silentNotificationService struct {
+	notification.NotificationService
+}
+
+func (n *silentNotificationService) NotifyStatusChange(ctx context.Context, statusChange *notification.StatusChange) error {
+	return nil
+}
+
+// The courier of the tests works with the user 7.
+const testCourierUserID = 7
+
+// Order of the tests in the given status, assigned to the courier of the tests.
+func newStatusOrder(shippingOrderID int, orderStatus string) *ShippingOrderOut {
+	courierID := 1
+	return &ShippingOrderOut{
+		ID:            shippingOrderID,
+		Sender:        &ShippingOrderSender{FullNameSender: "Ana Perez"},
+		Recipient:     &ShippingOrderRecipient{FullNameRecipient: "Luis Soto"},
+		Origin:        &ShippingOrderOrigin{LatOrigin: coordinate(-33.4263), LngOrigin: coordinate(-70.6170)},
+		Destination:   &ShippingOrderDestination{LatDestination: coordinate(-33.0458), LngDestination: coordinate(-71.6197)},
+		Package:       &ShippingOrderPackage{PackageSize: "S"},
+		Notifications: &ShippingOrderNotifications{OptOutSender: true, OptOutRecipient: true, Language: "es"},
+		ServiceLevel:  "standard",
+		OrderStatus:   orderStatus,
+		CourierID:     &courierID,
+		Application:   "app",
+	}
+}
+
+// Service over the given orders, with the courier of the tests.
+func newStatusService(repository *memoryRepository) *shippingOrderService {
+	return &shippingOrderService{
+		shippingOrderRepository: repository,
+		serviceLevelRepository:  &memoryServiceLevelRepository{},
+		courierRepository: &memoryCourierRepository{couriers: map[int]*courier.CourierOut{
+			1: {ID: 1, UserID: testCourierUserID},
+		}},
+		notificationService: &silentNotificationService{},
+	}
+}
+
+func TestUpdateShippingOrdersStatus(t *testing.T) {
+	type outcome struct {
+		success bool
+		message string
+	}
+
+	tests := []struct {
+		name        string
+		orders      []*ShippingOrderOut
+		changed     []int
+		batch       *ShippingOrderStatusBatch
+		committed   bool
+		results     map[int]outcome
+		orderStatus map[int]string
+	}{
+		{
+			name:      "partial mode updates every valid order",
+			orders:    []*ShippingOrderOut{newStatusOrder(1, "creado"), newStatusOrder(2, "creado")},
+			batch:     &ShippingOrderStatusBatch{ShippingOrderIDs: []int{1, 2}, OrderStatus: "recolectado", Mode: "partial"},
+			committed: true,
+			results: map[int]outcome{
+				1: {success: true},
+				2: {success: true},
+			},
+			orderStatus: map[int]string{1: "recolectado", 2: "recolectado"},
+		},
+		{
+			name:      "partial mode reports each rejected order and keeps the rest",
+			orders:    []*ShippingOrderOut{newStatusOrder(1, "creado"), newStatusOrder(2, "en_ruta")},
+			batch:     &ShippingOrderStatusBatch{ShippingOrderIDs: []int{1, 2, 3}, OrderStatus: "recolectado", Mode: "partial"},
+			committed: true,
+			results: map[int]outcome{
+				1: {success: true},
+				2: {message: "to make this change the order status must be created"},
+				3: {message: "There is no shippingOrder with this ID"},
+			},
+			orderStatus: map[int]string{1: "recolectado", 2: "en_ruta"},
+		},
+		{
+			name:      "partial mode reports an order changed while processing",
+			orders:    []*ShippingOrderOut{newStatusOrder(1, "creado"), newStatusOrder(2, "creado")},
+			changed:   []int{2},
+			batch:     &ShippingOrderStatusBatch{ShippingOrderIDs: []int{1, 2}, OrderStatus: "recolectado", Mode: "partial"},
+			committed: true,
+			results: map[int]outcome{
+				1: {success: true},
+				2: {message: "the status of the order changed while processing the batch"},
+			},
+			orderStatus: map[int]string{1: "recolectado", 2: "creado"},
+		},
+		{
+			name:      "atomic mode rolls back when one order is rejected",
+			orders:    []*ShippingOrderOut{newStatusOrder(1, "creado"), newStatusOrder(2, "en_ruta")},
+			batch:     &ShippingOrderStatusBatch{ShippingOrderIDs: []int{1, 2}, OrderStatus: "recolectado", Mode: "atomic"},
+			committed: false,
+			results: map[int]outcome{
+				1: {message: "not applied, the batch was rolled back"},
+				2: {message: "to make this change the order status must be created"},
+			},
+			orderStatus: map[int]string{1: "creado", 2: "en_ruta"},
+		},
+		{
+			name:      "atomic mode rolls back when one order changed while processing",
+			orders:    []*ShippingOrderOut{newStatusOrder(1, "creado"), newStatusOrder(2, "creado")},
+			changed:   []int{2},
+			batch:     &ShippingOrderStatusBatch{ShippingOrderIDs: []int{1, 2}, OrderStatus: "recolectado", Mode: "atomic"},
+			committed: false,
+			results: map[int]outcome{
+				1: {message: "not applied, the batch was rolled back"},
+				2: {message: "the status of the order changed while processing the batch"},
+			},
+			orderStatus: map[int]string{1: "creado", 2: "creado"},
+		},
+		{
+			name:      "delivered orders are rejected in a batch",
+			orders:    []*ShippingOrderOut{newStatusOrder(1, "en_ruta")},
+			batch:     &ShippingOrderStatusBatch{ShippingOrderIDs: []int{1}, OrderStatus: "entregado", Mode: "partial"},
+			committed: true,
+			results: map[int]outcome{
+				1: {message: "orders must be delivered one at a time with their proof of delivery"},
+			},
+			orderStatus: map[int]string{1: "en_ruta"},
+		},
+		{
+			name:      "only the assigned courier moves its orders",
+			orders:    []*ShippingOrderOut{newStatusOrder(1, "devolucion")},
+			batch:     &ShippingOrderStatusBatch{ShippingOrderIDs: []int{1}, OrderStatus: "devuelto", Mode: "partial", UserID: testCourierUserID + 1},
+			committed: true,
+			results: map[int]outcome{
+				1: {message: ErrNotAssignedCourier.Error()},
+			},
+			orderStatus: map[int]string{1: "devolucion"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := newMemoryRepository(test.orders...)
+			for _, shippingOrderID := range test.changed {
+				repository.changed[shippingOrderID] = true
+			}
+			if test.batch.UserID == 0 {
+				test.batch.UserID = testCourierUserID
+			}
+			test.batch.UpdatedUser = "tester"
+
+			batchOut, err := newStatusService(repository).UpdateShippingOrdersStatus(context.Background(), test.batch)
+			if err != nil {
+				t.Fatalf("update: %v", err)
+			}
+
+			if batchOut.Committed != test.committed {
+				t.Errorf("committed %v, expected %v", batchOut.Committed, test.committed)
+			}
+			if len(batchOut.Results) != len(test.results) {
+				t.Fatalf("%d results, expected %d", len(batchOut.Results), len(test.results))
+			}
+
+			updated := 0
+			for _, result := range batchOut.Results {
+				expected := test.results[result.ShippingOrderID]
+				if result.Success != expected.success || result.Message != expected.message {
+					t.Errorf("order %d: success %v %q, expected %v %q", result.ShippingOrderID, result.Success, result.Message, expected.success, expected.message)
+				}
+				if result.Success {
+					updated++
+				}
+			}
+			if batchOut.Updated != updated || batchOut.Failed != len(batchOut.Results)-updated {
+				t.Errorf("updated %d and failed %d, expected %d and %d", batchOut.Updated, batchOut.Failed, updated, len(batchOut.Results)-updated)
+			}
+			if repository.events != updated {
+				t.Errorf("%d events stored, expected %d", repository.events, updated)
+			}
+
+			for shippingOrderID, orderStatus := range test.orderStatus {
+				if stored := repository.orders[shippingOrderID].OrderStatus; stored != orderStatus {
+					t.Errorf("order %d stored as %v, expected %v", shippingOrderID, stored, orderStatus)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package shipping_order
+
+import (
+	"context"
+	"crypto/rand"
+	"delivery-service/internal/notification"
+	"delivery-service/internal/utils"
+	"delivery-service/internal/verification"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Characters of the tracking codes, without the ones easy to mistake for each other.
+const trackingCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Returned when the preferences are changed without a session verified for the order.
+var ErrTrackingSessionRequired = errors.New("a verified tracking session of the order is required to make this change")
+
+// Returned when the verification code typed by the recipient is wrong or expired.
+var ErrWrongVerificationCode = errors.New("the verification code is not valid")
+
+// Returned when the order already left for delivery.
+var ErrPreferencesClosed = errors.New("the delivery preferences can not be changed once the order is en route")
+
+// Generates a random tracking code of twelve characters.
+func newTrackingCode() (string, error) {
+	max := big.NewInt(int64(len(trackingCodeAlphabet)))
+
+	code := make([]byte, 12)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = trackingCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// Hides most of an email, keeping its first character and its domain.
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return ""
+	}
+
+	return email[:1] + strings.Repeat("*", at-1) + email[at:]
+}
+
+// Hides most of a phone, keeping its last three digits.
+func maskPhone(phone string) string {
+	if len(phone) <= 3 {
+		return ""
+	}
+
+	return strings.Repeat("*", len(phone)-3) + phone[len(phone)-3:]
+}
+
+// Builds the public view of an order, the preferences are only shown with a verified session.
+func newTrackingOut(shippingOrder *ShippingOrderOut, verified bool) *TrackingOut {
+	trackingOut := &TrackingOut{
+		TrackingCode:          shippingOrder.TrackingCode,
+		OrderStatus:           shippingOrder.OrderStatus,
+		EstimatedDeliveryFrom: shippingOrder.EstimatedDeliveryFrom,
+		EstimatedDeliveryTo:   shippingOrder.EstimatedDeliveryTo,
+		DeliveryAttempts:      shippingOrder.DeliveryAttempts,
+		RecipientEmail:        maskEmail(shippingOrder.Recipient.EmailRecipient),
+		RecipientPhone:        maskPhone(shippingOrder.Recipient.PhoneRecipient),
+		PreferencesOpen:       preferenceOrderStatuses[shippingOrder.OrderStatus],
+	}
+	if verified {
+		trackingOut.Preferences = shippingOrder.Preferences
+	}
+
+	return trackingOut
+}
+
+// Checks that a token belongs to a session verified for the order.
+func (s *shippingOrderService) checkTrackingSession(ctx context.Context, trackingCode string, token string) error {
+	session, err := s.verificationRepository.GetSession(ctx, token)
+	if err != nil {
+		return utils.FailOnError(err, "the tracking session could not be retrieved")
+	}
+	if session == nil || session.Subject != trackingCode {
+		return ErrTrackingSessionRequired
+	}
+
+	return nil
+}
+
+// Implementation of 'GetTracking'.
+func (s *shippingOrderService) GetTracking(ctx context.Context, trackingCode string, token string) (*TrackingOut, error) {
+	searchedShippingOrder, err := s.shippingOrderRepository.GetTrackedShippingOrder(ctx, trackingCode)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil {
+		return nil, nil
+	}
+
+	// Without a token the view is public.
+	verified := false
+	if token != "" {
+		if err = s.checkTrackingSession(ctx, trackingCode, token); err != nil {
+			return nil, err
+		}
+		verified = true
+	}
+
+	return newTrackingOut(searchedShippingOrder, verified), nil
+}
+
+// Implementation of 'SendTrackingCode'.
+func (s *shippingOrderService) SendTrackingCode(ctx context.Context, trackingCode string, trackingCodeInsert *TrackingCodeInsert) (*TrackingCodeOut, error) {
+	searchedShippingOrder, err := s.shippingOrderRepository.GetTrackedShippingOrder(ctx, trackingCode)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil {
+		return nil, nil
+	}
+
+	// The code goes to the recipient, never to the one asking for it.
+	to, sentTo := searchedShippingOrder.Recipient.EmailRecipient, maskEmail(searchedShippingOrder.Recipient.EmailRecipient)
+	if trackingCodeInsert.Channel == notification.CHANNEL_SMS {
+		to, sentTo = searchedShippingOrder.Recipient.PhoneRecipient, maskPhone(searchedShippingOrder.Recipient.PhoneRecipient)
+	}
+	if to == "" {
+		return nil, fmt.Errorf("the recipient of the order has no %s", trackingCodeInsert.Channel)
+	}
+
+	code, err := verification.NewCode()
+	if err != nil {
+		return nil, utils.FailOnError(err, "the verification code could not be generated")
+	}
+	if err = s.verificationRepository.SaveCode(ctx, trackingCode, code); err != nil {
+		if errors.Is(err, verification.ErrCodeRecentlySent) {
+			return nil, err
+		}
+		return nil, utils.FailOnError(err, "the verification code could not be stored")
+	}
+
+	codeTTL := s.verificationRepository.CodeTTL()
+	err = s.notificationService.NotifyVerificationCode(ctx, &notification.VerificationCode{
+		ShippingOrderID: searchedShippingOrder.ID,
+		Channel:         trackingCodeInsert.Channel,
+		To:              to,
+		FullName:        searchedShippingOrder.Recipient.FullNameRecipient,
+		Code:            code,
+		ExpiresMinutes:  int(codeTTL / time.Minute),
+		Language:        searchedShippingOrder.Notifications.Language,
+	})
+	if err != nil {
+		return nil, utils.FailOnError(err, "the verification code could not be sent")
+	}
+
+	return &TrackingCodeOut{
+		Channel:   trackingCodeInsert.Channel,
+		SentTo:    sentTo,
+		ExpiresAt: time.Now().Add(codeTTL),
+	}, nil
+}
+
+// Implementation of 'VerifyTrackingCode'.
+func (s *shippingOrderService) VerifyTrackingCode(ctx context.Context, trackingCode string, trackingVerifyInsert *TrackingVerifyInsert) (*verification.Session, error) {
+	searchedShippingOrder, err := s.shippingOrderRepository.GetTrackedShippingOrder(ctx, trackingCode)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil {
+		return nil, nil
+	}
+
+	verified, err := s.verificationRepository.CheckCode(ctx, trackingCode, trackingVerifyInsert.Code)
+	if err != nil {
+		if errors.Is(err, verification.ErrTooManyAttempts) {
+			return nil, err
+		}
+		return nil, utils.FailOnError(err, "the verification code could not be checked")
+	}
+	if !verified {
+		return nil, ErrWrongVerificationCode
+	}
+
+	session, err := s.verificationRepository.CreateSession(ctx, trackingCode)
+	if err != nil {
+		return nil, utils.FailOnError(err, "the tracking session could not be created")
+	}
+
+	return session, nil
+}
+
+// Implementation of 'UpdateDeliveryPreferences'.
+func (s *shippingOrderService) UpdateDeliveryPreferences(ctx context.Context, trackingCode string, token string, deliveryPreferencesUpdate *DeliveryPreferencesUpdate) (*TrackingOut, error) {
+	if err := s.checkTrackingSession(ctx, trackingCode, token); err != nil {
+		return nil, err
+	}
+
+	searchedShippingOrder, err := s.shippingOrderRepository.GetTrackedShippingOrder(ctx, trackingCode)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedShippingOrder == nil {
+		return nil, nil
+	}
+	if !preferenceOrderStatuses[searchedShippingOrder.OrderStatus] {
+		return nil, ErrPreferencesClosed
+	}
+
+	// A neighbour receives the order at its destination, not at a pickup point.
+	if deliveryPreferencesUpdate.PickupPoint != nil && deliveryPreferencesUpdate.Neighbour != nil {
+		return nil, fmt.Errorf("the order can not be redirected to a pickup point and left with a neighbour")
+	}
+
+	preferences := &DeliveryPreferences{
+		ShippingOrderID: searchedShippingOrder.ID,
+		Instructions:    strings.TrimSpace(deliveryPreferencesUpdate.Instructions),
+		Neighbour:       deliveryPreferencesUpdate.Neighbour,
+		UpdatedAt:       time.Now(),
+	}
+
+	if deliveryPreferencesUpdate.PickupPoint != nil {
+		preferences.PickupPoint, err = s.locatePickupPoint(ctx, searchedShippingOrder, deliveryPreferencesUpdate.PickupPoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if deliveryPreferencesUpdate.DeliveryWindow != nil {
+		preferences.DeliveryWindowFrom, preferences.DeliveryWindowTo, err = s.scheduleDeliveryWindow(ctx, searchedShippingOrder, deliveryPreferencesUpdate.DeliveryWindow, preferences.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Prepare the event of the change.
+	preferencesOut := &DeliveryPreferencesOut{
+		DeliveryWindowFrom: preferences.DeliveryWindowFrom,
+		DeliveryWindowTo:   preferences.DeliveryWindowTo,
+		Instructions:       preferences.Instructions,
+		Neighbour:          preferences.Neighbour,
+		UpdatedAt:          preferences.UpdatedAt,
+	}
+	changed := &DeliveryPreferencesChanged{
+		ShippingOrderID: searchedShippingOrder.ID,
+		Preferences:     preferencesOut,
+		UpdatedAt:       preferences.UpdatedAt,
+	}
+	if pickupPoint := preferences.PickupPoint; pickupPoint != nil {
+		preferencesOut.PickupPoint = pickupPoint.Name
+		changed.Destination = &ShippingOrderDestination{
+			LatDestination:       &pickupPoint.Lat,
+			LngDestination:       &pickupPoint.Lng,
+			AddressDestination:   pickupPoint.Address,
+			ReferenceDestination: pickupPoint.Name,
+		}
+	}
+	event, err := newShippingOrderEvent(EVENT_DELIVERY_PREFERENCES_CHANGED, searchedShippingOrder.ID, searchedShippingOrder.Application, changed)
+	if err != nil {
+		return nil, utils.FailOnError(err, "the event of the record could not be prepared")
+	}
+
+	// Pass to the repository layer.
+	if err = s.shippingOrderRepository.UpdateDeliveryPreferences(ctx, preferences, event); err != nil {
+		if errors.Is(err, ErrPreferencesClosed) {
+			return nil, err
+		}
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	updatedShippingOrder, err := s.shippingOrderRepository.GetTrackedShippingOrder(ctx, trackingCode)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+
+	// Without a window, the promise follows the destination the order goes to now.
+	if preferences.DeliveryWindowFrom == nil {
+		if err = s.refreshDeliveryEstimate(ctx, updatedShippingOrder, updatedShippingOrder.OrderStatus); err != nil {
+			log.Printf("Oops... Delivery estimate of ShippingOrder %d could not be updated! Reason: %v", updatedShippingOrder.ID, err)
+		}
+	}
+
+	return newTrackingOut(updatedShippingOrder, true), nil
+}
+
+// Locates the pickup point an order is redirected to, it must be served in the country of the destination.
+func (s *shippingOrderService) locatePickupPoint(ctx context.Context, shippingOrder *ShippingOrderOut, pickupPointInsert *PickupPointInsert) (*PickupPoint, error) {
+	pickupPoint := &PickupPoint{
+		Name:    strings.TrimSpace(pickupPointInsert.Name),
+		Address: pickupPointInsert.Address,
+	}
+	// A pickup point is only sent by its address.
+	var lat, lng *float64
+	if err := s.locate(ctx, "pickup point", &pickupPoint.Address, &lat, &lng); err != nil {
+		return nil, err
+	}
+	pickupPoint.Lat, pickupPoint.Lng = *lat, *lng
+	if pickupPoint.Address.CountryCode != shippingOrder.Destination.AddressDestination.CountryCode {
+		return nil, fmt.Errorf("the pickup point must be in the country of the destination")
+	}
+
+	zoneID, err := s.resolveZone(ctx, "pickup point", pickupPoint.Address.CountryCode, pickupPoint.Lat, pickupPoint.Lng)
+	if err != nil {
+		return nil, err
+	}
+	pickupPoint.ZoneID = zoneID
+
+	return pickupPoint, nil
+}
+
+// Checks the delivery window chosen by the recipient, it can not end before the order could be delivered.
+func (s *shippingOrderService) scheduleDeliveryWindow(ctx context.Context, shippingOrder *ShippingOrderOut, window *ShippingOrderWindow, now time.Time) (*time.Time, *time.Time, error) {
+	// Define delivery window settings.
+	minWindow, _ := strconv.Atoi(os.Getenv("DELIVERY_WINDOW_MIN_MINUTES"))
+	if minWindow <= 0 {
+		minWindow = 60
+	}
+	maxDays, _ := strconv.Atoi(os.Getenv("DELIVERY_WINDOW_MAX_DAYS"))
+	if maxDays <= 0 {
+		maxDays = 14
+	}
+
+	from, to, err := s.scheduleWindow(ctx, "delivery", window, shippingOrder.Destination.AddressDestination.CountryCode, now, minWindow, maxDays)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The earliest delivery is estimated again, an earlier choice of the recipient may be the current promise.
+	serviceLevel, err := s.serviceLevelRepository.GetServiceLevel(ctx, shippingOrder.ServiceLevel)
+	if err != nil {
+		return nil, nil, utils.FailOnError(err, "service level information could not be retrieved")
+	}
+	handoverAt := now
+	if shippingOrder.PickupFrom != nil && shippingOrder.PickupFrom.After(now) {
+		handoverAt = *shippingOrder.PickupFrom
+	}
+	earliest, _ := estimateDelivery(handoverAt, shippingOrder.OrderStatus, serviceLevel, shippingOrder.Origin, shippingOrder.Destination, shippingOrder.Package.PackageSize, nil)
+	if to.Before(earliest) {
+		return nil, nil, fmt.Errorf("the delivery window must not end before %s, the earliest the order can be delivered", earliest.Format("2006-01-02 15:04"))
+	}
+
+	return from, to, nil
+}
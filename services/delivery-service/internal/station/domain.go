@@ -0,0 +1,93 @@
+package station
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/internal/address"
+	"errors"
+	"time"
+)
+
+// Returned when a station to delete still holds parcels, they must leave it first.
+var ErrStationNotEmpty = errors.New("the station still holds parcels, they must leave it before it is deleted")
+
+// Station struct to describe Station object, a place where parcels wait between pickup and delivery.
+type Station struct {
+	ID           int               `db:"id"`
+	Code         string            `db:"code"`
+	Name         string            `db:"name"`
+	Address      *address.Address  `db:"-"`
+	Lat          float64           `db:"lat"`
+	Lng          float64           `db:"lng"`
+	OpeningHours map[string]string `db:"openingHours"`
+	Capacity     int               `db:"capacity"`
+	CreatedUser  string            `db:"created_user"`
+	CreatedAt    time.Time         `db:"created_at"`
+	UpdatedUser  string            `db:"updated_user"`
+	UpdatedAt    time.Time         `db:"updated_at"`
+	Status       string            `db:"status"`
+}
+
+// StationInsert struct to describe register a new station.
+// Opening hours are "HH:MM-HH:MM" per lowercase English weekday, days left out are closed.
+type StationInsert struct {
+	Code         string            `json:"code" validate:"required,alphanum,lte=20"`
+	Name         string            `json:"name" validate:"required,lte=100"`
+	Address      *address.Address  `json:"address" validate:"required"`
+	Lat          *float64          `json:"lat" validate:"required,latitude"`
+	Lng          *float64          `json:"lng" validate:"required,longitude"`
+	OpeningHours map[string]string `json:"openingHours" validate:"required,min=1"`
+	Capacity     int               `json:"capacity" validate:"required,gt=0"`
+	CreatedUser  string            `json:"createdUser" validate:"required,lte=100"`
+}
+
+// StationUpdate struct to describe update station.
+type StationUpdate struct {
+	Name         string            `json:"name" validate:"required,lte=100"`
+	Address      *address.Address  `json:"address" validate:"required"`
+	Lat          *float64          `json:"lat" validate:"required,latitude"`
+	Lng          *float64          `json:"lng" validate:"required,longitude"`
+	OpeningHours map[string]string `json:"openingHours" validate:"required,min=1"`
+	Capacity     int               `json:"capacity" validate:"required,gt=0"`
+	UpdatedUser  string            `json:"updatedUser" validate:"required,lte=100"`
+}
+
+// StationDelete struct to describe delete station.
+type StationDelete struct {
+	UpdatedUser string `json:"updatedUser" validate:"required,lte=100"`
+}
+
+type StationOut struct {
+	ID           int               `json:"id"`
+	Code         string            `json:"code"`
+	Name         string            `json:"name"`
+	Address      *address.Address  `json:"address"`
+	Lat          float64           `json:"lat"`
+	Lng          float64           `json:"lng"`
+	OpeningHours map[string]string `json:"openingHours"`
+	Capacity     int               `json:"capacity"`
+	CreatedUser  string            `json:"created_user"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedUser  string            `json:"updated_user"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	Status       string            `json:"status"`
+}
+
+// Our repository will implement these methods.
+type StationRepository interface {
+	GetStations(ctx context.Context) (*[]StationOut, error)
+	GetStation(ctx context.Context, stationID int) (*StationOut, error)
+	CodeExists(ctx context.Context, code string) (bool, error)
+	CreateStation(ctx context.Context, station *Station) (sql.Result, error)
+	UpdateStation(ctx context.Context, stationID int, station *Station) error
+	DeleteStation(ctx context.Context, stationID int, station *Station) error
+}
+
+// Our use-case or service will implement these methods.
+type StationService interface {
+	GetStations(ctx context.Context) (*[]StationOut, error)
+	GetStation(ctx context.Context, stationID int) (*StationOut, error)
+	CreateStation(ctx context.Context, stationInsert *StationInsert) (*StationOut, error)
+	UpdateStation(ctx context.Context, stationID int, stationUpdate *StationUpdate) (*StationOut, error)
+	DeleteStation(ctx context.Context, stationID int, stationDelete *StationDelete) error
+}
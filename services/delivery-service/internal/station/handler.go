@@ -0,0 +1,279 @@
+package station
+
+import (
+	"context"
+	"delivery-service/internal/middleware"
+	"delivery-service/internal/utils"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Represents our handler with our use-case / service.
+type StationHandler struct {
+	stationService StationService
+}
+
+// Creates a new handler.
+func NewStationHandler(stationRoute fiber.Router, ss StationService) {
+	// Create a handler based on our created service / use-case.
+	handler := &StationHandler{
+		stationService: ss,
+	}
+
+	// We will restrict this route with our JWT middleware.
+	stationRoute.Use(middleware.JWTProtected(), middleware.ExtractTokenMetadata)
+
+	// Declare routing endpoints for general routes.
+	stationRoute.Get("", handler.getStations)
+	stationRoute.Post("", handler.createStation)
+
+	// Declare routing endpoints for specific routes.
+	stationRoute.Get("/:stationID", handler.getStation)
+	stationRoute.Put("/:stationID", handler.updateStation)
+	stationRoute.Delete("/:stationID", handler.deleteStation)
+}
+
+// Gets all stations.
+func (h *StationHandler) getStations(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Get all stations.
+	stations, err := h.stationService.GetStations(customContext)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Stations obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      stations,
+	})
+}
+
+// Gets a single station.
+func (h *StationHandler) getStation(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedStationID, err := c.ParamsInt("stationID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid station ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get one station.
+	station, err := h.stationService.GetStation(customContext, targetedStationID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if station == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("Station of ID {%d} does not exist.", targetedStationID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Station obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      station,
+	})
+}
+
+// Creates a single station.
+func (h *StationHandler) createStation(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	stationInsert := &StationInsert{}
+
+	// Parse request body.
+	if err := c.BodyParser(stationInsert); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Station model.
+	validate := utils.NewValidator()
+
+	// Validate station fields.
+	if err := validate.Struct(stationInsert); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create one station.
+	station, err := h.stationService.CreateStation(customContext, stationInsert)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusCreated).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Station has been created successfully!",
+		"http_code": fiber.StatusCreated,
+		"data":      station,
+	})
+}
+
+// Updates a single station.
+func (h *StationHandler) updateStation(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	stationUpdate := &StationUpdate{}
+
+	// Fetch parameter.
+	targetedStationID, err := c.ParamsInt("stationID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid station ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(stationUpdate); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Station model.
+	validate := utils.NewValidator()
+
+	// Validate station fields.
+	if err := validate.Struct(stationUpdate); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Update one station.
+	station, err := h.stationService.UpdateStation(customContext, targetedStationID, stationUpdate)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Station has been updated successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      station,
+	})
+}
+
+// Deletes a single station.
+func (h *StationHandler) deleteStation(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	stationDelete := &StationDelete{}
+
+	// Fetch parameter.
+	targetedStationID, err := c.ParamsInt("stationID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid station ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(stationDelete); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Station model.
+	validate := utils.NewValidator()
+
+	// Validate station fields.
+	if err := validate.Struct(stationDelete); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Delete one station.
+	err = h.stationService.DeleteStation(customContext, targetedStationID, stationDelete)
+	if err != nil && errors.Is(err, ErrStationNotEmpty) {
+		return c.Status(fiber.StatusConflict).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusConflict,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Station has been deleted successfully!",
+		"http_code": fiber.StatusOK,
+	})
+}
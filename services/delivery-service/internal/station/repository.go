@@ -0,0 +1,196 @@
+package station
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/internal/address"
+	"encoding/json"
+)
+
+// Queries that we will use.
+const (
+	QUERY_STATION_COLUMNS = "id, code, name, street, number, district, city, region, country_code, postal_code, lat, lng, openingHours, capacity, " +
+		"created_user, created_at, updated_user, updated_at, status"
+	QUERY_GET_STATIONS   = "SELECT " + QUERY_STATION_COLUMNS + " FROM station WHERE status = ? order by code asc"
+	QUERY_GET_STATION    = "SELECT " + QUERY_STATION_COLUMNS + " FROM station WHERE id = ? and status = ?"
+	QUERY_CODE_EXISTS    = "SELECT EXISTS(SELECT 1 FROM station WHERE code = ?)"
+	QUERY_CREATE_STATION = "INSERT INTO station (code, name, street, number, district, city, region, country_code, postal_code, lat, lng, openingHours, capacity, " +
+		"created_user, created_at, updated_user, updated_at, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_UPDATE_STATION = "UPDATE station SET name = ?, street = ?, number = ?, district = ?, city = ?, region = ?, country_code = ?, postal_code = ?, " +
+		"lat = ?, lng = ?, openingHours = ?, capacity = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_DELETE_STATION = "UPDATE station SET status = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_LOCK_STATION   = "SELECT id FROM station WHERE id = ? FOR UPDATE"
+	QUERY_COUNT_PARCELS  = "SELECT COUNT(*) FROM shipping_order_parcels WHERE stationId = ? and parcelStatus = 'en_estacion'"
+)
+
+// Represents that we will use MariaDB in order to implement the methods.
+type mariaDBRepository struct {
+	mariadb *sql.DB
+}
+
+// Create a new repository with MariaDB as the driver.
+func NewStationRepository(mariaDBConnection *sql.DB) StationRepository {
+	return &mariaDBRepository{
+		mariadb: mariaDBConnection,
+	}
+}
+
+// Row of any query that selects 'QUERY_STATION_COLUMNS'.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a station row into the 'StationOut' struct, the opening hours are stored as JSON.
+func scanStation(row rowScanner) (*StationOut, error) {
+	station := &StationOut{Address: &address.Address{}}
+	var openingHours string
+	err := row.Scan(&station.ID, &station.Code, &station.Name,
+		&station.Address.Street, &station.Address.Number, &station.Address.District, &station.Address.City, &station.Address.Region, &station.Address.CountryCode, &station.Address.PostalCode,
+		&station.Lat, &station.Lng, &openingHours, &station.Capacity,
+		&station.CreatedUser, &station.CreatedAt, &station.UpdatedUser, &station.UpdatedAt, &station.Status)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal([]byte(openingHours), &station.OpeningHours); err != nil {
+		return nil, err
+	}
+
+	return station, nil
+}
+
+// Gets all stations in the database.
+func (r *mariaDBRepository) GetStations(ctx context.Context) (*[]StationOut, error) {
+	// Initialize variables.
+	stations := []StationOut{}
+
+	// Get all stations.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_STATIONS, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'stations' array.
+	for res.Next() {
+		station, err := scanStation(res)
+		if err != nil {
+			return nil, err
+		}
+		stations = append(stations, *station)
+	}
+
+	// Return all of our stations.
+	return &stations, res.Err()
+}
+
+// Gets a single station in the database.
+func (r *mariaDBRepository) GetStation(ctx context.Context, stationID int) (*StationOut, error) {
+	// Get one station.
+	// If it's empty, return null.
+	station, err := scanStation(r.mariadb.QueryRowContext(ctx, QUERY_GET_STATION, stationID, "A"))
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return station, nil
+}
+
+// Checks if a code is taken by any station in the database, deleted ones included.
+func (r *mariaDBRepository) CodeExists(ctx context.Context, code string) (bool, error) {
+	exists := false
+	if err := r.mariadb.QueryRowContext(ctx, QUERY_CODE_EXISTS, code).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// Creates a single station in the database.
+func (r *mariaDBRepository) CreateStation(ctx context.Context, station *Station) (sql.Result, error) {
+	openingHours, err := json.Marshal(station.OpeningHours)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_CREATE_STATION)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Insert one station.
+	a := station.Address
+	result, err := stmt.ExecContext(ctx, station.Code, station.Name, a.Street, a.Number, a.District, a.City, a.Region, a.CountryCode, a.PostalCode,
+		station.Lat, station.Lng, string(openingHours), station.Capacity,
+		station.CreatedUser, station.CreatedAt, station.UpdatedUser, station.UpdatedAt, station.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return result, nil
+}
+
+// Updates a single station in the database.
+func (r *mariaDBRepository) UpdateStation(ctx context.Context, stationID int, station *Station) error {
+	openingHours, err := json.Marshal(station.OpeningHours)
+	if err != nil {
+		return err
+	}
+
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_UPDATE_STATION)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Update one station.
+	a := station.Address
+	_, err = stmt.ExecContext(ctx, station.Name, a.Street, a.Number, a.District, a.City, a.Region, a.CountryCode, a.PostalCode,
+		station.Lat, station.Lng, string(openingHours), station.Capacity, station.UpdatedUser, station.UpdatedAt, stationID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Deletes a single station in the database, as long as it holds no parcels.
+// The station is locked first, parcels arriving at it meanwhile wait for the deletion and then find it deleted.
+func (r *mariaDBRepository) DeleteStation(ctx context.Context, stationID int, station *Station) error {
+	// Begin transaction.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var lockedID int
+	if err = tx.QueryRowContext(ctx, QUERY_LOCK_STATION, stationID).Scan(&lockedID); err != nil {
+		return err
+	}
+
+	var parcels int
+	if err = tx.QueryRowContext(ctx, QUERY_COUNT_PARCELS, stationID).Scan(&parcels); err != nil {
+		return err
+	}
+	if parcels > 0 {
+		return ErrStationNotEmpty
+	}
+
+	// Delete one station.
+	_, err = tx.ExecContext(ctx, QUERY_DELETE_STATION, station.Status, station.UpdatedUser, station.UpdatedAt, stationID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return tx.Commit()
+}
@@ -0,0 +1,157 @@
+package station
+
+import (
+	"context"
+	"delivery-service/internal/address"
+	"delivery-service/internal/calendar"
+	"delivery-service/internal/utils"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Implementation of the repository in this service.
+type stationService struct {
+	stationRepository StationRepository
+}
+
+// Create a new 'service' or 'use-case' for 'Station' entity.
+func NewStationService(r StationRepository) StationService {
+	return &stationService{
+		stationRepository: r,
+	}
+}
+
+// Cleans the address and checks the opening hours of a station.
+func checkStation(stationAddress *address.Address, openingHours map[string]string) error {
+	if err := address.Normalize(stationAddress); err != nil {
+		return err
+	}
+	if _, err := calendar.ParseWeekHours(openingHours); err != nil {
+		return fmt.Errorf("opening hours %v", err)
+	}
+
+	return nil
+}
+
+// Implementation of 'GetStations'.
+func (s *stationService) GetStations(ctx context.Context) (*[]StationOut, error) {
+	return s.stationRepository.GetStations(ctx)
+}
+
+// Implementation of 'GetStation'.
+func (s *stationService) GetStation(ctx context.Context, stationID int) (*StationOut, error) {
+	return s.stationRepository.GetStation(ctx, stationID)
+}
+
+// Implementation of 'CreateStation'.
+func (s *stationService) CreateStation(ctx context.Context, stationInsert *StationInsert) (*StationOut, error) {
+	if err := checkStation(stationInsert.Address, stationInsert.OpeningHours); err != nil {
+		return nil, err
+	}
+
+	// Codes are unique, even among deleted stations.
+	code := strings.ToUpper(stationInsert.Code)
+	exists, err := s.stationRepository.CodeExists(ctx, code)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if exists {
+		return nil, fmt.Errorf("There is already a station with the code %v", code)
+	}
+
+	// Set initialized default data for station.
+	station := &Station{
+		Code:         code,
+		Name:         stationInsert.Name,
+		Address:      stationInsert.Address,
+		Lat:          *stationInsert.Lat,
+		Lng:          *stationInsert.Lng,
+		OpeningHours: stationInsert.OpeningHours,
+		Capacity:     stationInsert.Capacity,
+		CreatedUser:  stationInsert.CreatedUser,
+		CreatedAt:    time.Now(),
+		UpdatedUser:  stationInsert.CreatedUser,
+		UpdatedAt:    time.Now(),
+		Status:       "A",
+	}
+
+	// Pass to the repository layer.
+	result, err := s.stationRepository.CreateStation(ctx, station)
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not insert record")
+	}
+
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, utils.FailOnError(err, "could not get inserted id")
+	}
+
+	return s.stationRepository.GetStation(ctx, int(insertedID))
+}
+
+// Implementation of 'UpdateStation'.
+func (s *stationService) UpdateStation(ctx context.Context, stationID int, stationUpdate *StationUpdate) (*StationOut, error) {
+	// Check if station exists.
+	searchedStation, err := s.stationRepository.GetStation(ctx, stationID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedStation == nil {
+		return nil, fmt.Errorf("There is no station with this ID")
+	}
+
+	if err = checkStation(stationUpdate.Address, stationUpdate.OpeningHours); err != nil {
+		return nil, err
+	}
+
+	// Set value for 'Modified' attribute.
+	station := &Station{
+		Name:         stationUpdate.Name,
+		Address:      stationUpdate.Address,
+		Lat:          *stationUpdate.Lat,
+		Lng:          *stationUpdate.Lng,
+		OpeningHours: stationUpdate.OpeningHours,
+		Capacity:     stationUpdate.Capacity,
+		UpdatedUser:  stationUpdate.UpdatedUser,
+		UpdatedAt:    time.Now(),
+	}
+
+	// Pass to the repository layer.
+	if err = s.stationRepository.UpdateStation(ctx, stationID, station); err != nil {
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	return s.stationRepository.GetStation(ctx, stationID)
+}
+
+// Implementation of 'DeleteStation'.
+func (s *stationService) DeleteStation(ctx context.Context, stationID int, stationDelete *StationDelete) error {
+	// Check if station exists.
+	searchedStation, err := s.stationRepository.GetStation(ctx, stationID)
+	if err != nil {
+		return utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedStation == nil {
+		return fmt.Errorf("There is no station with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	station := &Station{
+		UpdatedUser: stationDelete.UpdatedUser,
+		UpdatedAt:   time.Now(),
+		Status:      "I",
+	}
+
+	// Pass to the repository layer.
+	err = s.stationRepository.DeleteStation(ctx, stationID, station)
+	if err != nil && errors.Is(err, ErrStationNotEmpty) {
+		return err
+	}
+	if err != nil {
+		return utils.FailOnError(err, "could not delete record")
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package utils
+
+import "math"
+
+// Mean radius of the Earth in kilometers.
+const earthRadiusKm = 6371.0
+
+// Haversine func for the great-circle distance in kilometers between two coordinates.
+func Haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	// Convert degrees to radians.
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLng := (lng2 - lng1) * math.Pi / 180
+	rLat1 := lat1 * math.Pi / 180
+	rLat2 := lat2 * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rLat1)*math.Cos(rLat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
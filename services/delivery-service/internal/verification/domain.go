@@ -0,0 +1,31 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Returned when a new code is asked for before the last one can be resent.
+var ErrCodeRecentlySent = errors.New("a verification code was sent recently, please wait before asking for a new one")
+
+// Returned when a code was checked wrong too many times, a new code must be asked for.
+var ErrTooManyAttempts = errors.New("too many wrong verification codes, please ask for a new one")
+
+// Session struct to describe the access granted after checking a code.
+type Session struct {
+	Token     string    `json:"token"`
+	Subject   string    `json:"-"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Our repository will implement these methods.
+// Codes are kept hashed until they expire, a code is consumed once it is checked right.
+// 'GetSession' returns null when the session does not exist or expired.
+type VerificationRepository interface {
+	SaveCode(ctx context.Context, subject string, code string) error
+	CheckCode(ctx context.Context, subject string, code string) (bool, error)
+	CreateSession(ctx context.Context, subject string) (*Session, error)
+	GetSession(ctx context.Context, token string) (*Session, error)
+	CodeTTL() time.Duration
+}
@@ -0,0 +1,194 @@
+package verification
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Represents that we will use Redis keys with expiration for the codes and sessions.
+type redisVerificationRepository struct {
+	redis       *redis.Client
+	keyPrefix   string
+	codeTTL     time.Duration
+	resendAfter time.Duration
+	maxAttempts int64
+	sessionTTL  time.Duration
+}
+
+// Create a new verification store with Redis as the driver.
+func NewVerificationRepository(redisConnection *redis.Client) VerificationRepository {
+	// Define verification settings.
+	keyPrefix := os.Getenv("VERIFICATION_KEY")
+	if keyPrefix == "" {
+		keyPrefix = "delivery-service.verification"
+	}
+	codeTTLCount, err := strconv.Atoi(os.Getenv("VERIFICATION_CODE_TTL"))
+	if err != nil || codeTTLCount <= 0 {
+		codeTTLCount = 600
+	}
+	resendAfterCount, err := strconv.Atoi(os.Getenv("VERIFICATION_CODE_RESEND_SECONDS"))
+	if err != nil || resendAfterCount < 0 {
+		resendAfterCount = 60
+	}
+	maxAttempts, err := strconv.ParseInt(os.Getenv("VERIFICATION_CODE_MAX_ATTEMPTS"), 10, 64)
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	sessionTTLCount, err := strconv.Atoi(os.Getenv("VERIFICATION_SESSION_TTL"))
+	if err != nil || sessionTTLCount <= 0 {
+		sessionTTLCount = 1800
+	}
+
+	return &redisVerificationRepository{
+		redis:       redisConnection,
+		keyPrefix:   keyPrefix,
+		codeTTL:     time.Second * time.Duration(codeTTLCount),
+		resendAfter: time.Second * time.Duration(resendAfterCount),
+		maxAttempts: maxAttempts,
+		sessionTTL:  time.Second * time.Duration(sessionTTLCount),
+	}
+}
+
+// Generates a random code of six digits.
+func NewCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// Saves a new code of a subject, replacing the last one and its wrong attempts.
+func (r *redisVerificationRepository) SaveCode(ctx context.Context, subject string, code string) error {
+	// Codes can not be resent right away.
+	if r.resendAfter > 0 {
+		allowed, err := r.redis.SetNX(ctx, r.key("resend", subject), 1, r.resendAfter).Result()
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrCodeRecentlySent
+		}
+	}
+
+	pipe := r.redis.TxPipeline()
+	pipe.Set(ctx, r.key("code", subject), hashCode(subject, code), r.codeTTL)
+	pipe.Del(ctx, r.key("attempts", subject))
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// Checks a code of a subject, the code is removed when it is right or after too many wrong attempts.
+// Every attempt is counted before the code is compared, parallel attempts can not go past the limit.
+func (r *redisVerificationRepository) CheckCode(ctx context.Context, subject string, code string) (bool, error) {
+	codeKey := r.key("code", subject)
+	attemptsKey := r.key("attempts", subject)
+
+	// Count the attempt, it lives as long as the code.
+	pipe := r.redis.TxPipeline()
+	attempts := pipe.Incr(ctx, attemptsKey)
+	pipe.Expire(ctx, attemptsKey, r.codeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	if attempts.Val() > r.maxAttempts {
+		if err := r.redis.Del(ctx, codeKey, attemptsKey).Err(); err != nil {
+			return false, err
+		}
+		return false, ErrTooManyAttempts
+	}
+
+	stored, err := r.redis.Get(ctx, codeKey).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashCode(subject, code))) == 1 {
+		return true, r.redis.Del(ctx, codeKey, attemptsKey).Err()
+	}
+
+	// The last wrong attempt allowed removes the code.
+	if attempts.Val() >= r.maxAttempts {
+		if err = r.redis.Del(ctx, codeKey, attemptsKey).Err(); err != nil {
+			return false, err
+		}
+		return false, ErrTooManyAttempts
+	}
+
+	return false, nil
+}
+
+// Creates a session of a subject with a random token.
+func (r *redisVerificationRepository) CreateSession(ctx context.Context, subject string) (*Session, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+
+	session := &Session{
+		Token:     hex.EncodeToString(random),
+		Subject:   subject,
+		ExpiresAt: time.Now().Add(r.sessionTTL),
+	}
+	if err := r.redis.Set(ctx, r.key("session", session.Token), subject, r.sessionTTL).Err(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Gets the session of a token.
+func (r *redisVerificationRepository) GetSession(ctx context.Context, token string) (*Session, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	sessionKey := r.key("session", token)
+	pipe := r.redis.TxPipeline()
+	subject := pipe.Get(ctx, sessionKey)
+	ttl := pipe.TTL(ctx, sessionKey)
+	_, err := pipe.Exec(ctx)
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		Token:     token,
+		Subject:   subject.Val(),
+		ExpiresAt: time.Now().Add(ttl.Val()),
+	}, nil
+}
+
+// Time a code can be used after it is sent.
+func (r *redisVerificationRepository) CodeTTL() time.Duration {
+	return r.codeTTL
+}
+
+// Key of a kind of value of a subject.
+func (r *redisVerificationRepository) key(kind string, subject string) string {
+	return r.keyPrefix + "." + kind + "." + subject
+}
+
+// Codes are not stored in clear, the subject salts them.
+func hashCode(subject string, code string) string {
+	sum := sha256.Sum256([]byte(subject + "|" + code))
+	return hex.EncodeToString(sum[:])
+}
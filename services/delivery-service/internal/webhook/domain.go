@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/internal/outbox"
+	"encoding/json"
+	"time"
+)
+
+// States of a delivery.
+const (
+	DELIVERY_STATUS_PENDING   = "pending"
+	DELIVERY_STATUS_DELIVERED = "delivered"
+	DELIVERY_STATUS_DEAD      = "dead"
+)
+
+// Subscription struct to describe Subscription object.
+type Subscription struct {
+	ID          int       `db:"id"`
+	Application string    `db:"application"`
+	URL         string    `db:"url"`
+	EventFilter string    `db:"event_filter"`
+	Secret      string    `db:"secret"`
+	CreatedUser string    `db:"created_user"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedUser string    `db:"updated_user"`
+	UpdatedAt   time.Time `db:"updated_at"`
+	Status      string    `db:"status"`
+}
+
+// SubscriptionInsert struct to describe register a new subscription.
+type SubscriptionInsert struct {
+	URL         string   `json:"url" validate:"required,url,lte=500"`
+	Events      []string `json:"events" validate:"required,min=1,dive,eq=OrderCreated|eq=OrderStatusChanged|eq=OrderCancelled"`
+	Secret      string   `json:"secret" validate:"required,gte=16,lte=200"`
+	Application string   `json:"-"`
+	CreatedUser string   `json:"createdUser" validate:"required,lte=200"`
+}
+
+// SubscriptionDelete struct to describe delete subscription.
+type SubscriptionDelete struct {
+	UpdatedUser string `json:"updatedUser" validate:"required,lte=200"`
+}
+
+type SubscriptionOut struct {
+	ID          int       `json:"id"`
+	Application string    `json:"application"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	CreatedUser string    `json:"created_user"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedUser string    `json:"updated_user"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Status      string    `json:"status"`
+}
+
+// Delivery struct to describe the delivery of one event to one subscription.
+type Delivery struct {
+	ID               int       `db:"id"`
+	SubscriptionID   int       `db:"subscription_id"`
+	EventID          int       `db:"event_id"`
+	EventType        string    `db:"event_type"`
+	Payload          string    `db:"payload"`
+	DeliveryStatus   string    `db:"delivery_status"`
+	Attempts         int       `db:"attempts"`
+	NextAttemptAt    time.Time `db:"next_attempt_at"`
+	LastResponseCode int       `db:"last_response_code"`
+	LastError        string    `db:"last_error"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+	URL              string    `db:"url"`
+	Secret           string    `db:"secret"`
+}
+
+// DeliveryLog struct to describe a single attempt of a delivery.
+type DeliveryLog struct {
+	ID           int       `db:"id"`
+	DeliveryID   int       `db:"delivery_id"`
+	Attempt      int       `db:"attempt"`
+	ResponseCode int       `db:"response_code"`
+	ErrorMessage string    `db:"error_message"`
+	DurationMs   int       `db:"duration_ms"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type DeliveryLogOut struct {
+	Attempt      int       `json:"attempt"`
+	ResponseCode int       `json:"responseCode"`
+	ErrorMessage string    `json:"errorMessage,omitempty"`
+	DurationMs   int       `json:"durationMs"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type DeliveryOut struct {
+	ID               int               `json:"id"`
+	SubscriptionID   int               `json:"subscriptionId"`
+	EventID          int               `json:"eventId"`
+	EventType        string            `json:"eventType"`
+	DeliveryStatus   string            `json:"deliveryStatus"`
+	Attempts         int               `json:"attempts"`
+	NextAttemptAt    time.Time         `json:"nextAttemptAt"`
+	LastResponseCode int               `json:"lastResponseCode"`
+	LastError        string            `json:"lastError,omitempty"`
+	Logs             *[]DeliveryLogOut `json:"logs,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// Envelope of every webhook body, the payload of the event goes in 'data'.
+type webhookEnvelope struct {
+	EventID       int             `json:"eventId"`
+	EventType     string          `json:"eventType"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   int             `json:"aggregateId"`
+	Application   string          `json:"application"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Our repository will implement these methods.
+// 'ClaimDueDeliveries' moves the next attempt of the due deliveries to 'claimUntil', so they are sent once.
+type WebhookRepository interface {
+	GetSubscriptions(ctx context.Context, application string) (*[]Subscription, error)
+	GetSubscription(ctx context.Context, subscriptionID int, application string) (*Subscription, error)
+	CreateSubscription(ctx context.Context, subscription *Subscription) (sql.Result, error)
+	DeleteSubscription(ctx context.Context, subscriptionID int, subscription *Subscription) error
+	CreateDeliveries(ctx context.Context, deliveries *[]Delivery) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, claimUntil time.Time, limit int) (*[]Delivery, error)
+	GetDeliveries(ctx context.Context, subscriptionID int) (*[]DeliveryOut, error)
+	GetDelivery(ctx context.Context, deliveryID int, application string) (*DeliveryOut, error)
+	GetDeliveryLogs(ctx context.Context, deliveryID int) (*[]DeliveryLogOut, error)
+	UpdateDelivery(ctx context.Context, delivery *Delivery, deliveryLog *DeliveryLog) error
+	RedeliverDelivery(ctx context.Context, deliveryID int, now time.Time) error
+}
+
+// Our use-case or service will implement these methods.
+// It also acts as an outbox sink, turning order events into deliveries.
+type WebhookService interface {
+	outbox.Publisher
+	GetSubscriptions(ctx context.Context, application string) (*[]SubscriptionOut, error)
+	CreateSubscription(ctx context.Context, subscriptionInsert *SubscriptionInsert) (*SubscriptionOut, error)
+	DeleteSubscription(ctx context.Context, subscriptionID int, application string, subscriptionDelete *SubscriptionDelete) error
+	GetDeliveries(ctx context.Context, subscriptionID int, application string) (*[]DeliveryOut, error)
+	GetDelivery(ctx context.Context, deliveryID int, application string) (*DeliveryOut, error)
+	RedeliverDelivery(ctx context.Context, deliveryID int, application string) (*DeliveryOut, error)
+	StartDispatcher()
+}
@@ -0,0 +1,308 @@
+package webhook
+
+import (
+	"context"
+	"delivery-service/internal/middleware"
+	"delivery-service/internal/utils"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Represents our handler with our use-case / service.
+type WebhookHandler struct {
+	webhookService WebhookService
+}
+
+// Creates a new handler.
+func NewWebhookHandler(webhookRoute fiber.Router, ws WebhookService) {
+	// Create a handler based on our created service / use-case.
+	handler := &WebhookHandler{
+		webhookService: ws,
+	}
+
+	// We will restrict this route with our JWT middleware.
+	// Subscriptions are scoped to the application of the token.
+	webhookRoute.Use(middleware.JWTProtected(), middleware.ExtractTokenMetadata)
+
+	// Declare routing endpoints for general routes.
+	webhookRoute.Get("", handler.getSubscriptions)
+	webhookRoute.Post("", handler.createSubscription)
+	webhookRoute.Delete("/:subscriptionID", handler.deleteSubscription)
+
+	// Declare routing endpoints for deliveries.
+	webhookRoute.Get("/:subscriptionID/deliveries", handler.getDeliveries)
+	webhookRoute.Get("/deliveries/:deliveryID", handler.getDelivery)
+	webhookRoute.Post("/deliveries/:deliveryID/redeliver", handler.redeliverDelivery)
+}
+
+// Gets all subscriptions of the application.
+func (h *WebhookHandler) getSubscriptions(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Get all subscriptions.
+	subscriptions, err := h.webhookService.GetSubscriptions(customContext, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "subscriptions obtained succesfully",
+		"http_code": fiber.StatusOK,
+		"data":      subscriptions,
+	})
+}
+
+// Creates a single subscription.
+func (h *WebhookHandler) createSubscription(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables and Create a new subscription struct.
+	subscriptionInsert := &SubscriptionInsert{}
+
+	// Parse request body.
+	if err := c.BodyParser(subscriptionInsert); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Subscription model.
+	validate := utils.NewValidator()
+
+	// Validate subscription fields.
+	if err := validate.Struct(subscriptionInsert); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create one subscription.
+	subscriptionInsert.Application = c.Locals("application").(string)
+	subscription, err := h.webhookService.CreateSubscription(customContext, subscriptionInsert)
+	if err != nil && errors.Is(err, ErrForbiddenTarget) {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusCreated).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Subscription has been created successfully!",
+		"http_code": fiber.StatusCreated,
+		"data":      subscription,
+	})
+}
+
+// Deletes a single subscription.
+func (h *WebhookHandler) deleteSubscription(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	subscriptionDelete := &SubscriptionDelete{}
+
+	// Fetch parameter.
+	targetedSubscriptionID, err := c.ParamsInt("subscriptionID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid subscription ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Parse request body.
+	if err := c.BodyParser(subscriptionDelete); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Subscription model.
+	validate := utils.NewValidator()
+
+	// Validate subscription fields.
+	if err := validate.Struct(subscriptionDelete); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Delete one subscription.
+	err = h.webhookService.DeleteSubscription(customContext, targetedSubscriptionID, c.Locals("application").(string), subscriptionDelete)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Subscription has been deleted successfully!",
+		"http_code": fiber.StatusOK,
+	})
+}
+
+// Gets the deliveries of a subscription.
+func (h *WebhookHandler) getDeliveries(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedSubscriptionID, err := c.ParamsInt("subscriptionID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid subscription ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get all deliveries.
+	deliveries, err := h.webhookService.GetDeliveries(customContext, targetedSubscriptionID, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if deliveries == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("subscription of ID {%d} does not exist.", targetedSubscriptionID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "deliveries obtained succesfully",
+		"http_code": fiber.StatusOK,
+		"data":      deliveries,
+	})
+}
+
+// Gets a single delivery with the log of its attempts.
+func (h *WebhookHandler) getDelivery(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedDeliveryID, err := c.ParamsInt("deliveryID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid delivery ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get one delivery.
+	delivery, err := h.webhookService.GetDelivery(customContext, targetedDeliveryID, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if delivery == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("delivery of ID {%d} does not exist.", targetedDeliveryID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "delivery obtained succesfully",
+		"http_code": fiber.StatusOK,
+		"data":      delivery,
+	})
+}
+
+// Queues a single delivery again.
+func (h *WebhookHandler) redeliverDelivery(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Fetch parameter.
+	targetedDeliveryID, err := c.ParamsInt("deliveryID")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "Please specify a valid delivery ID!",
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Redeliver one delivery.
+	delivery, err := h.webhookService.RedeliverDelivery(customContext, targetedDeliveryID, c.Locals("application").(string))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	if delivery == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   fmt.Sprintf("delivery of ID {%d} does not exist.", targetedDeliveryID),
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return result.
+	return c.Status(fiber.StatusAccepted).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Delivery has been queued again successfully!",
+		"http_code": fiber.StatusAccepted,
+		"data":      delivery,
+	})
+}
@@ -0,0 +1,355 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Queries that we will use.
+const (
+	QUERY_GET_SUBSCRIPTIONS = "SELECT id, application, url, event_filter, secret, created_user, created_at, updated_user, updated_at, status " +
+		"FROM webhook_subscription WHERE application = ? and status = ?"
+	QUERY_GET_SUBSCRIPTION = "SELECT id, application, url, event_filter, secret, created_user, created_at, updated_user, updated_at, status " +
+		"FROM webhook_subscription WHERE id = ? and application = ? and status = ?"
+	QUERY_CREATE_SUBSCRIPTION = "INSERT INTO webhook_subscription (application, url, event_filter, secret, created_user, created_at, updated_user, updated_at, status) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_DELETE_SUBSCRIPTION = "UPDATE webhook_subscription SET status = ?, updated_user = ?, updated_at = ? WHERE id = ?"
+	QUERY_CREATE_DELIVERY     = "INSERT IGNORE INTO webhook_delivery (subscription_id, event_id, event_type, payload, delivery_status, attempts, next_attempt_at, last_response_code, last_error, created_at, updated_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	QUERY_LOCK_DUE_DELIVERIES = "SELECT id FROM webhook_delivery WHERE delivery_status = ? and next_attempt_at <= ? " +
+		"order by next_attempt_at asc limit ? FOR UPDATE SKIP LOCKED"
+	QUERY_CLAIM_DELIVERIES   = "UPDATE webhook_delivery SET next_attempt_at = ? WHERE id IN (%s)"
+	QUERY_GET_DUE_DELIVERIES = "SELECT d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.delivery_status, d.attempts, d.next_attempt_at, d.last_response_code, d.last_error, d.created_at, d.updated_at, " +
+		"s.url, s.secret FROM webhook_delivery d INNER JOIN webhook_subscription s ON s.id = d.subscription_id " +
+		"WHERE d.id IN (%s) and s.status = ? order by d.id asc"
+	QUERY_GET_DELIVERIES = "SELECT id, subscription_id, event_id, event_type, delivery_status, attempts, next_attempt_at, last_response_code, last_error, created_at, updated_at " +
+		"FROM webhook_delivery WHERE subscription_id = ? order by id desc"
+	QUERY_GET_DELIVERY = "SELECT d.id, d.subscription_id, d.event_id, d.event_type, d.delivery_status, d.attempts, d.next_attempt_at, d.last_response_code, d.last_error, d.created_at, d.updated_at " +
+		"FROM webhook_delivery d INNER JOIN webhook_subscription s ON s.id = d.subscription_id WHERE d.id = ? and s.application = ?"
+	QUERY_GET_DELIVERY_LOGS = "SELECT attempt, response_code, error_message, duration_ms, created_at " +
+		"FROM webhook_delivery_log WHERE delivery_id = ? order by id asc"
+	QUERY_UPDATE_DELIVERY     = "UPDATE webhook_delivery SET delivery_status = ?, attempts = ?, next_attempt_at = ?, last_response_code = ?, last_error = ?, updated_at = ? WHERE id = ?"
+	QUERY_CREATE_DELIVERY_LOG = "INSERT INTO webhook_delivery_log (delivery_id, attempt, response_code, error_message, duration_ms, created_at) " +
+		"VALUES (?, ?, ?, ?, ?, ?)"
+	QUERY_REDELIVER_DELIVERY = "UPDATE webhook_delivery SET delivery_status = ?, next_attempt_at = ?, updated_at = ? WHERE id = ?"
+)
+
+// Represents that we will use MariaDB in order to implement the methods.
+type mariaDBRepository struct {
+	mariadb *sql.DB
+}
+
+// Create a new repository with MariaDB as the driver.
+func NewWebhookRepository(mariaDBConnection *sql.DB) WebhookRepository {
+	return &mariaDBRepository{
+		mariadb: mariaDBConnection,
+	}
+}
+
+// Gets all subscriptions of an application in the database.
+func (r *mariaDBRepository) GetSubscriptions(ctx context.Context, application string) (*[]Subscription, error) {
+	// Initialize variables.
+	var subscriptions []Subscription
+
+	// Get all subscriptions.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_SUBSCRIPTIONS, application, "A")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'subscriptions' array.
+	for res.Next() {
+		subscription := &Subscription{}
+		err = res.Scan(&subscription.ID, &subscription.Application, &subscription.URL, &subscription.EventFilter, &subscription.Secret,
+			&subscription.CreatedUser, &subscription.CreatedAt, &subscription.UpdatedUser, &subscription.UpdatedAt, &subscription.Status)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	// Return all of our subscriptions.
+	return &subscriptions, nil
+}
+
+// Gets a single subscription of an application in the database.
+func (r *mariaDBRepository) GetSubscription(ctx context.Context, subscriptionID int, application string) (*Subscription, error) {
+	// Initialize variable.
+	subscription := &Subscription{}
+
+	// Prepare SQL to get one subscription.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_GET_SUBSCRIPTION)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Get one subscription and insert it to the 'subscription' struct.
+	// If it's empty, return null.
+	err = stmt.QueryRowContext(ctx, subscriptionID, application, "A").Scan(&subscription.ID, &subscription.Application, &subscription.URL,
+		&subscription.EventFilter, &subscription.Secret, &subscription.CreatedUser, &subscription.CreatedAt, &subscription.UpdatedUser,
+		&subscription.UpdatedAt, &subscription.Status)
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return subscription, nil
+}
+
+// Creates a single subscription in the database.
+func (r *mariaDBRepository) CreateSubscription(ctx context.Context, subscription *Subscription) (sql.Result, error) {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_CREATE_SUBSCRIPTION)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Insert one subscription.
+	result, err := stmt.ExecContext(ctx, subscription.Application, subscription.URL, subscription.EventFilter, subscription.Secret,
+		subscription.CreatedUser, subscription.CreatedAt, subscription.UpdatedUser, subscription.UpdatedAt, subscription.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return result, nil
+}
+
+// Deletes a single subscription in the database.
+func (r *mariaDBRepository) DeleteSubscription(ctx context.Context, subscriptionID int, subscription *Subscription) error {
+	// Prepare context to be used.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_DELETE_SUBSCRIPTION)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Delete one subscription.
+	_, err = stmt.ExecContext(ctx, subscription.Status, subscription.UpdatedUser, subscription.UpdatedAt, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return nil
+}
+
+// Creates the deliveries of an event in the database.
+// An event already delivered to a subscription is ignored, so publishing it twice is harmless.
+func (r *mariaDBRepository) CreateDeliveries(ctx context.Context, deliveries *[]Delivery) error {
+	// Begin transaction, an event is fanned out to every subscription or to none.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Prepare context to be used.
+	stmt, err := tx.PrepareContext(ctx, QUERY_CREATE_DELIVERY)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	// Insert all deliveries.
+	for _, delivery := range *deliveries {
+		_, err = stmt.ExecContext(ctx, delivery.SubscriptionID, delivery.EventID, delivery.EventType, delivery.Payload, delivery.DeliveryStatus,
+			delivery.Attempts, delivery.NextAttemptAt, delivery.LastResponseCode, delivery.LastError, delivery.CreatedAt, delivery.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Return empty.
+	return tx.Commit()
+}
+
+// Claims the pending deliveries whose next attempt is due, skipping the ones another dispatcher is claiming.
+func (r *mariaDBRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, claimUntil time.Time, limit int) (*[]Delivery, error) {
+	// Initialize variables.
+	var deliveries []Delivery
+	var args []interface{}
+
+	// Begin transaction, the due deliveries stay locked until their next attempt is moved.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.QueryContext(ctx, QUERY_LOCK_DUE_DELIVERIES, DELIVERY_STATUS_PENDING, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	for res.Next() {
+		var deliveryID int
+		if err = res.Scan(&deliveryID); err != nil {
+			res.Close()
+			return nil, err
+		}
+		args = append(args, deliveryID)
+	}
+	res.Close()
+	if err = res.Err(); err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return &deliveries, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(args)), ",")
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(QUERY_CLAIM_DELIVERIES, placeholders), append([]interface{}{claimUntil}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the claimed deliveries of active subscriptions.
+	res, err = tx.QueryContext(ctx, fmt.Sprintf(QUERY_GET_DUE_DELIVERIES, placeholders), append(args, "A")...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'deliveries' array.
+	for res.Next() {
+		delivery := &Delivery{}
+		err = res.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &delivery.Payload, &delivery.DeliveryStatus,
+			&delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastResponseCode, &delivery.LastError, &delivery.CreatedAt, &delivery.UpdatedAt,
+			&delivery.URL, &delivery.Secret)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	res.Close()
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Return all of our deliveries.
+	return &deliveries, nil
+}
+
+// Gets the deliveries of a subscription in the database.
+func (r *mariaDBRepository) GetDeliveries(ctx context.Context, subscriptionID int) (*[]DeliveryOut, error) {
+	// Initialize variables.
+	var deliveries []DeliveryOut
+
+	// Get all deliveries.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_DELIVERIES, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'deliveries' array.
+	for res.Next() {
+		delivery := &DeliveryOut{}
+		err = res.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &delivery.DeliveryStatus, &delivery.Attempts,
+			&delivery.NextAttemptAt, &delivery.LastResponseCode, &delivery.LastError, &delivery.CreatedAt, &delivery.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	// Return all of our deliveries.
+	return &deliveries, nil
+}
+
+// Gets a single delivery of an application in the database.
+func (r *mariaDBRepository) GetDelivery(ctx context.Context, deliveryID int, application string) (*DeliveryOut, error) {
+	// Initialize variable.
+	delivery := &DeliveryOut{}
+
+	// Prepare SQL to get one delivery.
+	stmt, err := r.mariadb.PrepareContext(ctx, QUERY_GET_DELIVERY)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Get one delivery and insert it to the 'delivery' struct.
+	// If it's empty, return null.
+	err = stmt.QueryRowContext(ctx, deliveryID, application).Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType,
+		&delivery.DeliveryStatus, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastResponseCode, &delivery.LastError,
+		&delivery.CreatedAt, &delivery.UpdatedAt)
+	if err != nil && err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result.
+	return delivery, nil
+}
+
+// Gets the attempts of a delivery in the database.
+func (r *mariaDBRepository) GetDeliveryLogs(ctx context.Context, deliveryID int) (*[]DeliveryLogOut, error) {
+	// Initialize variables.
+	var deliveryLogs []DeliveryLogOut
+
+	// Get all attempts.
+	res, err := r.mariadb.QueryContext(ctx, QUERY_GET_DELIVERY_LOGS, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// Scan all of the results to the 'deliveryLogs' array.
+	for res.Next() {
+		deliveryLog := &DeliveryLogOut{}
+		err = res.Scan(&deliveryLog.Attempt, &deliveryLog.ResponseCode, &deliveryLog.ErrorMessage, &deliveryLog.DurationMs, &deliveryLog.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveryLogs = append(deliveryLogs, *deliveryLog)
+	}
+
+	// Return all of our attempts.
+	return &deliveryLogs, nil
+}
+
+// Stores the outcome of an attempt together with its log.
+func (r *mariaDBRepository) UpdateDelivery(ctx context.Context, delivery *Delivery, deliveryLog *DeliveryLog) error {
+	// Begin transaction.
+	tx, err := r.mariadb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Update one delivery.
+	_, err = tx.ExecContext(ctx, QUERY_UPDATE_DELIVERY, delivery.DeliveryStatus, delivery.Attempts, delivery.NextAttemptAt, delivery.LastResponseCode,
+		delivery.LastError, delivery.UpdatedAt, delivery.ID)
+	if err != nil {
+		return err
+	}
+
+	// Insert one log.
+	_, err = tx.ExecContext(ctx, QUERY_CREATE_DELIVERY_LOG, deliveryLog.DeliveryID, deliveryLog.Attempt, deliveryLog.ResponseCode,
+		deliveryLog.ErrorMessage, deliveryLog.DurationMs, deliveryLog.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	// Return empty.
+	return tx.Commit()
+}
+
+// Puts a single delivery back in the queue.
+func (r *mariaDBRepository) RedeliverDelivery(ctx context.Context, deliveryID int, now time.Time) error {
+	_, err := r.mariadb.ExecContext(ctx, QUERY_REDELIVER_DELIVERY, DELIVERY_STATUS_PENDING, now, now, deliveryID)
+	return err
+}
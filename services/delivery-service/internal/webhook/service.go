@@ -0,0 +1,363 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"delivery-service/internal/outbox"
+	"delivery-service/internal/utils"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Implementation of the repository in this service.
+type webhookService struct {
+	webhookRepository WebhookRepository
+	client            *http.Client
+	maxAttempts       int
+	retryBase         time.Duration
+}
+
+// Create a new 'service' or 'use-case' for 'Webhook' entity.
+func NewWebhookService(r WebhookRepository) WebhookService {
+	// Define delivery settings.
+	timeoutSecondsCount, _ := strconv.Atoi(os.Getenv("WEBHOOK_TIMEOUT"))
+	if timeoutSecondsCount <= 0 {
+		timeoutSecondsCount = 10
+	}
+
+	maxAttempts, _ := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+
+	retryBaseSecondsCount, _ := strconv.Atoi(os.Getenv("WEBHOOK_RETRY_BASE_SECONDS"))
+	if retryBaseSecondsCount <= 0 {
+		retryBaseSecondsCount = 30
+	}
+
+	return &webhookService{
+		webhookRepository: r,
+		client:            newWebhookClient(time.Second * time.Duration(timeoutSecondsCount)),
+		maxAttempts:       maxAttempts,
+		retryBase:         time.Second * time.Duration(retryBaseSecondsCount),
+	}
+}
+
+// Implementation of 'GetSubscriptions'.
+func (s *webhookService) GetSubscriptions(ctx context.Context, application string) (*[]SubscriptionOut, error) {
+	subscriptions, err := s.webhookRepository.GetSubscriptions(ctx, application)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionsOut := make([]SubscriptionOut, 0, len(*subscriptions))
+	for _, subscription := range *subscriptions {
+		subscriptionsOut = append(subscriptionsOut, *toSubscriptionOut(&subscription))
+	}
+
+	return &subscriptionsOut, nil
+}
+
+// Implementation of 'CreateSubscription'.
+func (s *webhookService) CreateSubscription(ctx context.Context, subscriptionInsert *SubscriptionInsert) (*SubscriptionOut, error) {
+	// Webhooks are only sent to public addresses.
+	if err := checkTarget(ctx, subscriptionInsert.URL); err != nil {
+		return nil, err
+	}
+
+	// Create a new subscription struct.
+	subscription := &Subscription{}
+
+	// Set initialized default data for subscription:
+	subscription.Application = subscriptionInsert.Application
+	subscription.URL = subscriptionInsert.URL
+	subscription.EventFilter = strings.Join(subscriptionInsert.Events, ",")
+	subscription.Secret = subscriptionInsert.Secret
+	subscription.CreatedUser = subscriptionInsert.CreatedUser
+	subscription.CreatedAt = time.Now()
+	subscription.Status = "A"
+
+	// Pass to the repository layer.
+	result, err := s.webhookRepository.CreateSubscription(ctx, subscription)
+	if err != nil {
+		return nil, utils.FailOnError(err, "problems creating the record")
+	}
+
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return nil, utils.FailOnError(err, "it is not possible to retrieve the id from the record")
+	}
+
+	subscription.ID = int(insertedID)
+	return toSubscriptionOut(subscription), nil
+}
+
+// Implementation of 'DeleteSubscription'.
+func (s *webhookService) DeleteSubscription(ctx context.Context, subscriptionID int, application string, subscriptionDelete *SubscriptionDelete) error {
+	// Check if subscription exists.
+	searchedSubscription, err := s.webhookRepository.GetSubscription(ctx, subscriptionID, application)
+	if err != nil {
+		return utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedSubscription == nil {
+		return fmt.Errorf("There is no subscription with this ID")
+	}
+
+	// Set value for 'Modified' attribute.
+	subscription := &Subscription{
+		UpdatedUser: subscriptionDelete.UpdatedUser,
+		UpdatedAt:   time.Now(),
+		Status:      "I",
+	}
+
+	// Pass to the repository layer.
+	if err = s.webhookRepository.DeleteSubscription(ctx, subscriptionID, subscription); err != nil {
+		return utils.FailOnError(err, "could not update record")
+	}
+
+	return nil
+}
+
+// Implementation of 'GetDeliveries'.
+func (s *webhookService) GetDeliveries(ctx context.Context, subscriptionID int, application string) (*[]DeliveryOut, error) {
+	// Check if subscription exists.
+	searchedSubscription, err := s.webhookRepository.GetSubscription(ctx, subscriptionID, application)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if searchedSubscription == nil {
+		return nil, nil
+	}
+
+	return s.webhookRepository.GetDeliveries(ctx, subscriptionID)
+}
+
+// Implementation of 'GetDelivery'.
+func (s *webhookService) GetDelivery(ctx context.Context, deliveryID int, application string) (*DeliveryOut, error) {
+	delivery, err := s.webhookRepository.GetDelivery(ctx, deliveryID, application)
+	if err != nil || delivery == nil {
+		return delivery, err
+	}
+
+	// Attach the log of every attempt.
+	deliveryLogs, err := s.webhookRepository.GetDeliveryLogs(ctx, deliveryID)
+	if err != nil {
+		return nil, utils.FailOnError(err, "delivery logs could not be retrieved")
+	}
+
+	delivery.Logs = deliveryLogs
+	return delivery, nil
+}
+
+// Implementation of 'RedeliverDelivery'.
+// The delivery goes back to pending, including dead ones, and is sent on the next dispatch.
+func (s *webhookService) RedeliverDelivery(ctx context.Context, deliveryID int, application string) (*DeliveryOut, error) {
+	// Check if delivery exists.
+	delivery, err := s.webhookRepository.GetDelivery(ctx, deliveryID, application)
+	if err != nil {
+		return nil, utils.FailOnError(err, "information could not be retrieved")
+	}
+	if delivery == nil {
+		return nil, nil
+	}
+
+	// Pass to the repository layer.
+	if err = s.webhookRepository.RedeliverDelivery(ctx, deliveryID, time.Now()); err != nil {
+		return nil, utils.FailOnError(err, "could not update record")
+	}
+
+	return s.GetDelivery(ctx, deliveryID, application)
+}
+
+// Implementation of 'Publish'.
+// Creates one delivery for every subscription of the application interested in the event.
+func (s *webhookService) Publish(ctx context.Context, event *outbox.Event) error {
+	subscriptions, err := s.webhookRepository.GetSubscriptions(ctx, event.Application)
+	if err != nil {
+		return err
+	}
+
+	// Body sent to the subscribers, kept as it is for the retries.
+	body, err := json.Marshal(&webhookEnvelope{
+		EventID:       event.ID,
+		EventType:     event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Application:   event.Application,
+		CreatedAt:     event.CreatedAt,
+		Data:          json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []Delivery
+	for _, subscription := range *subscriptions {
+		if !subscribedTo(&subscription, event.EventType) {
+			continue
+		}
+
+		deliveries = append(deliveries, Delivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.EventType,
+			Payload:        string(body),
+			DeliveryStatus: DELIVERY_STATUS_PENDING,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.webhookRepository.CreateDeliveries(ctx, &deliveries)
+}
+
+// Implementation of 'StartDispatcher'.
+// Launches the goroutine that sends the due deliveries.
+func (s *webhookService) StartDispatcher() {
+	// Define dispatcher settings.
+	intervalSecondsCount, _ := strconv.Atoi(os.Getenv("WEBHOOK_DISPATCH_INTERVAL"))
+	if intervalSecondsCount <= 0 {
+		intervalSecondsCount = 5
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Second * time.Duration(intervalSecondsCount))
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.dispatchDueDeliveries(context.Background())
+		}
+	}()
+}
+
+// Sends every delivery whose next attempt is due.
+// The deliveries are claimed until all of them could have been sent, other dispatchers skip them meanwhile.
+func (s *webhookService) dispatchDueDeliveries(ctx context.Context) {
+	batchSize := 100
+	now := time.Now()
+	deliveries, err := s.webhookRepository.ClaimDueDeliveries(ctx, now, now.Add(s.client.Timeout*time.Duration(batchSize)), batchSize)
+	if err != nil {
+		log.Printf("Oops... Webhook deliveries could not be retrieved! Reason: %v", err)
+		return
+	}
+
+	for _, delivery := range *deliveries {
+		s.attemptDelivery(ctx, &delivery)
+	}
+}
+
+// Sends a single delivery and schedules its retry when it fails.
+func (s *webhookService) attemptDelivery(ctx context.Context, delivery *Delivery) {
+	start := time.Now()
+	responseCode, err := s.send(ctx, delivery)
+
+	delivery.Attempts++
+	delivery.LastResponseCode = responseCode
+	delivery.LastError = ""
+	delivery.UpdatedAt = time.Now()
+
+	switch {
+	case err == nil:
+		delivery.DeliveryStatus = DELIVERY_STATUS_DELIVERED
+	case delivery.Attempts >= s.maxAttempts:
+		delivery.DeliveryStatus = DELIVERY_STATUS_DEAD
+		delivery.LastError = err.Error()
+	default:
+		// Exponential backoff: base, 2*base, 4*base...
+		backoff := time.Duration(float64(s.retryBase) * math.Pow(2, float64(delivery.Attempts-1)))
+		delivery.NextAttemptAt = delivery.UpdatedAt.Add(backoff)
+		delivery.LastError = err.Error()
+	}
+
+	deliveryLog := &DeliveryLog{
+		DeliveryID:   delivery.ID,
+		Attempt:      delivery.Attempts,
+		ResponseCode: responseCode,
+		ErrorMessage: delivery.LastError,
+		DurationMs:   int(time.Since(start).Milliseconds()),
+		CreatedAt:    delivery.UpdatedAt,
+	}
+
+	if err := s.webhookRepository.UpdateDelivery(ctx, delivery, deliveryLog); err != nil {
+		log.Printf("Oops... Webhook delivery %d could not be updated! Reason: %v", delivery.ID, err)
+	}
+}
+
+// Posts the signed body of a delivery to its subscriber.
+// Any status outside 2xx is considered a failure.
+func (s *webhookService) send(ctx context.Context, delivery *Delivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set("X-Webhook-Delivery", strconv.Itoa(delivery.ID))
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", "sha256="+Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("subscriber answered with status %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+// Sign func for computing the HMAC-SHA256 signature of a webhook body.
+// Subscribers verify it by signing "<timestamp>.<body>" with their secret.
+func Sign(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Checks whether a subscription listens to an event type.
+func subscribedTo(subscription *Subscription, eventType string) bool {
+	for _, filter := range strings.Split(subscription.EventFilter, ",") {
+		if filter == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Shapes a subscription for the output, the secret is never returned.
+func toSubscriptionOut(subscription *Subscription) *SubscriptionOut {
+	return &SubscriptionOut{
+		ID:          subscription.ID,
+		Application: subscription.Application,
+		URL:         subscription.URL,
+		Events:      strings.Split(subscription.EventFilter, ","),
+		CreatedUser: subscription.CreatedUser,
+		CreatedAt:   subscription.CreatedAt,
+		UpdatedUser: subscription.UpdatedUser,
+		UpdatedAt:   subscription.UpdatedAt,
+		Status:      subscription.Status,
+	}
+}
@@ -0,0 +1,457 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"delivery-service/internal/outbox"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// In-memory repository, enough to drive the service without a database.
+type memoryRepository struct {
+	mu            sync.Mutex
+	subscriptions []Subscription
+	deliveries    map[int]*Delivery
+	logs          map[int][]DeliveryLogOut
+}
+
+func newMemoryRepository(subscriptions ...Subscription) *memoryRepository {
+	return &memoryRepository{
+		subscriptions: subscriptions,
+		deliveries:    make(map[int]*Delivery),
+		logs:          make(map[int][]DeliveryLogOut),
+	}
+}
+
+type memoryResult struct {
+	id int64
+}
+
+func (r memoryResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r memoryResult) RowsAffected() (int64, error) { return 1, nil }
+
+func (r *memoryRepository) subscription(subscriptionID int) *Subscription {
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == subscriptionID {
+			return &r.subscriptions[i]
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) GetSubscriptions(ctx context.Context, application string) (*[]Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var subscriptions []Subscription
+	for _, subscription := range r.subscriptions {
+		if subscription.Application == application && subscription.Status == "A" {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return &subscriptions, nil
+}
+
+func (r *memoryRepository) GetSubscription(ctx context.Context, subscriptionID int, application string) (*Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscription := r.subscription(subscriptionID)
+	if subscription == nil || subscription.Application != application {
+		return nil, nil
+	}
+	return subscription, nil
+}
+
+func (r *memoryRepository) CreateSubscription(ctx context.Context, subscription *Subscription) (sql.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscription.ID = len(r.subscriptions) + 1
+	r.subscriptions = append(r.subscriptions, *subscription)
+	return memoryResult{id: int64(subscription.ID)}, nil
+}
+
+func (r *memoryRepository) DeleteSubscription(ctx context.Context, subscriptionID int, subscription *Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscription(subscriptionID).Status = subscription.Status
+	return nil
+}
+
+func (r *memoryRepository) CreateDeliveries(ctx context.Context, deliveries *[]Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range *deliveries {
+		delivery.ID = len(r.deliveries) + 1
+		r.deliveries[delivery.ID] = &delivery
+	}
+	return nil
+}
+
+func (r *memoryRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, claimUntil time.Time, limit int) (*[]Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deliveries []Delivery
+	for id := 1; id <= len(r.deliveries) && len(deliveries) < limit; id++ {
+		delivery := r.deliveries[id]
+		if delivery.DeliveryStatus != DELIVERY_STATUS_PENDING || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		delivery.NextAttemptAt = claimUntil
+
+		subscription := r.subscription(delivery.SubscriptionID)
+		if subscription.Status != "A" {
+			continue
+		}
+		claimed := *delivery
+		claimed.URL = subscription.URL
+		claimed.Secret = subscription.Secret
+		deliveries = append(deliveries, claimed)
+	}
+	return &deliveries, nil
+}
+
+func (r *memoryRepository) GetDeliveries(ctx context.Context, subscriptionID int) (*[]DeliveryOut, error) {
+	return nil, errors.New("not used")
+}
+
+func (r *memoryRepository) GetDelivery(ctx context.Context, deliveryID int, application string) (*DeliveryOut, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery := r.deliveries[deliveryID]
+	if delivery == nil || r.subscription(delivery.SubscriptionID).Application != application {
+		return nil, nil
+	}
+	return &DeliveryOut{
+		ID:               delivery.ID,
+		SubscriptionID:   delivery.SubscriptionID,
+		EventID:          delivery.EventID,
+		EventType:        delivery.EventType,
+		DeliveryStatus:   delivery.DeliveryStatus,
+		Attempts:         delivery.Attempts,
+		NextAttemptAt:    delivery.NextAttemptAt,
+		LastResponseCode: delivery.LastResponseCode,
+		LastError:        delivery.LastError,
+		CreatedAt:        delivery.CreatedAt,
+		UpdatedAt:        delivery.UpdatedAt,
+	}, nil
+}
+
+func (r *memoryRepository) GetDeliveryLogs(ctx context.Context, deliveryID int) (*[]DeliveryLogOut, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	logs := r.logs[deliveryID]
+	return &logs, nil
+}
+
+func (r *memoryRepository) UpdateDelivery(ctx context.Context, delivery *Delivery, deliveryLog *DeliveryLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *delivery
+	stored.URL, stored.Secret = "", ""
+	r.deliveries[delivery.ID] = &stored
+	r.logs[delivery.ID] = append(r.logs[delivery.ID], DeliveryLogOut{
+		Attempt:      deliveryLog.Attempt,
+		ResponseCode: deliveryLog.ResponseCode,
+		ErrorMessage: deliveryLog.ErrorMessage,
+		DurationMs:   deliveryLog.DurationMs,
+		CreatedAt:    deliveryLog.CreatedAt,
+	})
+	return nil
+}
+
+func (r *memoryRepository) RedeliverDelivery(ctx context.Context, deliveryID int, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deliveries[deliveryID].DeliveryStatus = DELIVERY_STATUS_PENDING
+	r.deliveries[deliveryID].NextAttemptAt = now
+	return nil
+}
+
+// Makes every pending delivery due again, as if its backoff had passed.
+func (r *memoryRepository) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		delivery.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+func (r *memoryRepository) delivery(deliveryID int) Delivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return *r.deliveries[deliveryID]
+}
+
+// Subscriber that records the requests it gets and answers with the given status.
+type subscriber struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (s *subscriber) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r)
+	s.bodies = append(s.bodies, string(body))
+	w.WriteHeader(s.status)
+}
+
+func (s *subscriber) answer(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = status
+}
+
+func (s *subscriber) hits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.requests)
+}
+
+// Starts a subscriber and a service with one delivery pending for it.
+// The test server listens on loopback, so the service uses a client without the address guard.
+func setupDelivery(t *testing.T, status int) (*webhookService, *memoryRepository, *subscriber) {
+	target := &subscriber{status: status}
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	repository := newMemoryRepository(Subscription{
+		ID:          1,
+		Application: "shop",
+		URL:         server.URL + "/hooks",
+		EventFilter: "OrderCreated,OrderCancelled",
+		Secret:      "0123456789abcdef",
+		Status:      "A",
+	})
+	service := &webhookService{
+		webhookRepository: repository,
+		client:            server.Client(),
+		maxAttempts:       3,
+		retryBase:         time.Minute,
+	}
+
+	err := service.Publish(context.Background(), &outbox.Event{
+		ID:            7,
+		AggregateType: "shipping_order",
+		AggregateID:   42,
+		Application:   "shop",
+		EventType:     "OrderCreated",
+		Payload:       `{"trackingCode":"AB12CD34"}`,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if len(repository.deliveries) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(repository.deliveries))
+	}
+
+	return service, repository, target
+}
+
+func TestDeliverySignsTheBody(t *testing.T) {
+	service, repository, target := setupDelivery(t, http.StatusOK)
+
+	service.dispatchDueDeliveries(context.Background())
+
+	if target.hits() != 1 {
+		t.Fatalf("expected 1 request, got %d", target.hits())
+	}
+	request, body := target.requests[0], target.bodies[0]
+
+	mac := hmac.New(sha256.New, []byte("0123456789abcdef"))
+	mac.Write([]byte(request.Header.Get("X-Webhook-Timestamp") + "." + body))
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if signature := request.Header.Get("X-Webhook-Signature"); signature != expected {
+		t.Errorf("signature %q, expected %q", signature, expected)
+	}
+	if eventType := request.Header.Get("X-Webhook-Event"); eventType != "OrderCreated" {
+		t.Errorf("event header %q, expected OrderCreated", eventType)
+	}
+	if request.Header.Get("X-Webhook-Delivery") != "1" {
+		t.Errorf("delivery header %q, expected 1", request.Header.Get("X-Webhook-Delivery"))
+	}
+
+	delivery := repository.delivery(1)
+	if delivery.DeliveryStatus != DELIVERY_STATUS_DELIVERED || delivery.Attempts != 1 || delivery.LastResponseCode != http.StatusOK {
+		t.Errorf("delivery %+v, expected delivered on the first attempt", delivery)
+	}
+}
+
+func TestSignDependsOnSecretAndTimestamp(t *testing.T) {
+	signature := Sign("secret", "1700000000", "{}")
+	if signature == Sign("other", "1700000000", "{}") {
+		t.Error("signatures with different secrets must differ")
+	}
+	if signature == Sign("secret", "1700000001", "{}") {
+		t.Error("signatures with different timestamps must differ")
+	}
+	if len(signature) != 64 {
+		t.Errorf("signature %q must be a hex encoded sha256", signature)
+	}
+}
+
+func TestFailedDeliveryBacksOff(t *testing.T) {
+	service, repository, target := setupDelivery(t, http.StatusInternalServerError)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		repository.makeDue()
+		service.dispatchDueDeliveries(context.Background())
+
+		delivery := repository.delivery(1)
+		if delivery.DeliveryStatus != DELIVERY_STATUS_PENDING || delivery.Attempts != attempt {
+			t.Fatalf("attempt %d: delivery %+v, expected pending", attempt, delivery)
+		}
+		expected := time.Minute * time.Duration(1<<(attempt-1))
+		if backoff := delivery.NextAttemptAt.Sub(delivery.UpdatedAt); backoff != expected {
+			t.Errorf("attempt %d: backoff %v, expected %v", attempt, backoff, expected)
+		}
+		if delivery.LastResponseCode != http.StatusInternalServerError || delivery.LastError == "" {
+			t.Errorf("attempt %d: delivery %+v, expected the failure to be recorded", attempt, delivery)
+		}
+	}
+
+	// A delivery waiting for its backoff is not sent.
+	service.dispatchDueDeliveries(context.Background())
+	if target.hits() != 2 {
+		t.Errorf("expected 2 requests, got %d", target.hits())
+	}
+
+	logs, _ := repository.GetDeliveryLogs(context.Background(), 1)
+	if len(*logs) != 2 || (*logs)[1].Attempt != 2 || (*logs)[1].ResponseCode != http.StatusInternalServerError {
+		t.Errorf("logs %+v, expected one per attempt", *logs)
+	}
+}
+
+func TestDeliveryIsDeadAfterMaxAttempts(t *testing.T) {
+	service, repository, target := setupDelivery(t, http.StatusBadGateway)
+
+	for attempt := 1; attempt <= 4; attempt++ {
+		repository.makeDue()
+		service.dispatchDueDeliveries(context.Background())
+	}
+
+	delivery := repository.delivery(1)
+	if delivery.DeliveryStatus != DELIVERY_STATUS_DEAD || delivery.Attempts != 3 {
+		t.Errorf("delivery %+v, expected dead after 3 attempts", delivery)
+	}
+	if target.hits() != 3 {
+		t.Errorf("expected 3 requests, got %d", target.hits())
+	}
+}
+
+func TestRedeliverSendsDeadDelivery(t *testing.T) {
+	service, repository, target := setupDelivery(t, http.StatusServiceUnavailable)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		repository.makeDue()
+		service.dispatchDueDeliveries(context.Background())
+	}
+	if delivery := repository.delivery(1); delivery.DeliveryStatus != DELIVERY_STATUS_DEAD {
+		t.Fatalf("delivery %+v, expected dead", delivery)
+	}
+
+	// Other applications can not redeliver it.
+	redelivered, err := service.RedeliverDelivery(context.Background(), 1, "other")
+	if err != nil || redelivered != nil {
+		t.Fatalf("redeliver of another application: %+v, %v", redelivered, err)
+	}
+
+	target.answer(http.StatusNoContent)
+	redelivered, err = service.RedeliverDelivery(context.Background(), 1, "shop")
+	if err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+	if redelivered.DeliveryStatus != DELIVERY_STATUS_PENDING || len(*redelivered.Logs) != 3 {
+		t.Errorf("redelivered %+v, expected pending with its previous logs", redelivered)
+	}
+
+	service.dispatchDueDeliveries(context.Background())
+
+	delivery := repository.delivery(1)
+	if delivery.DeliveryStatus != DELIVERY_STATUS_DELIVERED || delivery.Attempts != 4 || delivery.LastResponseCode != http.StatusNoContent {
+		t.Errorf("delivery %+v, expected delivered on the fourth attempt", delivery)
+	}
+	if target.hits() != 4 {
+		t.Errorf("expected 4 requests, got %d", target.hits())
+	}
+}
+
+func TestCreateSubscriptionRejectsPrivateTargets(t *testing.T) {
+	service := &webhookService{webhookRepository: newMemoryRepository()}
+
+	for _, url := range []string{
+		"http://127.0.0.1/hooks",
+		"http://localhost:8080/hooks",
+		"http://10.1.2.3/hooks",
+		"http://172.16.0.1/hooks",
+		"http://192.168.1.20/hooks",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hooks",
+		"http://[fd00::1]/hooks",
+		"http://0.0.0.0/hooks",
+	} {
+		_, err := service.CreateSubscription(context.Background(), &SubscriptionInsert{
+			URL:         url,
+			Events:      []string{"OrderCreated"},
+			Secret:      "0123456789abcdef",
+			Application: "shop",
+			CreatedUser: "tester",
+		})
+		if !errors.Is(err, ErrForbiddenTarget) {
+			t.Errorf("%v: error %v, expected %v", url, err, ErrForbiddenTarget)
+		}
+	}
+
+	subscription, err := service.CreateSubscription(context.Background(), &SubscriptionInsert{
+		URL:         "https://93.184.216.34/hooks",
+		Events:      []string{"OrderCreated"},
+		Secret:      "0123456789abcdef",
+		Application: "shop",
+		CreatedUser: "tester",
+	})
+	if err != nil || subscription.ID != 1 {
+		t.Errorf("public target: %+v, %v", subscription, err)
+	}
+}
+
+func TestClientRefusesPrivateAddresses(t *testing.T) {
+	target := &subscriber{status: http.StatusOK}
+	server := httptest.NewServer(target)
+	defer server.Close()
+
+	_, err := newWebhookClient(time.Second).Get(server.URL)
+	if !errors.Is(err, ErrForbiddenTarget) {
+		t.Errorf("error %v, expected %v", err, ErrForbiddenTarget)
+	}
+	if target.hits() != 0 {
+		t.Errorf("expected no request to reach the server, got %d", target.hits())
+	}
+}
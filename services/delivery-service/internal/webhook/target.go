@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// Returned when a webhook url points to an address of the private network, loopback or link-local.
+var ErrForbiddenTarget = errors.New("the webhook url must point to a public address")
+
+// Checks whether an address can be reached by the webhooks.
+func publicAddress(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// Resolves the host of a webhook url and checks every address it points to.
+func checkTarget(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("the webhook url must use http or https")
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, target.Hostname())
+	if err != nil {
+		return fmt.Errorf("the host of the webhook url could not be resolved: %v", err)
+	}
+	for _, address := range addresses {
+		if !publicAddress(address.IP) {
+			return ErrForbiddenTarget
+		}
+	}
+
+	return nil
+}
+
+// Client that only connects to public addresses.
+// The address is checked when dialing, so hosts that resolve elsewhere later and redirects are covered too.
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !publicAddress(ip) {
+				return ErrForbiddenTarget
+			}
+			return nil
+		},
+	}
+
+	// Proxies are not used, the dialed address must be the one of the subscriber.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
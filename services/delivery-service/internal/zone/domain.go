@@ -0,0 +1,39 @@
+package zone
+
+import (
+	"context"
+)
+
+// Zone struct to describe a service zone, its polygons are rings of [lng, lat] points.
+type Zone struct {
+	ID       string
+	Name     string
+	Country  string
+	Polygons [][][][2]float64
+}
+
+type ZoneOut struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+}
+
+// ZoneLookupQuery struct to describe the search of the zone of a point.
+type ZoneLookupQuery struct {
+	Lat     *float64 `query:"lat" validate:"required,gte=-90,lte=90"`
+	Lng     *float64 `query:"lng" validate:"required,gte=-180,lte=180"`
+	Country string   `query:"country" validate:"omitempty,lte=200"`
+}
+
+// Our repository will implement these methods.
+type ZoneRepository interface {
+	GetZones(ctx context.Context) (*[]ZoneOut, error)
+	FindZone(ctx context.Context, country string, lat float64, lng float64) (*ZoneOut, error)
+	IsConfigured() bool
+}
+
+// Our use-case or service will implement these methods.
+type ZoneService interface {
+	GetZones(ctx context.Context) (*[]ZoneOut, error)
+	LookupZone(ctx context.Context, zoneLookupQuery *ZoneLookupQuery) (*ZoneOut, error)
+}
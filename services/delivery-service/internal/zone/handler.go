@@ -0,0 +1,110 @@
+package zone
+
+import (
+	"context"
+	"delivery-service/internal/middleware"
+	"delivery-service/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Represents our handler with our use-case / service.
+type ZoneHandler struct {
+	zoneService ZoneService
+}
+
+// Creates a new handler.
+func NewZoneHandler(zoneRoute fiber.Router, zs ZoneService) {
+	// Create a handler based on our created service / use-case.
+	handler := &ZoneHandler{
+		zoneService: zs,
+	}
+
+	// We will restrict this route with our JWT middleware.
+	zoneRoute.Use(middleware.JWTProtected(), middleware.ExtractTokenMetadata)
+
+	// Declare routing endpoints for general routes.
+	zoneRoute.Get("", handler.getZones)
+	zoneRoute.Get("/lookup", handler.lookupZone)
+}
+
+// Gets all zones.
+func (h *ZoneHandler) getZones(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Get all zones.
+	zones, err := h.zoneService.GetZones(customContext)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Zones obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      zones,
+	})
+}
+
+// Gets the zone that contains a point.
+func (h *ZoneHandler) lookupZone(c *fiber.Ctx) error {
+	// Create cancellable context.
+	customContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize variables.
+	zoneLookupQuery := &ZoneLookupQuery{}
+
+	// Parse query string.
+	if err := c.QueryParser(zoneLookupQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Create a new validator for a Zone model.
+	validate := utils.NewValidator()
+
+	// Validate search fields.
+	if err := validate.Struct(zoneLookupQuery); err != nil {
+		// Return, if some fields are not valid.
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":    "fail",
+			"message":   utils.ValidatorErrors(err),
+			"http_code": fiber.StatusBadRequest,
+		})
+	}
+
+	// Get the zone of the point.
+	zone, err := h.zoneService.LookupZone(customContext, zoneLookupQuery)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   err.Error(),
+			"http_code": fiber.StatusInternalServerError,
+		})
+	}
+	if zone == nil {
+		return c.Status(fiber.StatusNotFound).JSON(&fiber.Map{
+			"status":    "fail",
+			"message":   "The point is outside of the service zones!",
+			"http_code": fiber.StatusNotFound,
+		})
+	}
+
+	// Return results.
+	return c.Status(fiber.StatusOK).JSON(&fiber.Map{
+		"status":    "success",
+		"message":   "Zone obtained successfully!",
+		"http_code": fiber.StatusOK,
+		"data":      zone,
+	})
+}
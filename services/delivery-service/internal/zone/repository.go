@@ -0,0 +1,156 @@
+package zone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Represents that we will read the zones from a GeoJSON file.
+type geoJSONRepository struct {
+	zones []*Zone
+}
+
+// Create a new repository with the zones of a GeoJSON FeatureCollection.
+// Every feature needs an 'id' and a 'country' property, an empty path configures no zones.
+func NewZoneRepository(path string) (ZoneRepository, error) {
+	if path == "" {
+		return &geoJSONRepository{}, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := parseZones(content)
+	if err != nil {
+		return nil, fmt.Errorf("service zones file '%v' is not valid: %v", path, err)
+	}
+
+	return &geoJSONRepository{zones: zones}, nil
+}
+
+// GeoJSON structures of the zones file.
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Properties struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Country string `json:"country"`
+	} `json:"properties"`
+	Geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+// Reads the zones of a FeatureCollection, only 'Polygon' and 'MultiPolygon' geometries are allowed.
+func parseZones(content []byte) ([]*Zone, error) {
+	collection := &featureCollection{}
+	if err := json.Unmarshal(content, collection); err != nil {
+		return nil, err
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("the root object must be a FeatureCollection")
+	}
+
+	zones := make([]*Zone, 0, len(collection.Features))
+	for i, f := range collection.Features {
+		if f.Properties.ID == "" || f.Properties.Country == "" {
+			return nil, fmt.Errorf("feature %d needs the 'id' and 'country' properties", i)
+		}
+
+		zone := &Zone{
+			ID:      f.Properties.ID,
+			Name:    f.Properties.Name,
+			Country: f.Properties.Country,
+		}
+
+		switch f.Geometry.Type {
+		case "Polygon":
+			var polygon [][][2]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygon); err != nil {
+				return nil, fmt.Errorf("feature '%v' has invalid coordinates: %v", zone.ID, err)
+			}
+			zone.Polygons = [][][][2]float64{polygon}
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &zone.Polygons); err != nil {
+				return nil, fmt.Errorf("feature '%v' has invalid coordinates: %v", zone.ID, err)
+			}
+		default:
+			return nil, fmt.Errorf("feature '%v' has an unsupported geometry '%v'", zone.ID, f.Geometry.Type)
+		}
+
+		zones = append(zones, zone)
+	}
+
+	return zones, nil
+}
+
+// Tells if any zone was loaded.
+func (r *geoJSONRepository) IsConfigured() bool {
+	return len(r.zones) > 0
+}
+
+// Gets all zones.
+func (r *geoJSONRepository) GetZones(ctx context.Context) (*[]ZoneOut, error) {
+	zones := make([]ZoneOut, 0, len(r.zones))
+	for _, zone := range r.zones {
+		zones = append(zones, ZoneOut{ID: zone.ID, Name: zone.Name, Country: zone.Country})
+	}
+
+	return &zones, nil
+}
+
+// Gets the first zone of the country that contains the point, nil if there is none.
+// An empty country searches every zone.
+func (r *geoJSONRepository) FindZone(ctx context.Context, country string, lat float64, lng float64) (*ZoneOut, error) {
+	for _, zone := range r.zones {
+		if country != "" && !strings.EqualFold(zone.Country, country) {
+			continue
+		}
+
+		for _, polygon := range zone.Polygons {
+			if polygonContains(polygon, lat, lng) {
+				return &ZoneOut{ID: zone.ID, Name: zone.Name, Country: zone.Country}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// Tells if the point is inside the outer ring of the polygon and outside its holes.
+func polygonContains(polygon [][][2]float64, lat float64, lng float64) bool {
+	if len(polygon) == 0 || !ringContains(polygon[0], lat, lng) {
+		return false
+	}
+	for _, hole := range polygon[1:] {
+		if ringContains(hole, lat, lng) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Casts a ray from the point and counts the edges of the ring it crosses.
+func ringContains(ring [][2]float64, lat float64, lng float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		lngI, latI := ring[i][0], ring[i][1]
+		lngJ, latJ := ring[j][0], ring[j][1]
+		if (latI > lat) != (latJ > lat) && lng < (lngJ-lngI)*(lat-latI)/(latJ-latI)+lngI {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
@@ -0,0 +1,27 @@
+package zone
+
+import (
+	"context"
+)
+
+// Implementation of the repository in this service.
+type zoneService struct {
+	zoneRepository ZoneRepository
+}
+
+// Create a new 'service' or 'use-case' for 'Zone' entity.
+func NewZoneService(r ZoneRepository) ZoneService {
+	return &zoneService{
+		zoneRepository: r,
+	}
+}
+
+// Implementation of 'GetZones'.
+func (s *zoneService) GetZones(ctx context.Context) (*[]ZoneOut, error) {
+	return s.zoneRepository.GetZones(ctx)
+}
+
+// Implementation of 'LookupZone'.
+func (s *zoneService) LookupZone(ctx context.Context, zoneLookupQuery *ZoneLookupQuery) (*ZoneOut, error) {
+	return s.zoneRepository.FindZone(ctx, zoneLookupQuery.Country, *zoneLookupQuery.Lat, *zoneLookupQuery.Lng)
+}
@@ -20,47 +20,212 @@ CREATE TABLE product
     id            INT NOT NULL AUTO_INCREMENT,
     name         VARCHAR(100) NOT NULL ,
     short_description VARCHAR(100) NOT NULL,
+    unit_value    DECIMAL(10,2) NOT NULL DEFAULT 0,
+    application   VARCHAR(100) NOT NULL,
     created_user  VARCHAR(100) NOT NULL,
     created_at    DATETIME    NOT NULL,
     updated_user  VARCHAR(100) NOT NULL,
     updated_at    DATETIME    NOT NULL,
     status   VARCHAR(1)   NOT NULL,
-    PRIMARY KEY (id)
+    PRIMARY KEY (id),
+    INDEX idx_product_application (application, status)
 )ENGINE=InnoDB CHARACTER SET utf8;
 
+CREATE TABLE parties
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    fingerprint     CHAR(64)     NOT NULL,
+    document_id     VARCHAR(200) NOT NULL,
+    full_name       VARCHAR(200) NOT NULL,
+    phone           VARCHAR(200) NOT NULL,
+    email           VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_parties_fingerprint (fingerprint),
+    INDEX idx_parties_document (document_id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE addresses
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    fingerprint     CHAR(64)     NOT NULL,
+    lat             DECIMAL(9,6) NOT NULL,
+    lng             DECIMAL(9,6) NOT NULL,
+    street          VARCHAR(200) NOT NULL,
+    number          VARCHAR(20)  NOT NULL DEFAULT '',
+    district        VARCHAR(100) NOT NULL DEFAULT '',
+    city            VARCHAR(100) NOT NULL DEFAULT '',
+    region          VARCHAR(100) NOT NULL DEFAULT '',
+    country_code    VARCHAR(2)   NOT NULL,
+    postal_code     VARCHAR(20)  NOT NULL,
+    reference       VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_addresses_fingerprint (fingerprint)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE station
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    code            VARCHAR(20)  NOT NULL,
+    name            VARCHAR(100) NOT NULL,
+    street          VARCHAR(200) NOT NULL,
+    number          VARCHAR(20)  NOT NULL DEFAULT '',
+    district        VARCHAR(100) NOT NULL DEFAULT '',
+    city            VARCHAR(100) NOT NULL,
+    region          VARCHAR(100) NOT NULL DEFAULT '',
+    country_code    VARCHAR(2)   NOT NULL,
+    postal_code     VARCHAR(20)  NOT NULL DEFAULT '',
+    lat             DECIMAL(9,6) NOT NULL,
+    lng             DECIMAL(9,6) NOT NULL,
+    openingHours    TEXT         NOT NULL,
+    capacity        INT          NOT NULL,
+    created_user    VARCHAR(100) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(100) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_station_code (code)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
 CREATE TABLE shipping_order
 (
     id            INT NOT NULL AUTO_INCREMENT,
-    idSender        VARCHAR(200) NOT NULL ,
-    fullNameSender  VARCHAR(200) NOT NULL ,
-    phoneSender     VARCHAR(200) NOT NULL,
-    emailSender     VARCHAR(200) NOT NULL,
-    idRecipient         VARCHAR(200) NOT NULL ,
-    fullNameRecipient   VARCHAR(200) NOT NULL ,
-    phoneRecipient      VARCHAR(200) NOT NULL ,
-    emailRecipient      VARCHAR(200) NOT NULL ,
-    latOrigin       VARCHAR(50) NOT NULL ,
-    lngOrigin       VARCHAR(50) NOT NULL ,
-    addressOrigin   VARCHAR(200) NOT NULL ,
-    countryOrigin   VARCHAR(200) NOT NULL ,
-    zipcodeOrigin   VARCHAR(200) NOT NULL ,
-    referenceOrigin VARCHAR(200) NOT NULL ,
-    latDestination       VARCHAR(50) NOT NULL ,
-    lngDestination       VARCHAR(50) NOT NULL ,
-    addressDestination   VARCHAR(200) NOT NULL ,
-    countryDestination   VARCHAR(200) NOT NULL ,
-    zipcodeDestination   VARCHAR(200) NOT NULL ,
-    referenceDestination VARCHAR(200) NOT NULL ,
+    senderPartyId        INT NOT NULL,
+    recipientPartyId     INT NOT NULL,
+    originAddressId      INT NOT NULL,
+    destinationAddressId INT NOT NULL,
     packageSize     VARCHAR(1) NOT NULL ,
     quantityProduct INT NOT NULL,
     weightProduct   INT NOT NULL,
+    originZoneId      VARCHAR(50) NOT NULL DEFAULT '',
+    destinationZoneId VARCHAR(50) NOT NULL DEFAULT '',
+    serviceLevel    VARCHAR(20) NOT NULL DEFAULT 'standard',
+    price           DECIMAL(10,2) NOT NULL DEFAULT 0,
+    declaredValue   DECIMAL(12,2) NOT NULL DEFAULT 0,
+    currency        VARCHAR(3) NOT NULL DEFAULT 'USD',
+    insured         BOOLEAN NOT NULL DEFAULT FALSE,
+    insurancePremium DECIMAL(10,2) NOT NULL DEFAULT 0,
+    cashOnDelivery  DECIMAL(12,2) NOT NULL DEFAULT 0,
+    cashCollected   DECIMAL(12,2) NULL,
+    cashCollectedAt DATETIME NULL,
+    deliveryAttempts INT NOT NULL DEFAULT 0,
+    pickupFrom   DATETIME NULL,
+    pickupTo     DATETIME NULL,
+    trackingCode VARCHAR(20) NOT NULL,
+    idempotencyKey VARCHAR(100) NULL,
     orderStatus  VARCHAR(200) NOT NULL,
+    courierId    INT NULL,
+    estimatedDeliveryFrom DATETIME NULL,
+    estimatedDeliveryTo   DATETIME NULL,
+    optOutSender    BOOLEAN NOT NULL DEFAULT FALSE,
+    optOutRecipient BOOLEAN NOT NULL DEFAULT FALSE,
+    language        VARCHAR(2) NOT NULL DEFAULT 'es',
+    application  VARCHAR(100) NOT NULL,
     created_user  VARCHAR(200) NOT NULL,
     created_at    DATETIME    NOT NULL,
     updated_user  VARCHAR(200) NOT NULL,
     updated_at    DATETIME    NOT NULL,
     status   VARCHAR(1)   NOT NULL,
-    PRIMARY KEY (id)
+    originPoint      POINT NOT NULL,
+    destinationPoint POINT NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_shipping_order_tracking_code (trackingCode),
+    UNIQUE INDEX idx_shipping_order_idempotency (application, idempotencyKey),
+    SPATIAL INDEX idx_shipping_order_origin_point (originPoint),
+    SPATIAL INDEX idx_shipping_order_destination_point (destinationPoint),
+    INDEX idx_shipping_order_courier (courierId, orderStatus),
+    INDEX idx_shipping_order_zones (originZoneId, destinationZoneId),
+    INDEX idx_shipping_order_cash (courierId, cashCollectedAt),
+    INDEX idx_shipping_order_pickup (orderStatus, pickupFrom),
+    FOREIGN KEY (senderPartyId) REFERENCES parties(id),
+    FOREIGN KEY (recipientPartyId) REFERENCES parties(id),
+    FOREIGN KEY (originAddressId) REFERENCES addresses(id),
+    FOREIGN KEY (destinationAddressId) REFERENCES addresses(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE shipping_order_parcels
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    shippingOrderId INT NOT NULL,
+    sequence        INT NOT NULL,
+    packageSize     VARCHAR(1) NOT NULL,
+    quantityProduct INT NOT NULL,
+    weightProduct   INT NOT NULL,
+    declaredValue   DECIMAL(12,2) NOT NULL DEFAULT 0,
+    parcelStatus    VARCHAR(200) NOT NULL,
+    stationId       INT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_shipping_order_parcels_sequence (shippingOrderId, sequence),
+    INDEX idx_shipping_order_parcels_station (stationId, parcelStatus),
+    FOREIGN KEY (shippingOrderId) REFERENCES shipping_order(id),
+    FOREIGN KEY (stationId) REFERENCES station(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE shipping_order_attempts
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    shippingOrderId INT NOT NULL,
+    attempt         INT NOT NULL,
+    reason          VARCHAR(50) NOT NULL,
+    notes           VARCHAR(500) NOT NULL DEFAULT '',
+    courierId       INT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_shipping_order_attempts_attempt (shippingOrderId, attempt),
+    FOREIGN KEY (shippingOrderId) REFERENCES shipping_order(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE shipping_order_pod
+(
+    shippingOrderId  INT NOT NULL,
+    receiverName     VARCHAR(200) NOT NULL,
+    receiverDocument VARCHAR(200) NOT NULL,
+    lat              DECIMAL(9,6) NOT NULL,
+    lng              DECIMAL(9,6) NOT NULL,
+    signatureKey     VARCHAR(255) NOT NULL DEFAULT '',
+    photoKey         VARCHAR(255) NOT NULL DEFAULT '',
+    created_user     VARCHAR(200) NOT NULL,
+    created_at       DATETIME    NOT NULL,
+    PRIMARY KEY (shippingOrderId),
+    FOREIGN KEY (shippingOrderId) REFERENCES shipping_order(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE shipping_order_preferences
+(
+    shippingOrderId    INT NOT NULL,
+    deliveryWindowFrom DATETIME NULL,
+    deliveryWindowTo   DATETIME NULL,
+    instructions       VARCHAR(500) NOT NULL DEFAULT '',
+    neighbourName      VARCHAR(200) NULL,
+    neighbourDocument  VARCHAR(50) NULL,
+    neighbourAddress   VARCHAR(200) NULL,
+    pickupPointName    VARCHAR(200) NULL,
+    redirectedFromAddressId INT NULL,
+    redirectedFromZoneId    VARCHAR(50) NULL,
+    updated_at         DATETIME    NOT NULL,
+    PRIMARY KEY (shippingOrderId),
+    FOREIGN KEY (shippingOrderId) REFERENCES shipping_order(id),
+    FOREIGN KEY (redirectedFromAddressId) REFERENCES addresses(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE shipping_order_items
+(
+    id          INT NOT NULL AUTO_INCREMENT,
+    parcelId    INT NOT NULL,
+    productId   INT NOT NULL,
+    name        VARCHAR(100) NOT NULL,
+    quantity    INT NOT NULL,
+    unitValue   DECIMAL(10,2) NOT NULL,
+    created_at  DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (parcelId) REFERENCES shipping_order_parcels(id),
+    FOREIGN KEY (productId) REFERENCES product(id)
 ) ENGINE=InnoDB CHARACTER SET utf8;
 
 CREATE TABLE package_size
@@ -81,4 +246,202 @@ CREATE TABLE package_size
 INSERT INTO package_size(id, name, nemo, limitvalue, created_user, created_at, updated_user,updated_at, status) VALUES
 (1, '0 hasta 5kg', 'S', 5, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A"),
 (2, 'hasta 15kg', 'M', 15, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A"),
-(3, 'hasta 25kg', 'L', 25, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A");
\ No newline at end of file
+(3, 'hasta 25kg', 'L', 25, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A");
+
+CREATE TABLE service_level
+(
+    id                INT NOT NULL AUTO_INCREMENT,
+    name              VARCHAR(100) NOT NULL,
+    nemo              VARCHAR(20) NOT NULL,
+    cutoff_time       VARCHAR(5) NOT NULL DEFAULT '',
+    max_distance_km   DECIMAL(10,2) NOT NULL DEFAULT 0,
+    allowed_sizes     VARCHAR(20) NOT NULL,
+    price_multiplier  DECIMAL(5,2) NOT NULL DEFAULT 1,
+    handling_minutes  INT NOT NULL DEFAULT 0,
+    priority          INT NOT NULL DEFAULT 0,
+    created_user      VARCHAR(100) NULL,
+    created_at        DATETIME    NULL,
+    updated_user      VARCHAR(100) NULL,
+    updated_at        DATETIME    NULL,
+    status            VARCHAR(1)   NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_service_level_nemo (nemo)
+)ENGINE=InnoDB CHARACTER SET utf8;
+
+INSERT INTO service_level(id, name, nemo, cutoff_time, max_distance_km, allowed_sizes, price_multiplier, handling_minutes, priority, created_user, created_at, updated_user, updated_at, status) VALUES
+(1, 'Estandar', 'standard', '', 0, 'S,M,L', 1.00, 1440, 0, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A"),
+(2, 'Express', 'express', '18:00', 100, 'S,M,L', 1.50, 240, 1, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A"),
+(3, 'Mismo dia', 'same_day', '12:00', 30, 'S,M', 2.00, 60, 2, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A");
+
+CREATE TABLE job
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    job_type        VARCHAR(50) NOT NULL,
+    job_status      VARCHAR(20) NOT NULL,
+    total_items     INT NOT NULL,
+    processed_items INT NOT NULL,
+    failed_items    INT NOT NULL,
+    user_id         INT NOT NULL,
+    application     VARCHAR(100) NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_job_status (job_status, status)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE job_item
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    job_id          INT NOT NULL,
+    item_index      INT NOT NULL,
+    payload         MEDIUMTEXT NOT NULL,
+    item_status     VARCHAR(20) NOT NULL,
+    result          MEDIUMTEXT NOT NULL,
+    error_message   TEXT NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_job_item_job (job_id, item_status),
+    FOREIGN KEY (job_id) REFERENCES job(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE outbox_event
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    aggregate_type  VARCHAR(100) NOT NULL,
+    aggregate_id    INT NOT NULL,
+    application     VARCHAR(100) NOT NULL,
+    event_type      VARCHAR(100) NOT NULL,
+    payload         TEXT NOT NULL,
+    event_status    VARCHAR(20) NOT NULL,
+    attempts        INT NOT NULL,
+    last_error      TEXT NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    published_at    DATETIME    NULL,
+    PRIMARY KEY (id),
+    INDEX idx_outbox_event_status (event_status, id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE webhook_subscription
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    application     VARCHAR(100) NOT NULL,
+    url             VARCHAR(500) NOT NULL,
+    event_filter    VARCHAR(500) NOT NULL,
+    secret          VARCHAR(200) NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_webhook_subscription_application (application, status)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE webhook_delivery
+(
+    id                  INT NOT NULL AUTO_INCREMENT,
+    subscription_id     INT NOT NULL,
+    event_id            INT NOT NULL,
+    event_type          VARCHAR(100) NOT NULL,
+    payload             TEXT NOT NULL,
+    delivery_status     VARCHAR(20) NOT NULL,
+    attempts            INT NOT NULL,
+    next_attempt_at     DATETIME    NOT NULL,
+    last_response_code  INT NOT NULL,
+    last_error          TEXT NOT NULL,
+    created_at          DATETIME    NOT NULL,
+    updated_at          DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_webhook_delivery_event (subscription_id, event_id),
+    INDEX idx_webhook_delivery_due (delivery_status, next_attempt_at),
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscription(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE webhook_delivery_log
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    delivery_id     INT NOT NULL,
+    attempt         INT NOT NULL,
+    response_code   INT NOT NULL,
+    error_message   TEXT NOT NULL,
+    duration_ms     INT NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_webhook_delivery_log_delivery (delivery_id),
+    FOREIGN KEY (delivery_id) REFERENCES webhook_delivery(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE courier
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    user_id         INT NOT NULL,
+    full_name       VARCHAR(200) NOT NULL,
+    phone           VARCHAR(20) NOT NULL,
+    vehicle_type    VARCHAR(20) NOT NULL,
+    capacity_kg     INT NOT NULL,
+    active_zone     VARCHAR(100) NOT NULL,
+    courier_status  VARCHAR(20) NOT NULL,
+    last_lat        DECIMAL(10,7) NULL,
+    last_lng        DECIMAL(10,7) NULL,
+    last_location_at DATETIME   NULL,
+    application     VARCHAR(100) NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_courier_user (user_id, status),
+    INDEX idx_courier_status (courier_status, status),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE contact
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    user_id         INT NOT NULL,
+    alias           VARCHAR(100) NOT NULL,
+    document_id     VARCHAR(200) NOT NULL,
+    full_name       VARCHAR(200) NOT NULL,
+    phone           VARCHAR(200) NOT NULL,
+    email           VARCHAR(200) NOT NULL,
+    application     VARCHAR(100) NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_contact_user (user_id, status),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE contact_address
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    user_id         INT NOT NULL,
+    alias           VARCHAR(100) NOT NULL,
+    street          VARCHAR(200) NOT NULL,
+    number          VARCHAR(20) NOT NULL,
+    district        VARCHAR(100) NOT NULL,
+    city            VARCHAR(100) NOT NULL,
+    region          VARCHAR(100) NOT NULL,
+    country_code    VARCHAR(2)   NOT NULL,
+    postal_code     VARCHAR(20)  NOT NULL,
+    reference       VARCHAR(200) NOT NULL,
+    lat             DECIMAL(9,6) NULL,
+    lng             DECIMAL(9,6) NULL,
+    application     VARCHAR(100) NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_contact_address_user (user_id, status),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
@@ -0,0 +1,11 @@
+-- Adds the currency, insurance and cash on delivery of the orders to an existing database.
+USE deliverydb;
+
+ALTER TABLE shipping_order
+    ADD COLUMN currency         VARCHAR(3) NOT NULL DEFAULT 'USD' AFTER declaredValue,
+    ADD COLUMN insured          BOOLEAN NOT NULL DEFAULT FALSE AFTER currency,
+    ADD COLUMN insurancePremium DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER insured,
+    ADD COLUMN cashOnDelivery   DECIMAL(12,2) NOT NULL DEFAULT 0 AFTER insurancePremium,
+    ADD COLUMN cashCollected    DECIMAL(12,2) NULL AFTER cashOnDelivery,
+    ADD COLUMN cashCollectedAt  DATETIME NULL AFTER cashCollected,
+    ADD INDEX idx_shipping_order_cash (courierId, cashCollectedAt);
@@ -0,0 +1,48 @@
+-- Adds the saved contacts and addresses of the users to an existing database.
+USE deliverydb;
+
+CREATE TABLE contact
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    user_id         INT NOT NULL,
+    alias           VARCHAR(100) NOT NULL,
+    document_id     VARCHAR(200) NOT NULL,
+    full_name       VARCHAR(200) NOT NULL,
+    phone           VARCHAR(200) NOT NULL,
+    email           VARCHAR(200) NOT NULL,
+    application     VARCHAR(100) NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_contact_user (user_id, status),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE contact_address
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    user_id         INT NOT NULL,
+    alias           VARCHAR(100) NOT NULL,
+    street          VARCHAR(200) NOT NULL,
+    number          VARCHAR(20) NOT NULL,
+    district        VARCHAR(100) NOT NULL,
+    city            VARCHAR(100) NOT NULL,
+    region          VARCHAR(100) NOT NULL,
+    country_code    VARCHAR(2)   NOT NULL,
+    postal_code     VARCHAR(20)  NOT NULL,
+    reference       VARCHAR(200) NOT NULL,
+    lat             DECIMAL(9,6) NULL,
+    lng             DECIMAL(9,6) NULL,
+    application     VARCHAR(100) NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_contact_address_user (user_id, status),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
@@ -0,0 +1,8 @@
+-- Adds the last known position of the couriers to an existing database.
+USE deliverydb;
+
+ALTER TABLE courier
+    ADD COLUMN last_lat         DECIMAL(10,7) NULL AFTER courier_status,
+    ADD COLUMN last_lng         DECIMAL(10,7) NULL AFTER last_lat,
+    ADD COLUMN last_location_at DATETIME      NULL AFTER last_lng,
+    ADD INDEX idx_courier_status (courier_status, status);
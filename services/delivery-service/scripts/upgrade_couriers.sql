@@ -0,0 +1,30 @@
+-- Adds the couriers and the courier assigned to each order to an existing database.
+-- Jobs created before have no user, let the running ones finish before upgrading.
+USE deliverydb;
+
+CREATE TABLE courier
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    user_id         INT NOT NULL,
+    full_name       VARCHAR(200) NOT NULL,
+    phone           VARCHAR(20) NOT NULL,
+    vehicle_type    VARCHAR(20) NOT NULL,
+    capacity_kg     INT NOT NULL,
+    active_zone     VARCHAR(100) NOT NULL,
+    courier_status  VARCHAR(20) NOT NULL,
+    application     VARCHAR(100) NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_courier_user (user_id, status),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+ALTER TABLE shipping_order
+    ADD COLUMN courierId INT NULL AFTER orderStatus,
+    ADD INDEX idx_shipping_order_courier (courierId, orderStatus);
+
+ALTER TABLE job ADD COLUMN user_id INT NOT NULL DEFAULT 0 AFTER failed_items;
@@ -0,0 +1,19 @@
+-- Adds the failed delivery attempts of the orders to an existing database.
+USE deliverydb;
+
+ALTER TABLE shipping_order ADD COLUMN deliveryAttempts INT NOT NULL DEFAULT 0 AFTER cashCollectedAt;
+
+CREATE TABLE shipping_order_attempts
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    shippingOrderId INT NOT NULL,
+    attempt         INT NOT NULL,
+    reason          VARCHAR(50) NOT NULL,
+    notes           VARCHAR(500) NOT NULL DEFAULT '',
+    courierId       INT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_shipping_order_attempts_attempt (shippingOrderId, attempt),
+    FOREIGN KEY (shippingOrderId) REFERENCES shipping_order(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
@@ -0,0 +1,7 @@
+-- Adds the estimated delivery window of the orders to an existing database.
+-- Orders created before have no estimate.
+USE deliverydb;
+
+ALTER TABLE shipping_order
+    ADD COLUMN estimatedDeliveryFrom DATETIME NULL AFTER courierId,
+    ADD COLUMN estimatedDeliveryTo   DATETIME NULL AFTER estimatedDeliveryFrom;
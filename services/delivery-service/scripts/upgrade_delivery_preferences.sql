@@ -0,0 +1,27 @@
+-- Adds the tracking codes and the delivery preferences of the recipients to an existing database.
+USE deliverydb;
+
+-- Existing orders get a random tracking code before it is required.
+ALTER TABLE shipping_order ADD COLUMN trackingCode VARCHAR(20) NULL AFTER pickupTo;
+UPDATE shipping_order SET trackingCode = UPPER(SUBSTRING(SHA2(CONCAT(id, '|', RAND(), '|', NOW(6)), 256), 1, 12)) WHERE trackingCode IS NULL;
+ALTER TABLE shipping_order
+    MODIFY COLUMN trackingCode VARCHAR(20) NOT NULL,
+    ADD UNIQUE INDEX idx_shipping_order_tracking_code (trackingCode);
+
+CREATE TABLE shipping_order_preferences
+(
+    shippingOrderId    INT NOT NULL,
+    deliveryWindowFrom DATETIME NULL,
+    deliveryWindowTo   DATETIME NULL,
+    instructions       VARCHAR(500) NOT NULL DEFAULT '',
+    neighbourName      VARCHAR(200) NULL,
+    neighbourDocument  VARCHAR(50) NULL,
+    neighbourAddress   VARCHAR(200) NULL,
+    pickupPointName    VARCHAR(200) NULL,
+    redirectedFromAddressId INT NULL,
+    redirectedFromZoneId    VARCHAR(50) NULL,
+    updated_at         DATETIME    NOT NULL,
+    PRIMARY KEY (shippingOrderId),
+    FOREIGN KEY (shippingOrderId) REFERENCES shipping_order(id),
+    FOREIGN KEY (redirectedFromAddressId) REFERENCES addresses(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
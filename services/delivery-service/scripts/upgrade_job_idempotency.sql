@@ -0,0 +1,7 @@
+-- Adds the idempotency key of the orders created by jobs to an existing database.
+-- Needs the tracking codes and the application of the orders, run it after upgrade_delivery_preferences.sql.
+USE deliverydb;
+
+ALTER TABLE shipping_order
+    ADD COLUMN idempotencyKey VARCHAR(100) NULL AFTER trackingCode,
+    ADD UNIQUE INDEX idx_shipping_order_idempotency (application, idempotencyKey);
@@ -0,0 +1,35 @@
+-- Adds the background jobs of bulk operations to an existing database.
+USE deliverydb;
+
+CREATE TABLE job
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    job_type        VARCHAR(50) NOT NULL,
+    job_status      VARCHAR(20) NOT NULL,
+    total_items     INT NOT NULL,
+    processed_items INT NOT NULL,
+    failed_items    INT NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_job_status (job_status, status)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE job_item
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    job_id          INT NOT NULL,
+    item_index      INT NOT NULL,
+    payload         MEDIUMTEXT NOT NULL,
+    item_status     VARCHAR(20) NOT NULL,
+    result          MEDIUMTEXT NOT NULL,
+    error_message   TEXT NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_job_item_job (job_id, item_status),
+    FOREIGN KEY (job_id) REFERENCES job(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
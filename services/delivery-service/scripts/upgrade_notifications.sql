@@ -0,0 +1,8 @@
+-- Adds the notification preferences of the orders to an existing database.
+-- Orders created before notify both parties in Spanish.
+USE deliverydb;
+
+ALTER TABLE shipping_order
+    ADD COLUMN optOutSender    BOOLEAN NOT NULL DEFAULT FALSE AFTER orderStatus,
+    ADD COLUMN optOutRecipient BOOLEAN NOT NULL DEFAULT FALSE AFTER optOutSender,
+    ADD COLUMN language        VARCHAR(2) NOT NULL DEFAULT 'es' AFTER optOutRecipient;
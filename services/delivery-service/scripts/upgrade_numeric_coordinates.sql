@@ -0,0 +1,22 @@
+-- Moves the coordinates of an existing database from text to numbers.
+-- Orders with coordinates that are not numbers must be fixed before running it.
+USE deliverydb;
+
+ALTER TABLE shipping_order
+    MODIFY latOrigin       DECIMAL(9,6) NOT NULL,
+    MODIFY lngOrigin       DECIMAL(9,6) NOT NULL,
+    MODIFY latDestination  DECIMAL(9,6) NOT NULL,
+    MODIFY lngDestination  DECIMAL(9,6) NOT NULL,
+    ADD COLUMN originPoint      POINT NULL,
+    ADD COLUMN destinationPoint POINT NULL;
+
+-- Points are stored as (lng, lat).
+UPDATE shipping_order
+SET originPoint = POINT(lngOrigin, latOrigin),
+    destinationPoint = POINT(lngDestination, latDestination);
+
+ALTER TABLE shipping_order
+    MODIFY originPoint      POINT NOT NULL,
+    MODIFY destinationPoint POINT NOT NULL,
+    ADD SPATIAL INDEX idx_shipping_order_origin_point (originPoint),
+    ADD SPATIAL INDEX idx_shipping_order_destination_point (destinationPoint);
@@ -0,0 +1,18 @@
+-- Adds the outbox of the domain events to an existing database.
+USE deliverydb;
+
+CREATE TABLE outbox_event
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    aggregate_type  VARCHAR(100) NOT NULL,
+    aggregate_id    INT NOT NULL,
+    event_type      VARCHAR(100) NOT NULL,
+    payload         TEXT NOT NULL,
+    event_status    VARCHAR(20) NOT NULL,
+    attempts        INT NOT NULL,
+    last_error      TEXT NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    published_at    DATETIME    NULL,
+    PRIMARY KEY (id),
+    INDEX idx_outbox_event_status (event_status, id)
+) ENGINE=InnoDB CHARACTER SET utf8;
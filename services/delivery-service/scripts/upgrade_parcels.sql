@@ -0,0 +1,23 @@
+-- Adds the parcels of an existing database, every order becomes a single parcel with its package and status.
+USE deliverydb;
+
+CREATE TABLE shipping_order_parcels
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    shippingOrderId INT NOT NULL,
+    sequence        INT NOT NULL,
+    packageSize     VARCHAR(1) NOT NULL,
+    quantityProduct INT NOT NULL,
+    weightProduct   INT NOT NULL,
+    parcelStatus    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_shipping_order_parcels_sequence (shippingOrderId, sequence),
+    FOREIGN KEY (shippingOrderId) REFERENCES shipping_order(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+INSERT INTO shipping_order_parcels (shippingOrderId, sequence, packageSize, quantityProduct, weightProduct, parcelStatus, created_at, updated_user, updated_at)
+SELECT id, 1, packageSize, quantityProduct, weightProduct, orderStatus, created_at, updated_user, updated_at
+FROM shipping_order;
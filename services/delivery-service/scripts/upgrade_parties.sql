@@ -0,0 +1,111 @@
+-- Moves the sender, recipient, origin and destination of an existing database into 'parties' and 'addresses'.
+-- Equal rows are stored once, the fingerprints match the ones computed by the service.
+USE deliverydb;
+
+CREATE TABLE parties
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    fingerprint     CHAR(64)     NOT NULL,
+    document_id     VARCHAR(200) NOT NULL,
+    full_name       VARCHAR(200) NOT NULL,
+    phone           VARCHAR(200) NOT NULL,
+    email           VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_parties_fingerprint (fingerprint),
+    INDEX idx_parties_document (document_id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE addresses
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    fingerprint     CHAR(64)     NOT NULL,
+    lat             DECIMAL(9,6) NOT NULL,
+    lng             DECIMAL(9,6) NOT NULL,
+    street          VARCHAR(200) NOT NULL,
+    number          VARCHAR(20)  NOT NULL DEFAULT '',
+    district        VARCHAR(100) NOT NULL DEFAULT '',
+    city            VARCHAR(100) NOT NULL DEFAULT '',
+    region          VARCHAR(100) NOT NULL DEFAULT '',
+    country_code    VARCHAR(2)   NOT NULL,
+    postal_code     VARCHAR(20)  NOT NULL,
+    reference       VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_addresses_fingerprint (fingerprint)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+ALTER TABLE shipping_order
+    ADD COLUMN senderPartyId        INT NULL AFTER id,
+    ADD COLUMN recipientPartyId     INT NULL AFTER senderPartyId,
+    ADD COLUMN originAddressId      INT NULL AFTER recipientPartyId,
+    ADD COLUMN destinationAddressId INT NULL AFTER originAddressId;
+
+-- Back-fill the parties.
+INSERT IGNORE INTO parties (fingerprint, document_id, full_name, phone, email, created_at)
+SELECT SHA2(CONCAT_WS('|', idSender, fullNameSender, phoneSender, emailSender), 256), idSender, fullNameSender, phoneSender, emailSender, MIN(created_at)
+FROM shipping_order GROUP BY idSender, fullNameSender, phoneSender, emailSender;
+
+INSERT IGNORE INTO parties (fingerprint, document_id, full_name, phone, email, created_at)
+SELECT SHA2(CONCAT_WS('|', idRecipient, fullNameRecipient, phoneRecipient, emailRecipient), 256), idRecipient, fullNameRecipient, phoneRecipient, emailRecipient, MIN(created_at)
+FROM shipping_order GROUP BY idRecipient, fullNameRecipient, phoneRecipient, emailRecipient;
+
+UPDATE shipping_order so
+JOIN parties sp ON sp.fingerprint = SHA2(CONCAT_WS('|', so.idSender, so.fullNameSender, so.phoneSender, so.emailSender), 256)
+JOIN parties rp ON rp.fingerprint = SHA2(CONCAT_WS('|', so.idRecipient, so.fullNameRecipient, so.phoneRecipient, so.emailRecipient), 256)
+SET so.senderPartyId = sp.id, so.recipientPartyId = rp.id;
+
+-- Back-fill the addresses.
+INSERT IGNORE INTO addresses (fingerprint, lat, lng, street, number, district, city, region, country_code, postal_code, reference, created_at)
+SELECT SHA2(CONCAT_WS('|', latOrigin, lngOrigin, addressOrigin, numberOrigin, districtOrigin, cityOrigin, regionOrigin, countryOrigin, zipcodeOrigin, referenceOrigin), 256),
+    latOrigin, lngOrigin, addressOrigin, numberOrigin, districtOrigin, cityOrigin, regionOrigin, countryOrigin, zipcodeOrigin, referenceOrigin, MIN(created_at)
+FROM shipping_order GROUP BY latOrigin, lngOrigin, addressOrigin, numberOrigin, districtOrigin, cityOrigin, regionOrigin, countryOrigin, zipcodeOrigin, referenceOrigin;
+
+INSERT IGNORE INTO addresses (fingerprint, lat, lng, street, number, district, city, region, country_code, postal_code, reference, created_at)
+SELECT SHA2(CONCAT_WS('|', latDestination, lngDestination, addressDestination, numberDestination, districtDestination, cityDestination, regionDestination, countryDestination, zipcodeDestination, referenceDestination), 256),
+    latDestination, lngDestination, addressDestination, numberDestination, districtDestination, cityDestination, regionDestination, countryDestination, zipcodeDestination, referenceDestination, MIN(created_at)
+FROM shipping_order GROUP BY latDestination, lngDestination, addressDestination, numberDestination, districtDestination, cityDestination, regionDestination, countryDestination, zipcodeDestination, referenceDestination;
+
+UPDATE shipping_order so
+JOIN addresses oa ON oa.fingerprint = SHA2(CONCAT_WS('|', so.latOrigin, so.lngOrigin, so.addressOrigin, so.numberOrigin, so.districtOrigin, so.cityOrigin, so.regionOrigin, so.countryOrigin, so.zipcodeOrigin, so.referenceOrigin), 256)
+JOIN addresses da ON da.fingerprint = SHA2(CONCAT_WS('|', so.latDestination, so.lngDestination, so.addressDestination, so.numberDestination, so.districtDestination, so.cityDestination, so.regionDestination, so.countryDestination, so.zipcodeDestination, so.referenceDestination), 256)
+SET so.originAddressId = oa.id, so.destinationAddressId = da.id;
+
+-- The order keeps only the references, its POINTs stay for the spatial indexes.
+ALTER TABLE shipping_order
+    MODIFY senderPartyId        INT NOT NULL,
+    MODIFY recipientPartyId     INT NOT NULL,
+    MODIFY originAddressId      INT NOT NULL,
+    MODIFY destinationAddressId INT NOT NULL,
+    DROP COLUMN idSender,
+    DROP COLUMN fullNameSender,
+    DROP COLUMN phoneSender,
+    DROP COLUMN emailSender,
+    DROP COLUMN idRecipient,
+    DROP COLUMN fullNameRecipient,
+    DROP COLUMN phoneRecipient,
+    DROP COLUMN emailRecipient,
+    DROP COLUMN latOrigin,
+    DROP COLUMN lngOrigin,
+    DROP COLUMN addressOrigin,
+    DROP COLUMN numberOrigin,
+    DROP COLUMN districtOrigin,
+    DROP COLUMN cityOrigin,
+    DROP COLUMN regionOrigin,
+    DROP COLUMN countryOrigin,
+    DROP COLUMN zipcodeOrigin,
+    DROP COLUMN referenceOrigin,
+    DROP COLUMN latDestination,
+    DROP COLUMN lngDestination,
+    DROP COLUMN addressDestination,
+    DROP COLUMN numberDestination,
+    DROP COLUMN districtDestination,
+    DROP COLUMN cityDestination,
+    DROP COLUMN regionDestination,
+    DROP COLUMN countryDestination,
+    DROP COLUMN zipcodeDestination,
+    DROP COLUMN referenceDestination,
+    ADD FOREIGN KEY (senderPartyId) REFERENCES parties(id),
+    ADD FOREIGN KEY (recipientPartyId) REFERENCES parties(id),
+    ADD FOREIGN KEY (originAddressId) REFERENCES addresses(id),
+    ADD FOREIGN KEY (destinationAddressId) REFERENCES addresses(id);
@@ -0,0 +1,7 @@
+-- Adds the scheduled pickups of the orders to an existing database.
+USE deliverydb;
+
+ALTER TABLE shipping_order
+    ADD COLUMN pickupFrom DATETIME NULL AFTER deliveryAttempts,
+    ADD COLUMN pickupTo   DATETIME NULL AFTER pickupFrom,
+    ADD INDEX idx_shipping_order_pickup (orderStatus, pickupFrom);
@@ -0,0 +1,7 @@
+-- Adds the application that owns each product to an existing database.
+-- Products created before belong to no application until they are assigned one.
+USE deliverydb;
+
+ALTER TABLE product
+    ADD COLUMN application VARCHAR(100) NOT NULL DEFAULT '' AFTER unit_value,
+    ADD INDEX idx_product_application (application, status);
@@ -0,0 +1,20 @@
+-- Adds the unit value of the products and the line items of the parcels to an existing database.
+USE deliverydb;
+
+ALTER TABLE product ADD COLUMN unit_value DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER short_description;
+ALTER TABLE shipping_order ADD COLUMN declaredValue DECIMAL(12,2) NOT NULL DEFAULT 0 AFTER price;
+ALTER TABLE shipping_order_parcels ADD COLUMN declaredValue DECIMAL(12,2) NOT NULL DEFAULT 0 AFTER weightProduct;
+
+CREATE TABLE shipping_order_items
+(
+    id          INT NOT NULL AUTO_INCREMENT,
+    parcelId    INT NOT NULL,
+    productId   INT NOT NULL,
+    name        VARCHAR(100) NOT NULL,
+    quantity    INT NOT NULL,
+    unitValue   DECIMAL(10,2) NOT NULL,
+    created_at  DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (parcelId) REFERENCES shipping_order_parcels(id),
+    FOREIGN KEY (productId) REFERENCES product(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
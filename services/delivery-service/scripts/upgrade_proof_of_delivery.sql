@@ -0,0 +1,17 @@
+-- Adds the proof of delivery of the orders to an existing database.
+USE deliverydb;
+
+CREATE TABLE shipping_order_pod
+(
+    shippingOrderId  INT NOT NULL,
+    receiverName     VARCHAR(200) NOT NULL,
+    receiverDocument VARCHAR(200) NOT NULL,
+    lat              DECIMAL(9,6) NOT NULL,
+    lng              DECIMAL(9,6) NOT NULL,
+    signatureKey     VARCHAR(255) NOT NULL DEFAULT '',
+    photoKey         VARCHAR(255) NOT NULL DEFAULT '',
+    created_user     VARCHAR(200) NOT NULL,
+    created_at       DATETIME    NOT NULL,
+    PRIMARY KEY (shippingOrderId),
+    FOREIGN KEY (shippingOrderId) REFERENCES shipping_order(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
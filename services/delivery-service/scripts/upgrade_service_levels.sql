@@ -0,0 +1,32 @@
+-- Adds the service levels and the price of the orders to an existing database.
+-- Orders created before are standard and have no price.
+USE deliverydb;
+
+ALTER TABLE shipping_order
+    ADD COLUMN serviceLevel VARCHAR(20) NOT NULL DEFAULT 'standard' AFTER weightProduct,
+    ADD COLUMN price        DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER serviceLevel;
+
+CREATE TABLE service_level
+(
+    id                INT NOT NULL AUTO_INCREMENT,
+    name              VARCHAR(100) NOT NULL,
+    nemo              VARCHAR(20) NOT NULL,
+    cutoff_time       VARCHAR(5) NOT NULL DEFAULT '',
+    max_distance_km   DECIMAL(10,2) NOT NULL DEFAULT 0,
+    allowed_sizes     VARCHAR(20) NOT NULL,
+    price_multiplier  DECIMAL(5,2) NOT NULL DEFAULT 1,
+    handling_minutes  INT NOT NULL DEFAULT 0,
+    priority          INT NOT NULL DEFAULT 0,
+    created_user      VARCHAR(100) NULL,
+    created_at        DATETIME    NULL,
+    updated_user      VARCHAR(100) NULL,
+    updated_at        DATETIME    NULL,
+    status            VARCHAR(1)   NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_service_level_nemo (nemo)
+)ENGINE=InnoDB CHARACTER SET utf8;
+
+INSERT INTO service_level(id, name, nemo, cutoff_time, max_distance_km, allowed_sizes, price_multiplier, handling_minutes, priority, created_user, created_at, updated_user, updated_at, status) VALUES
+(1, 'Estandar', 'standard', '', 0, 'S,M,L', 1.00, 1440, 0, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A"),
+(2, 'Express', 'express', '18:00', 100, 'S,M,L', 1.50, 240, 1, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A"),
+(3, 'Mismo dia', 'same_day', '12:00', 30, 'S,M', 2.00, 60, 2, "luis.torres", UTC_TIMESTAMP(), "luis.torres", UTC_TIMESTAMP(), "A");
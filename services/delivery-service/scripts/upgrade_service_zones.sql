@@ -0,0 +1,8 @@
+-- Adds the service zones of the orders to an existing database.
+-- Orders created before have no zones.
+USE deliverydb;
+
+ALTER TABLE shipping_order
+    ADD COLUMN originZoneId      VARCHAR(50) NOT NULL DEFAULT '' AFTER weightProduct,
+    ADD COLUMN destinationZoneId VARCHAR(50) NOT NULL DEFAULT '' AFTER originZoneId,
+    ADD INDEX idx_shipping_order_zones (originZoneId, destinationZoneId);
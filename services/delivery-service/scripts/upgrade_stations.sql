@@ -0,0 +1,33 @@
+-- Adds the stations and the station holding each parcel to an existing database.
+-- Parcels already in 'en_estacion' have no station, they should be moved on or assigned one by hand.
+USE deliverydb;
+
+CREATE TABLE station
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    code            VARCHAR(20)  NOT NULL,
+    name            VARCHAR(100) NOT NULL,
+    street          VARCHAR(200) NOT NULL,
+    number          VARCHAR(20)  NOT NULL DEFAULT '',
+    district        VARCHAR(100) NOT NULL DEFAULT '',
+    city            VARCHAR(100) NOT NULL,
+    region          VARCHAR(100) NOT NULL DEFAULT '',
+    country_code    VARCHAR(2)   NOT NULL,
+    postal_code     VARCHAR(20)  NOT NULL DEFAULT '',
+    lat             DECIMAL(9,6) NOT NULL,
+    lng             DECIMAL(9,6) NOT NULL,
+    openingHours    TEXT         NOT NULL,
+    capacity        INT          NOT NULL,
+    created_user    VARCHAR(100) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(100) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_station_code (code)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+ALTER TABLE shipping_order_parcels
+    ADD COLUMN stationId INT NULL AFTER parcelStatus,
+    ADD INDEX idx_shipping_order_parcels_station (stationId, parcelStatus),
+    ADD FOREIGN KEY (stationId) REFERENCES station(id);
@@ -0,0 +1,25 @@
+-- Splits the addresses of an existing database into structured columns.
+-- The old address text is kept as the street, countries must already be ISO-3166 codes.
+USE deliverydb;
+
+ALTER TABLE shipping_order
+    ADD COLUMN numberOrigin         VARCHAR(20) NOT NULL DEFAULT '' AFTER addressOrigin,
+    ADD COLUMN districtOrigin       VARCHAR(100) NOT NULL DEFAULT '' AFTER numberOrigin,
+    ADD COLUMN cityOrigin           VARCHAR(100) NOT NULL DEFAULT '' AFTER districtOrigin,
+    ADD COLUMN regionOrigin         VARCHAR(100) NOT NULL DEFAULT '' AFTER cityOrigin,
+    ADD COLUMN numberDestination    VARCHAR(20) NOT NULL DEFAULT '' AFTER addressDestination,
+    ADD COLUMN districtDestination  VARCHAR(100) NOT NULL DEFAULT '' AFTER numberDestination,
+    ADD COLUMN cityDestination      VARCHAR(100) NOT NULL DEFAULT '' AFTER districtDestination,
+    ADD COLUMN regionDestination    VARCHAR(100) NOT NULL DEFAULT '' AFTER cityDestination;
+
+UPDATE shipping_order
+SET countryOrigin = UPPER(TRIM(countryOrigin)),
+    countryDestination = UPPER(TRIM(countryDestination)),
+    zipcodeOrigin = UPPER(REPLACE(TRIM(zipcodeOrigin), ' ', '')),
+    zipcodeDestination = UPPER(REPLACE(TRIM(zipcodeDestination), ' ', ''));
+
+ALTER TABLE shipping_order
+    MODIFY countryOrigin       VARCHAR(2) NOT NULL,
+    MODIFY zipcodeOrigin       VARCHAR(20) NOT NULL,
+    MODIFY countryDestination  VARCHAR(2) NOT NULL,
+    MODIFY zipcodeDestination  VARCHAR(20) NOT NULL;
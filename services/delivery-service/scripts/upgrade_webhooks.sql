@@ -0,0 +1,69 @@
+-- Adds the webhooks and the application that owns each order, job and event to an existing database.
+-- Rows created before take the application of the user named as their creator, the rest must be assigned one by hand.
+USE deliverydb;
+
+ALTER TABLE shipping_order ADD COLUMN application VARCHAR(100) NOT NULL DEFAULT '' AFTER orderStatus;
+ALTER TABLE job ADD COLUMN application VARCHAR(100) NOT NULL DEFAULT '' AFTER failed_items;
+ALTER TABLE outbox_event ADD COLUMN application VARCHAR(100) NOT NULL DEFAULT '' AFTER aggregate_id;
+
+UPDATE shipping_order so
+JOIN users u ON u.name = so.created_user
+SET so.application = u.application;
+
+UPDATE job j
+JOIN users u ON u.name = j.created_user
+SET j.application = u.application;
+
+UPDATE outbox_event e
+JOIN shipping_order so ON so.id = e.aggregate_id and e.aggregate_type = 'shipping_order'
+SET e.application = so.application;
+
+CREATE TABLE webhook_subscription
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    application     VARCHAR(100) NOT NULL,
+    url             VARCHAR(500) NOT NULL,
+    event_filter    VARCHAR(500) NOT NULL,
+    secret          VARCHAR(200) NOT NULL,
+    created_user    VARCHAR(200) NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    updated_user    VARCHAR(200) NOT NULL,
+    updated_at      DATETIME    NOT NULL,
+    status          VARCHAR(1)   NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_webhook_subscription_application (application, status)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE webhook_delivery
+(
+    id                  INT NOT NULL AUTO_INCREMENT,
+    subscription_id     INT NOT NULL,
+    event_id            INT NOT NULL,
+    event_type          VARCHAR(100) NOT NULL,
+    payload             TEXT NOT NULL,
+    delivery_status     VARCHAR(20) NOT NULL,
+    attempts            INT NOT NULL,
+    next_attempt_at     DATETIME    NOT NULL,
+    last_response_code  INT NOT NULL,
+    last_error          TEXT NOT NULL,
+    created_at          DATETIME    NOT NULL,
+    updated_at          DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_webhook_delivery_event (subscription_id, event_id),
+    INDEX idx_webhook_delivery_due (delivery_status, next_attempt_at),
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscription(id)
+) ENGINE=InnoDB CHARACTER SET utf8;
+
+CREATE TABLE webhook_delivery_log
+(
+    id              INT NOT NULL AUTO_INCREMENT,
+    delivery_id     INT NOT NULL,
+    attempt         INT NOT NULL,
+    response_code   INT NOT NULL,
+    error_message   TEXT NOT NULL,
+    duration_ms     INT NOT NULL,
+    created_at      DATETIME    NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_webhook_delivery_log_delivery (delivery_id),
+    FOREIGN KEY (delivery_id) REFERENCES webhook_delivery(id)
+) ENGINE=InnoDB CHARACTER SET utf8;